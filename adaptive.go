@@ -0,0 +1,273 @@
+package yfs
+
+import (
+	"math"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/evanphx/yfs/format"
+)
+
+// storingTransform is implemented by compression transforms that can
+// store a block without attempting to compress it.
+type storingTransform interface {
+	Store(block []byte) ([]byte, []byte, error)
+}
+
+// CompressionStats reports how much work compression did and what it
+// bought. All sizes are in bytes of uncompressed block data.
+type CompressionStats struct {
+	// Attempted is how much data was passed to the codec.
+	Attempted int64
+	// Saved is how much smaller the attempted data got.
+	Saved int64
+	// Skipped is how much data was stored without trying to compress
+	// it because it looked incompressible.
+	Skipped int64
+}
+
+type compressionStats struct {
+	attempted, saved, skipped int64
+}
+
+// CompressionStats returns the compression counters for everything this
+// FS has compressed since it was opened: blocks, including the ones of
+// TOC nodes, and also the sections of heads and the block and pack
+// indexes.
+func (f *FS) CompressionStats() CompressionStats {
+	return CompressionStats{
+		Attempted: atomic.LoadInt64(&f.compStats.attempted),
+		Saved:     atomic.LoadInt64(&f.compStats.saved),
+		Skipped:   atomic.LoadInt64(&f.compStats.skipped),
+	}
+}
+
+// compressedExtensions are file types that are almost always already
+// compressed.
+var compressedExtensions = map[string]bool{
+	".7z": true, ".avi": true, ".br": true, ".bz2": true, ".docx": true,
+	".flac": true, ".gif": true, ".gz": true, ".heic": true, ".jar": true,
+	".jpeg": true, ".jpg": true, ".lz4": true, ".m4a": true, ".mkv": true,
+	".mov": true, ".mp3": true, ".mp4": true, ".ogg": true, ".png": true,
+	".rar": true, ".tgz": true, ".webm": true, ".webp": true, ".xlsx": true,
+	".xz": true, ".zip": true, ".zst": true,
+}
+
+const (
+	// entropyThreshold is the sampled entropy, in bits per byte, above
+	// which a block isn't worth compressing.
+	entropyThreshold = 7.5
+
+	entropySample = 4096
+	minEntropyLen = 256
+
+	// After probeBlocks blocks of a file saving less than
+	// minSavingsRatio, the rest of the file is stored as is.
+	probeBlocks     = 4
+	minSavingsRatio = 0.02
+
+	// A previous version that compressed to more than this ratio makes
+	// the new version start out skipped.
+	skipPreviousRatio = 0.98
+)
+
+// compressHint tracks how well the blocks of one file compress so that
+// compression can stop being attempted on data that won't benefit. The
+// blocks of a file are compressed concurrently with WithWriteWorkers, so
+// it's updated under mu.
+type compressHint struct {
+	mu sync.Mutex
+
+	skip  bool
+	probe bool
+
+	blocks          int
+	attempted, save int64
+}
+
+// newCompressHint starts a hint for path, using its extension and the
+// compression ratio of prev, the previous version of the file, if any.
+func (t *Txn) newCompressHint(path string, prev *format.Entry) *compressHint {
+	if !t.f.adaptive {
+		return nil
+	}
+
+	h := &compressHint{}
+
+	if compressedExtensions[strings.ToLower(filepath.Ext(path))] {
+		h.skip = true
+		return h
+	}
+
+	if prev == nil || prev.Blocks == nil {
+		return h
+	}
+
+	var size, comp int64
+
+	for i, blk := range prev.Blocks.Blocks {
+		if i == probeBlocks {
+			break
+		}
+
+		if info, ok := t.tocBlocks.FindBlock(blk.Id); ok {
+			size += info.ByteSize
+			comp += info.CompSize
+		}
+	}
+
+	// Still try the first block, in case the contents changed.
+	if size > 0 && float64(comp)/float64(size) > skipPreviousRatio {
+		h.skip = true
+		h.probe = true
+	}
+
+	return h
+}
+
+// try reports if compressing block is worth attempting.
+func (h *compressHint) try(block []byte) bool {
+	if h == nil {
+		return true
+	}
+
+	h.mu.Lock()
+
+	probe, skip := h.probe, h.skip
+	h.probe = false
+
+	h.mu.Unlock()
+
+	if probe {
+		return true
+	}
+
+	if skip {
+		return false
+	}
+
+	return sampleEntropy(block) < entropyThreshold
+}
+
+func (h *compressHint) record(in, out int) {
+	if h == nil {
+		return
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.blocks++
+	h.attempted += int64(in)
+
+	if out < in {
+		h.save += int64(in - out)
+	}
+
+	if h.blocks == 1 && h.skip && float64(out)/float64(in) < 1-minSavingsRatio {
+		// The probe compressed, give the file another chance.
+		h.skip = false
+	}
+
+	if h.blocks >= probeBlocks && float64(h.save)/float64(h.attempted) < minSavingsRatio {
+		h.skip = true
+	}
+}
+
+// sampleEntropy estimates the Shannon entropy of block in bits per byte
+// from a sample of bytes spread across it.
+func sampleEntropy(block []byte) float64 {
+	if len(block) < minEntropyLen {
+		return 0
+	}
+
+	var counts [256]int
+
+	step := 1
+	if len(block) > entropySample {
+		step = len(block) / entropySample
+	}
+
+	var n int
+
+	for i := 0; i < len(block); i += step {
+		counts[block[i]]++
+		n++
+	}
+
+	var e float64
+
+	for _, c := range counts {
+		if c == 0 {
+			continue
+		}
+
+		p := float64(c) / float64(n)
+		e -= p * math.Log2(p)
+	}
+
+	return e
+}
+
+// compress runs the write compression transform over block, or stores it
+// as is when hint says it's not worth trying.
+func (ba *blockAccess) compress(block []byte, hint *compressHint) ([]byte, error) {
+	comp := ba.write.compression
+
+	if st, ok := comp.(storingTransform); ok && !hint.try(block) {
+		out, _, err := st.Store(block)
+		if err != nil {
+			return nil, err
+		}
+
+		if ba.stats != nil {
+			atomic.AddInt64(&ba.stats.skipped, int64(len(block)))
+		}
+
+		return out, nil
+	}
+
+	out, _, err := comp.Transform(block)
+	if err != nil {
+		return nil, err
+	}
+
+	hint.record(len(block), len(out))
+
+	if ba.stats != nil {
+		atomic.AddInt64(&ba.stats.attempted, int64(len(block)))
+
+		if len(out) < len(block) {
+			atomic.AddInt64(&ba.stats.saved, int64(len(block)-len(out)))
+		}
+	}
+
+	return out, nil
+}
+
+func (l lz4Writer) Store(block []byte) ([]byte, []byte, error) {
+	out := getBlockBuf(len(block) + 2)
+	out[0] = 0
+	out[1] = 0
+	copy(out[2:], block)
+	return out[:len(block)+2], out, nil
+}
+
+func (c *codecWriter) Store(block []byte) ([]byte, []byte, error) {
+	out := getBlockBuf(len(block) + 1)
+	out[0] = codecNone
+	copy(out[1:], block)
+	return out[:len(block)+1], out, nil
+}
+
+// WithAdaptiveCompression skips compressing blocks that are unlikely to
+// shrink: files with extensions of compressed formats, files whose
+// previous version didn't compress, the rest of files whose first blocks
+// didn't compress, and blocks whose sampled entropy is high.
+func WithAdaptiveCompression() Option {
+	return Option(func(f *FS) {
+		f.adaptive = true
+	})
+}
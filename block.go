@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"encoding/hex"
 	"errors"
+	"io"
 	"io/ioutil"
 	"os"
 	"path/filepath"
@@ -22,8 +23,31 @@ type blockTransform interface {
 	Transform(src []byte) ([]byte, []byte, error)
 }
 
+// aeadTransform is implemented by encryption transforms that can bind
+// additional data, such as the block id, to the ciphertext.
+type aeadTransform interface {
+	TransformAD(src, ad []byte) ([]byte, []byte, error)
+}
+
+func transformAD(t blockTransform, block, ad []byte) ([]byte, []byte, error) {
+	if at, ok := t.(aeadTransform); ok && ad != nil {
+		return at.TransformAD(block, ad)
+	}
+
+	return t.Transform(block)
+}
+
 type blockAccess struct {
-	root string
+	root  string
+	store blockStore
+	stats *compressionStats
+	cache *blockCache
+
+	// authenticated binds block ids and head headers to their
+	// ciphertext. headKeys are the keys head MACs are checked against,
+	// the first one signs new heads.
+	authenticated bool
+	headKeys      [][]byte
 
 	write struct {
 		compression blockTransform
@@ -34,11 +58,28 @@ type blockAccess struct {
 		compression blockTransform
 		encryption  blockTransform
 	}
+
+	// legacy reads blocks and version 0 heads that an interrupted
+	// upgrade from version 0 hasn't rewritten yet.
+	legacy *blockAccess
 }
 
-func (ba *blockAccess) writeTransform(block []byte) ([]byte, error) {
+// blockAD is the associated data block bid is encrypted with.
+func (ba *blockAccess) blockAD(bid BlockId) []byte {
+	if !ba.authenticated {
+		return nil
+	}
+
+	return bid
+}
+
+func (ba *blockAccess) writeTransform(block, ad []byte) ([]byte, error) {
+	return ba.writeTransformHint(block, nil, ad)
+}
+
+func (ba *blockAccess) writeTransformHint(block []byte, hint *compressHint, ad []byte) ([]byte, error) {
 	if ba.write.compression != nil {
-		out, _, err := ba.write.compression.Transform(block)
+		out, err := ba.compress(block, hint)
 		if err != nil {
 			return nil, err
 		}
@@ -47,7 +88,7 @@ func (ba *blockAccess) writeTransform(block []byte) ([]byte, error) {
 	}
 
 	if ba.write.encryption != nil {
-		out, _, err := ba.write.encryption.Transform(block)
+		out, _, err := transformAD(ba.write.encryption, block, ad)
 		if err != nil {
 			return nil, err
 		}
@@ -58,40 +99,39 @@ func (ba *blockAccess) writeTransform(block []byte) ([]byte, error) {
 	return block, nil
 }
 
-func (ba *blockAccess) writeBlock(bid BlockId, block []byte) (int64, error) {
-	id := bid.String()
-
-	dir := filepath.Join(ba.root, id[:6])
-	err := os.MkdirAll(dir, 0755)
-	if err != nil {
-		return 0, err
+func (ba *blockAccess) blockStore() blockStore {
+	if ba.store != nil {
+		return ba.store
 	}
 
-	path := filepath.Join(dir, id)
+	return fileStore{root: ba.root}
+}
 
-	block, err = ba.writeTransform(block)
+func (ba *blockAccess) writeBlock(bid BlockId, block []byte, hint *compressHint) (int64, error) {
+	block, err := ba.writeTransformHint(block, hint, ba.blockAD(bid))
 	if err != nil {
 		return 0, err
 	}
 
-	of, err := os.Create(path)
+	err = ba.blockStore().Put(bid, block)
 	if err != nil {
 		return 0, err
 	}
 
-	defer of.Close()
+	return int64(len(block)), nil
+}
 
-	_, err = of.Write(block)
-	if err != nil {
-		return 0, err
-	}
+func (ba *blockAccess) removeBlock(bid BlockId) error {
+	return ba.blockStore().Remove(bid)
+}
 
-	return int64(len(block)), nil
+func (ba *blockAccess) flush() error {
+	return ba.blockStore().Flush()
 }
 
-func (ba *blockAccess) readTransform(block []byte) ([]byte, error) {
+func (ba *blockAccess) readTransform(block, ad []byte) ([]byte, error) {
 	if ba.read.encryption != nil {
-		out, _, err := ba.read.encryption.Transform(block)
+		out, _, err := transformAD(ba.read.encryption, block, ad)
 		if err != nil {
 			return nil, err
 		}
@@ -114,22 +154,39 @@ func (ba *blockAccess) readTransform(block []byte) ([]byte, error) {
 var ErrCorruptBlock = errors.New("corrupt block detected")
 
 func (ba *blockAccess) readBlock(bid BlockId) ([]byte, error) {
-	hid := bid.String()
+	if ba.cache != nil {
+		if data, ok := ba.cache.get(bid); ok {
+			return data, nil
+		}
+	}
 
-	path := filepath.Join(ba.root, hid[:6], hid)
-	f, err := os.Open(path)
+	rawBlock, err := ba.blockStore().Get(bid)
 	if err != nil {
 		return nil, err
 	}
 
-	defer f.Close()
+	data, err := ba.decodeBlock(bid, rawBlock)
+	if err != nil && ba.legacy != nil {
+		if ldata, lerr := ba.legacy.decodeBlock(bid, rawBlock); lerr == nil {
+			data, err = ldata, nil
+		}
+	}
 
-	rawBlock, err := ioutil.ReadAll(f)
 	if err != nil {
-		return nil, err
+		return data, err
+	}
+
+	if ba.cache != nil {
+		ba.cache.add(bid, data)
 	}
 
-	data, err := ba.readTransform(rawBlock)
+	return data, nil
+}
+
+// decodeBlock reverses the transforms of rawBlock, the stored bytes of
+// block bid, and checks the result against bid.
+func (ba *blockAccess) decodeBlock(bid BlockId, rawBlock []byte) ([]byte, error) {
+	data, err := ba.readTransform(rawBlock, ba.blockAD(bid))
 	if err != nil {
 		return nil, err
 	}
@@ -157,3 +214,84 @@ func (ba *blockAccess) readSet(set *format.BlockSet) ([]byte, error) {
 
 	return buf.Bytes(), nil
 }
+
+// blockStore persists the transformed bytes of blocks by id.
+type blockStore interface {
+	Put(bid BlockId, data []byte) error
+	Get(bid BlockId) ([]byte, error)
+	Remove(bid BlockId) error
+	Flush() error
+}
+
+// fileStore stores every block as its own file, fanned out into
+// directories by the first 6 hex digits of the id.
+type fileStore struct {
+	root string
+}
+
+func (fs fileStore) path(bid BlockId) (string, string) {
+	id := bid.String()
+	dir := filepath.Join(fs.root, id[:6])
+	return dir, filepath.Join(dir, id)
+}
+
+func (fs fileStore) Put(bid BlockId, data []byte) error {
+	dir, path := fs.path(bid)
+
+	err := os.MkdirAll(dir, 0755)
+	if err != nil {
+		return err
+	}
+
+	// Blocks are rewritten in place when they're re-encrypted, so don't
+	// leave a partial block behind if that's interrupted.
+	tmp := path + ".tmp"
+
+	err = ioutil.WriteFile(tmp, data, 0644)
+	if err != nil {
+		return err
+	}
+
+	return os.Rename(tmp, path)
+}
+
+func (fs fileStore) Get(bid BlockId) ([]byte, error) {
+	_, path := fs.path(bid)
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	defer f.Close()
+
+	return ioutil.ReadAll(f)
+}
+
+func (fs fileStore) Remove(bid BlockId) error {
+	dir, path := fs.path(bid)
+
+	err := os.Remove(path)
+	if err != nil {
+		return err
+	}
+
+	// Drop the fan directory once it's empty.
+	f, err := os.Open(dir)
+	if err != nil {
+		return nil
+	}
+
+	_, err = f.Readdir(1)
+	f.Close()
+
+	if err == io.EOF {
+		return os.Remove(dir)
+	}
+
+	return nil
+}
+
+func (fs fileStore) Flush() error {
+	return nil
+}
@@ -0,0 +1,121 @@
+package yfs
+
+import (
+	"container/list"
+	"sync"
+	"sync/atomic"
+)
+
+// WithBlockCache keeps up to size bytes of decoded blocks in memory, so
+// blocks read again skip the store, decryption and decompression. The
+// least recently used blocks are dropped first. Snapshots opened with
+// ReadSnapshot share the cache of the FS they're opened from.
+func WithBlockCache(size int64) Option {
+	return Option(func(f *FS) {
+		f.blockCacheSize = size
+	})
+}
+
+// BlockCacheStats reports how well the block cache is doing.
+type BlockCacheStats struct {
+	// Hits and Misses count reads of blocks that were and weren't
+	// cached.
+	Hits   int64
+	Misses int64
+	// Blocks is how many blocks are cached, using Bytes bytes.
+	Blocks int
+	Bytes  int64
+}
+
+// BlockCacheStats returns the counters of the block cache. They're all
+// zero if there isn't one.
+func (f *FS) BlockCacheStats() BlockCacheStats {
+	if f.blockCache == nil {
+		return BlockCacheStats{}
+	}
+
+	return f.blockCache.stats()
+}
+
+type cachedBlock struct {
+	id   string
+	data []byte
+}
+
+// blockCache is an LRU cache of decoded blocks. Blocks are cached by id,
+// which is the hash of their contents, so they stay valid whatever
+// happens to how they're stored.
+type blockCache struct {
+	hits, misses int64
+
+	mu     sync.Mutex
+	max    int64
+	size   int64
+	lru    *list.List
+	blocks map[string]*list.Element
+}
+
+func newBlockCache(max int64) *blockCache {
+	return &blockCache{
+		max:    max,
+		lru:    list.New(),
+		blocks: make(map[string]*list.Element),
+	}
+}
+
+// get returns the cached contents of block bid, which must not be
+// modified.
+func (c *blockCache) get(bid BlockId) ([]byte, bool) {
+	c.mu.Lock()
+	e, ok := c.blocks[string(bid)]
+	if ok {
+		c.lru.MoveToFront(e)
+	}
+	c.mu.Unlock()
+
+	if !ok {
+		atomic.AddInt64(&c.misses, 1)
+		return nil, false
+	}
+
+	atomic.AddInt64(&c.hits, 1)
+
+	return e.Value.(*cachedBlock).data, true
+}
+
+// add caches data as the contents of block bid. data must not be
+// modified afterwards.
+func (c *blockCache) add(bid BlockId, data []byte) {
+	if int64(len(data)) > c.max {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, ok := c.blocks[string(bid)]; ok {
+		return
+	}
+
+	c.blocks[string(bid)] = c.lru.PushFront(&cachedBlock{id: string(bid), data: data})
+	c.size += int64(len(data))
+
+	for c.size > c.max {
+		old := c.lru.Remove(c.lru.Back()).(*cachedBlock)
+
+		delete(c.blocks, old.id)
+		c.size -= int64(len(old.data))
+	}
+}
+
+func (c *blockCache) stats() BlockCacheStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return BlockCacheStats{
+		Hits:   atomic.LoadInt64(&c.hits),
+		Misses: atomic.LoadInt64(&c.misses),
+		Blocks: c.lru.Len(),
+		Bytes:  c.size,
+	}
+}
@@ -0,0 +1,47 @@
+package yfs
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vektra/neko"
+)
+
+func TestBlockCache(t *testing.T) {
+	n := neko.Modern(t)
+
+	n.It("drops the least recently used blocks past its size", func(t *testing.T) {
+		c := newBlockCache(25)
+
+		a, b, d := BlockId("a"), BlockId("b"), BlockId("d")
+
+		c.add(a, bytes.Repeat([]byte{1}, 10))
+		c.add(b, bytes.Repeat([]byte{2}, 10))
+
+		_, ok := c.get(a)
+		assert.True(t, ok)
+
+		c.add(d, bytes.Repeat([]byte{3}, 10))
+
+		_, ok = c.get(b)
+		assert.False(t, ok)
+
+		data, ok := c.get(a)
+		assert.True(t, ok)
+		assert.Equal(t, bytes.Repeat([]byte{1}, 10), data)
+
+		c.add(BlockId("big"), make([]byte, 30))
+
+		_, ok = c.get(BlockId("big"))
+		assert.False(t, ok)
+
+		stats := c.stats()
+		assert.Equal(t, 2, stats.Blocks)
+		assert.Equal(t, int64(20), stats.Bytes)
+		assert.Equal(t, int64(2), stats.Hits)
+		assert.Equal(t, int64(2), stats.Misses)
+	})
+
+	n.Meow()
+}
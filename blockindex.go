@@ -0,0 +1,152 @@
+package yfs
+
+import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/evanphx/yfs/format"
+	"github.com/golang/crypto/blake2b"
+)
+
+// Index files, blocks.idx and packs/index, start with blockIndexMagic
+// and a version byte, then the blake2b sum of the rest of the file, then
+// the index run through the same transforms as heads. Files without the
+// magic are plaintext indexes written before they were encrypted.
+var blockIndexMagic = []byte("YFSI")
+
+const (
+	blockIndexVersion = 1
+	blockIndexHeader  = 4 + 1 + 32
+)
+
+var (
+	ErrCorruptBlockIndex = errors.New("block index is corrupt")
+	ErrUnknownBlockIndex = errors.New("block index uses an unknown version")
+)
+
+func (f *FS) blockIndexPath() string {
+	return filepath.Join(f.root, "blocks.idx")
+}
+
+// blockIndexAD is the associated data the block index is encrypted with.
+func (ba *blockAccess) blockIndexAD() []byte {
+	if !ba.authenticated {
+		return nil
+	}
+
+	return []byte("yfs blocks.idx")
+}
+
+// packIndexAD is the associated data the pack index is encrypted with.
+func (ba *blockAccess) packIndexAD() []byte {
+	if !ba.authenticated {
+		return nil
+	}
+
+	return []byte("yfs packs/index")
+}
+
+// sealIndex returns the contents of an index file holding index, run
+// through the transforms of ba with ad.
+func (ba *blockAccess) sealIndex(index, ad []byte) ([]byte, error) {
+	payload, err := ba.writeTransform(index, ad)
+	if err != nil {
+		return nil, err
+	}
+
+	sum := blake2b.Sum256(payload)
+
+	data := make([]byte, 0, blockIndexHeader+len(payload))
+	data = append(data, blockIndexMagic...)
+	data = append(data, blockIndexVersion)
+	data = append(data, sum[:]...)
+	data = append(data, payload...)
+
+	return data, nil
+}
+
+// openIndex returns the index in data, the contents of an index file.
+// Plaintext indexes are returned as they are, with sealed false.
+func (ba *blockAccess) openIndex(data, ad []byte) ([]byte, bool, error) {
+	if !bytes.HasPrefix(data, blockIndexMagic) {
+		return data, false, nil
+	}
+
+	if len(data) < blockIndexHeader {
+		return nil, true, ErrCorruptBlockIndex
+	}
+
+	if data[len(blockIndexMagic)] != blockIndexVersion {
+		return nil, true, ErrUnknownBlockIndex
+	}
+
+	payload := data[blockIndexHeader:]
+
+	sum := blake2b.Sum256(payload)
+
+	if !bytes.Equal(sum[:], data[len(blockIndexMagic)+1:blockIndexHeader]) {
+		return nil, true, ErrCorruptBlockIndex
+	}
+
+	index, err := ba.readTransform(payload, ad)
+	if err != nil {
+		return nil, true, err
+	}
+
+	return index, true, nil
+}
+
+// writeBlockIndex writes bt to blocks.idx with the transforms of ba.
+func (f *FS) writeBlockIndex(ba *blockAccess, bt *format.BlockTOC) error {
+	buf := getBlockBuf(bt.Size())
+	defer putBlockBuf(buf)
+
+	n, err := bt.MarshalTo(buf)
+	if err != nil {
+		return err
+	}
+
+	data, err := ba.sealIndex(buf[:n], ba.blockIndexAD())
+	if err != nil {
+		return err
+	}
+
+	tmp := f.blockIndexPath() + ".tmp"
+
+	err = ioutil.WriteFile(tmp, data, 0644)
+	if err != nil {
+		return err
+	}
+
+	return os.Rename(tmp, f.blockIndexPath())
+}
+
+// readBlockIndex reads blocks.idx into bt. A missing index leaves bt
+// untouched.
+func (f *FS) readBlockIndex(bt *format.BlockTOC) error {
+	data, err := ioutil.ReadFile(f.blockIndexPath())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+
+		return err
+	}
+
+	index, sealed, err := f.blockAccess.openIndex(data, f.blockAccess.blockIndexAD())
+	if err != nil {
+		return err
+	}
+
+	// Authenticated repositories never wrote a plaintext index, so one
+	// there has been swapped in, unless an interrupted upgrade hasn't
+	// rewritten it yet.
+	if !sealed && f.blockAccess.authenticated && f.blockAccess.read.encryption != nil && f.blockAccess.legacy == nil {
+		return ErrCorruptBlockIndex
+	}
+
+	return bt.Unmarshal(index)
+}
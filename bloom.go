@@ -0,0 +1,146 @@
+package yfs
+
+import (
+	"encoding/binary"
+	"math"
+
+	"github.com/evanphx/yfs/format"
+	"github.com/golang/crypto/blake2b"
+)
+
+// DefaultBloomFalsePositive is the false positive rate the block bloom
+// filters are sized for unless WithBloomFalsePositiveRate is used.
+const DefaultBloomFalsePositive = 0.01
+
+const (
+	minBloomBits = 64
+	maxBloomK    = 32
+)
+
+// bloomFilter answers "is this block definitely not present" without
+// scanning a BlockTOC. Block ids are already uniformly distributed
+// hashes, so the probe positions are derived from the id bytes directly
+// using double hashing rather than hashing them again.
+//
+// The serialized form stored in BlockTOC.BloomFilter is a single byte
+// holding the number of probes followed by the bit array.
+//
+// A filter is sized for capacity ids. Past that its false positive rate
+// climbs, and full reports that it should be rebuilt.
+type bloomFilter struct {
+	k    uint8
+	bits []byte
+
+	n        int
+	capacity int
+}
+
+func newBloomFilter(n int, fp float64) *bloomFilter {
+	if fp <= 0 || fp >= 1 {
+		fp = DefaultBloomFalsePositive
+	}
+
+	if n < 1 {
+		n = 1
+	}
+
+	m := int(math.Ceil(-float64(n) * math.Log(fp) / (math.Ln2 * math.Ln2)))
+	if m < minBloomBits {
+		m = minBloomBits
+	}
+
+	k := int(math.Round(float64(m) / float64(n) * math.Ln2))
+	if k < 1 {
+		k = 1
+	} else if k > maxBloomK {
+		k = maxBloomK
+	}
+
+	return &bloomFilter{
+		k:        uint8(k),
+		bits:     make([]byte, (m+7)/8),
+		capacity: n,
+	}
+}
+
+// buildBloomFilter returns a filter of blocks, with room for as many
+// again before it's full.
+func buildBloomFilter(blocks []*format.BlockInfo, fp float64) *bloomFilter {
+	bf := newBloomFilter(2*len(blocks), fp)
+
+	for _, blk := range blocks {
+		bf.Add(blk.Id)
+	}
+
+	return bf
+}
+
+// loadBloomFilter decodes a filter previously produced by Bytes, of n
+// ids. It returns nil if data is empty or malformed, in which case the
+// caller should rebuild the filter from the block list.
+func loadBloomFilter(data []byte, n int) *bloomFilter {
+	if len(data) < 2 || data[0] == 0 || data[0] > maxBloomK {
+		return nil
+	}
+
+	bits := make([]byte, len(data)-1)
+	copy(bits, data[1:])
+
+	// The capacity isn't stored, but the number of probes was chosen
+	// as the best one for it.
+	capacity := int(float64(len(bits)*8) * math.Ln2 / float64(data[0]))
+
+	return &bloomFilter{k: data[0], bits: bits, n: n, capacity: capacity}
+}
+
+func (b *bloomFilter) Bytes() []byte {
+	out := make([]byte, len(b.bits)+1)
+	out[0] = b.k
+	copy(out[1:], b.bits)
+	return out
+}
+
+func (b *bloomFilter) hashes(id []byte) (uint64, uint64) {
+	if len(id) < 16 {
+		sum := blake2b.Sum256(id)
+		id = sum[:]
+	}
+
+	h1 := binary.LittleEndian.Uint64(id[0:8])
+	h2 := binary.LittleEndian.Uint64(id[8:16]) | 1
+
+	return h1, h2
+}
+
+func (b *bloomFilter) Add(id []byte) {
+	h1, h2 := b.hashes(id)
+	m := uint64(len(b.bits)) * 8
+
+	b.n++
+
+	for i := uint64(0); i < uint64(b.k); i++ {
+		pos := (h1 + i*h2) % m
+		b.bits[pos/8] |= 1 << (pos % 8)
+	}
+}
+
+// full reports whether more ids were added than the filter was sized
+// for.
+func (b *bloomFilter) full() bool {
+	return b.n > b.capacity
+}
+
+// MayContain returns false only if id was never added to the filter.
+func (b *bloomFilter) MayContain(id []byte) bool {
+	h1, h2 := b.hashes(id)
+	m := uint64(len(b.bits)) * 8
+
+	for i := uint64(0); i < uint64(b.k); i++ {
+		pos := (h1 + i*h2) % m
+		if b.bits[pos/8]&(1<<(pos%8)) == 0 {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,182 @@
+package yfs
+
+import (
+	"io"
+
+	"github.com/aclements/go-rabin/rabin"
+	"github.com/evanphx/yfs/format"
+)
+
+// chunker splits a stream into content defined chunks. Next returns the
+// length of the next chunk, which the caller takes from the data it has
+// seen pass through the reader given to the chunker.
+type chunker interface {
+	Next() (int, error)
+}
+
+func (f *FS) newChunker(r io.Reader) chunker {
+	cp := f.chunking
+
+	switch cp.Algorithm {
+	case format.FastCDC:
+		return newFastCDC(r, cp.MinBlock, cp.AverageBlock, cp.MaxBlock)
+	default:
+		return rabin.NewChunker(f.table, r, cp.MinBlock, cp.AverageBlock, cp.MaxBlock)
+	}
+}
+
+// gearTable maps each byte to a random 64bit value for the gear hash.
+// It's generated from a fixed seed because block boundaries, and so
+// deduplication against existing repositories, depend on it never
+// changing.
+var gearTable [256]uint64
+
+func init() {
+	// splitmix64
+	x := uint64(0x79667320676561)
+
+	for i := range gearTable {
+		x += 0x9e3779b97f4a7c15
+		z := x
+		z = (z ^ (z >> 30)) * 0xbf58476d1ce4e5b9
+		z = (z ^ (z >> 27)) * 0x94d049bb133111eb
+		gearTable[i] = z ^ (z >> 31)
+	}
+}
+
+// fastCDC implements FastCDC (Xia et al, 2016). It rolls a gear hash,
+// which costs a shift and an add per byte, skips hashing the first
+// min bytes of every chunk since they can't be a cut point, and uses
+// normalized chunking: a stricter mask before the average size and a
+// looser one after it, pulling chunk sizes toward the average.
+type fastCDC struct {
+	r io.Reader
+
+	min, avg, max int
+	maskS, maskL  uint64
+
+	buf        []byte
+	start, end int
+	eof        bool
+	emitted    bool
+}
+
+func newFastCDC(r io.Reader, min, avg, max int) *fastCDC {
+	bits := uint(0)
+	for (1 << bits) < avg {
+		bits++
+	}
+
+	lbits := uint(0)
+	if bits > 2 {
+		lbits = bits - 2
+	}
+
+	return &fastCDC{
+		r:     r,
+		min:   min,
+		avg:   avg,
+		max:   max,
+		maskS: topBits(bits + 2),
+		maskL: topBits(lbits),
+		buf:   make([]byte, max*2),
+	}
+}
+
+// topBits returns a mask of the n most significant bits. The gear hash
+// shifts left on every byte, so the high bits depend on the most input.
+func topBits(n uint) uint64 {
+	if n == 0 {
+		return 0
+	}
+
+	return ^uint64(0) << (64 - n)
+}
+
+func (c *fastCDC) fill() error {
+	if c.start > 0 {
+		c.end = copy(c.buf, c.buf[c.start:c.end])
+		c.start = 0
+	}
+
+	for c.end < len(c.buf) && !c.eof {
+		n, err := c.r.Read(c.buf[c.end:])
+		c.end += n
+
+		if err != nil {
+			if err == io.EOF {
+				c.eof = true
+				break
+			}
+
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (c *fastCDC) Next() (int, error) {
+	if c.end-c.start < c.max && !c.eof {
+		err := c.fill()
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	n := c.end - c.start
+
+	if n == 0 {
+		// Like the rabin chunker, empty input is a single empty chunk.
+		if !c.emitted {
+			c.emitted = true
+			return 0, nil
+		}
+
+		return 0, io.EOF
+	}
+
+	c.emitted = true
+
+	sz := c.cut(c.buf[c.start:c.end])
+	c.start += sz
+
+	return sz, nil
+}
+
+func (c *fastCDC) cut(data []byte) int {
+	n := len(data)
+
+	if n <= c.min {
+		return n
+	}
+
+	if n > c.max {
+		n = c.max
+	}
+
+	normal := c.avg
+	if n < normal {
+		normal = n
+	}
+
+	var fp uint64
+
+	i := c.min
+
+	for ; i < normal; i++ {
+		fp = (fp << 1) + gearTable[data[i]]
+		if fp&c.maskS == 0 {
+			return i + 1
+		}
+	}
+
+	for ; i < n; i++ {
+		fp = (fp << 1) + gearTable[data[i]]
+		if fp&c.maskL == 0 {
+			return i + 1
+		}
+	}
+
+	return n
+}
@@ -0,0 +1,172 @@
+package yfs
+
+import (
+	"bytes"
+	"io"
+	"math/rand"
+	"testing"
+
+	"github.com/aclements/go-rabin/rabin"
+	"github.com/evanphx/yfs/format"
+	"github.com/golang/crypto/blake2b"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vektra/neko"
+)
+
+func chunkAll(t testing.TB, c chunker, data []byte) [][]byte {
+	var chunks [][]byte
+
+	for {
+		n, err := c.Next()
+		if err == io.EOF {
+			break
+		}
+
+		require.NoError(t, err)
+
+		chunks = append(chunks, data[:n])
+		data = data[n:]
+	}
+
+	return chunks
+}
+
+func TestFastCDC(t *testing.T) {
+	n := neko.Modern(t)
+
+	data := make([]byte, AverageBlock*256)
+	rand.New(rand.NewSource(1)).Read(data)
+
+	n.It("produces chunks within the size bounds", func(t *testing.T) {
+		chunks := chunkAll(t, newFastCDC(bytes.NewReader(data), MinBlock, AverageBlock, MaxBlock), data)
+
+		var total int
+
+		for i, chunk := range chunks {
+			total += len(chunk)
+
+			assert.True(t, len(chunk) <= MaxBlock)
+
+			if i < len(chunks)-1 {
+				assert.True(t, len(chunk) >= MinBlock)
+			}
+		}
+
+		assert.Equal(t, len(data), total)
+
+		avg := len(data) / len(chunks)
+		assert.True(t, avg > AverageBlock/2 && avg < AverageBlock*2, "average chunk was %d", avg)
+	})
+
+	n.It("finds the same boundaries after an insertion", func(t *testing.T) {
+		edited := append([]byte("inserted"), data...)
+
+		a := chunkAll(t, newFastCDC(bytes.NewReader(data), MinBlock, AverageBlock, MaxBlock), data)
+		b := chunkAll(t, newFastCDC(bytes.NewReader(edited), MinBlock, AverageBlock, MaxBlock), edited)
+
+		seen := map[[32]byte]bool{}
+		for _, chunk := range a {
+			seen[blake2b.Sum256(chunk)] = true
+		}
+
+		var shared int
+		for _, chunk := range b {
+			if seen[blake2b.Sum256(chunk)] {
+				shared++
+			}
+		}
+
+		assert.True(t, shared >= len(b)-2, "only %d of %d chunks shared", shared, len(b))
+	})
+
+	n.It("returns a single empty chunk for empty input", func(t *testing.T) {
+		c := newFastCDC(bytes.NewReader(nil), MinBlock, AverageBlock, MaxBlock)
+
+		sz, err := c.Next()
+		require.NoError(t, err)
+		assert.Equal(t, 0, sz)
+
+		_, err = c.Next()
+		assert.Equal(t, io.EOF, err)
+	})
+
+	n.Meow()
+}
+
+// benchCorpus builds a corpus of versions of the same data with small
+// edits between them, which is what deduplication is meant to find.
+func benchCorpus() [][]byte {
+	rng := rand.New(rand.NewSource(42))
+
+	base := make([]byte, 4<<20)
+	rng.Read(base)
+
+	versions := [][]byte{base}
+
+	for i := 0; i < 7; i++ {
+		prev := versions[len(versions)-1]
+		next := make([]byte, 0, len(prev)+1024)
+
+		at := rng.Intn(len(prev))
+		insert := make([]byte, rng.Intn(512)+1)
+		rng.Read(insert)
+
+		next = append(next, prev[:at]...)
+		next = append(next, insert...)
+		next = append(next, prev[at:]...)
+
+		versions = append(versions, next)
+	}
+
+	return versions
+}
+
+func BenchmarkChunkers(b *testing.B) {
+	corpus := benchCorpus()
+
+	var size int64
+	for _, v := range corpus {
+		size += int64(len(v))
+	}
+
+	table := rabin.NewTable(rabin.Poly64, Window)
+
+	chunkers := []struct {
+		name string
+		alg  format.ChunkAlgorithm
+		new  func(r io.Reader) chunker
+	}{
+		{"rabin", format.Rabin, func(r io.Reader) chunker {
+			return rabin.NewChunker(table, r, MinBlock, AverageBlock, MaxBlock)
+		}},
+		{"fastcdc", format.FastCDC, func(r io.Reader) chunker {
+			return newFastCDC(r, MinBlock, AverageBlock, MaxBlock)
+		}},
+	}
+
+	for _, c := range chunkers {
+		b.Run(c.name, func(b *testing.B) {
+			b.SetBytes(size)
+
+			var unique int64
+
+			for i := 0; i < b.N; i++ {
+				seen := map[[32]byte]bool{}
+				unique = 0
+
+				for _, v := range corpus {
+					for _, chunk := range chunkAll(b, c.new(bytes.NewReader(v)), v) {
+						sum := blake2b.Sum256(chunk)
+						if !seen[sum] {
+							seen[sum] = true
+							unique += int64(len(chunk))
+						}
+					}
+				}
+			}
+
+			b.ReportMetric(float64(size)/float64(unique), "dedup-ratio")
+		})
+	}
+}
@@ -0,0 +1,124 @@
+// Command yfs-key creates and unlocks passphrase protected yfs key files.
+//
+//	yfs-key create <file>   generate a new key and write it to file
+//	yfs-key unlock <file>   check the passphrase and print the key id
+//	yfs-key id <file>       print the key id without the passphrase
+//
+// The passphrase is read from the terminal, or from the YFS_PASSPHRASE
+// environment variable when set.
+package main
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/evanphx/yfs"
+	"golang.org/x/term"
+)
+
+func main() {
+	if len(os.Args) != 3 {
+		usage()
+	}
+
+	var err error
+
+	switch os.Args[1] {
+	case "create":
+		err = create(os.Args[2])
+	case "unlock":
+		err = unlock(os.Args[2])
+	case "id":
+		err = id(os.Args[2])
+	default:
+		usage()
+	}
+
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "yfs-key: %s\n", err)
+		os.Exit(1)
+	}
+}
+
+func usage() {
+	fmt.Fprintf(os.Stderr, "usage: yfs-key create|unlock|id <file>\n")
+	os.Exit(2)
+}
+
+func readPassphrase(prompt string) ([]byte, error) {
+	if pass, ok := os.LookupEnv("YFS_PASSPHRASE"); ok {
+		return []byte(pass), nil
+	}
+
+	fmt.Fprint(os.Stderr, prompt)
+	defer fmt.Fprintln(os.Stderr)
+
+	return term.ReadPassword(int(os.Stdin.Fd()))
+}
+
+func create(path string) error {
+	pass, err := readPassphrase("Passphrase: ")
+	if err != nil {
+		return err
+	}
+
+	if _, ok := os.LookupEnv("YFS_PASSPHRASE"); !ok {
+		again, err := readPassphrase("Repeat passphrase: ")
+		if err != nil {
+			return err
+		}
+
+		if !bytes.Equal(pass, again) {
+			return errors.New("passphrases don't match")
+		}
+	}
+
+	if len(pass) == 0 {
+		return errors.New("empty passphrase")
+	}
+
+	key := yfs.GenerateKey()
+
+	err = yfs.SaveKeyFile(path, key, pass)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(hex.EncodeToString(key.Id()))
+	return nil
+}
+
+func unlock(path string) error {
+	pass, err := readPassphrase("Passphrase: ")
+	if err != nil {
+		return err
+	}
+
+	key, err := yfs.LoadKeyFile(path, pass)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(hex.EncodeToString(key.Id()))
+	return nil
+}
+
+func id(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+
+	defer f.Close()
+
+	kid, err := yfs.KeyFileId(f)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(hex.EncodeToString(kid))
+	return nil
+}
@@ -1,19 +1,79 @@
 package yfs
 
-import "github.com/pierrec/lz4"
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/rand"
 
+	"github.com/klauspost/compress/dict"
+	"github.com/klauspost/compress/zstd"
+	"github.com/pierrec/lz4"
+)
+
+// Repositories created before codec tagging store compressed blocks in
+// the bare lz4 frame, and whether blocks are compressed at all is fixed
+// by TOCHeader.Compressed. Newer repositories record blockFrameTagged
+// in their config and prefix every block with the codec that wrote it,
+// so the codec can change without rewriting existing blocks.
+const (
+	blockFrameLegacy = 0
+	blockFrameTagged = 1
+)
+
+const (
+	codecNone byte = 0
+	// codecLZ4 blocks use the legacy 2 byte length lz4 frame, they're
+	// only read now.
+	codecLZ4       byte = 1
+	codecZstd      byte = 2
+	codecLZ4Framed byte = 3
+)
+
+// maxFrameBlock is the largest block the legacy lz4 frame can describe,
+// it stores the uncompressed length in 2 bytes.
+const maxFrameBlock = 0xffff
+
+// lz4FrameVersion is the first byte of the lz4 frame used by
+// codecLZ4Framed, followed by the uncompressed length as a uvarint.
+const lz4FrameVersion = 1
+
+// maxFrameLength bounds the uncompressed length an lz4 frame may claim,
+// so that a corrupt one can't make a reader allocate without limit.
+const maxFrameLength = 1 << 30
+
+// DefaultZstdLevel is the zstd level used by WithZstd when given 0.
+const DefaultZstdLevel = 3
+
+var (
+	ErrUnknownCodec         = errors.New("block uses an unknown compression codec")
+	ErrTaggedFramesRequired = errors.New("codec requires a repository with tagged block frames")
+	ErrUnknownFrameVersion  = errors.New("block uses an unknown lz4 frame version")
+)
+
+// lz4Writer writes the legacy lz4 frame, which stores the uncompressed
+// length in 2 bytes with 0 meaning the block is stored as is.
 type lz4Writer struct{}
 
 func (l lz4Writer) Transform(block []byte) ([]byte, []byte, error) {
-	out := getBlockBuf(len(block) + 2)
+	bound := lz4.CompressBlockBound(len(block))
+	out := getBlockBuf(bound + 2)
 
-	clen, err := lz4.CompressBlock(block, out[2:], 0)
-	if err != nil {
-		return nil, nil, err
+	var clen int
+
+	// The length of larger blocks can't be described, but they can
+	// still be stored uncompressed.
+	if len(block) <= maxFrameBlock {
+		var err error
+
+		clen, err = lz4.CompressBlock(block, out[2:2+bound], 0)
+		if err != nil {
+			return nil, nil, err
+		}
 	}
 
 	// Welp, not compressable, bummer.
-	if clen == 0 {
+	if clen == 0 || clen >= len(block) {
 		copy(out[2:], block)
 		out[0] = 0
 		out[1] = 0
@@ -29,6 +89,10 @@ func (l lz4Writer) Transform(block []byte) ([]byte, []byte, error) {
 type lz4Reader struct{}
 
 func (l lz4Reader) Transform(block []byte) ([]byte, []byte, error) {
+	if len(block) < 2 {
+		return nil, nil, ErrCorruptBlock
+	}
+
 	var plen uint16 = uint16(block[0]) | (uint16(block[1]) << 8)
 
 	block = block[2:]
@@ -48,10 +112,310 @@ func (l lz4Reader) Transform(block []byte) ([]byte, []byte, error) {
 	return out[:len], out, nil
 }
 
+// lz4FrameWriter writes the versioned lz4 frame, which has no limit on
+// the block size.
+type lz4FrameWriter struct{}
+
+func (l lz4FrameWriter) Transform(block []byte) ([]byte, []byte, error) {
+	bound := lz4.CompressBlockBound(len(block))
+	out := getBlockBuf(1 + binary.MaxVarintLen64 + bound)
+
+	out[0] = lz4FrameVersion
+
+	hlen := 1 + binary.PutUvarint(out[1:], uint64(len(block)))
+
+	clen, err := lz4.CompressBlock(block, out[hlen:hlen+bound], 0)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Stored as is when compressing doesn't make it smaller.
+	if clen == 0 || clen >= len(block) {
+		hlen = 1 + binary.PutUvarint(out[1:], 0)
+		copy(out[hlen:], block)
+		return out[:hlen+len(block)], out, nil
+	}
+
+	return out[:hlen+clen], out, nil
+}
+
+type lz4FrameReader struct{}
+
+func (l lz4FrameReader) Transform(block []byte) ([]byte, []byte, error) {
+	if len(block) == 0 {
+		return nil, nil, ErrCorruptBlock
+	}
+
+	if block[0] != lz4FrameVersion {
+		return nil, nil, ErrUnknownFrameVersion
+	}
+
+	plen, n := binary.Uvarint(block[1:])
+	if n <= 0 || plen > maxFrameLength {
+		return nil, nil, ErrCorruptBlock
+	}
+
+	block = block[1+n:]
+
+	// It wasn't compressed
+	if plen == 0 {
+		return block, nil, nil
+	}
+
+	out := getBlockBuf(int(plen))
+
+	len, err := lz4.UncompressBlock(block, out[:plen], 0)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if uint64(len) != plen {
+		return nil, nil, ErrCorruptBlock
+	}
+
+	return out[:len], out, nil
+}
+
+type zstdWriter struct {
+	enc *zstd.Encoder
+}
+
+func newZstdWriter(level int, dicts [][]byte) (*zstdWriter, error) {
+	if level == 0 {
+		level = DefaultZstdLevel
+	}
+
+	opts := []zstd.EOption{
+		zstd.WithEncoderLevel(zstd.EncoderLevelFromZstd(level)),
+		// Blocks are already verified against their id.
+		zstd.WithEncoderCRC(false),
+	}
+
+	if len(dicts) > 0 {
+		opts = append(opts, zstd.WithEncoderDict(dicts[len(dicts)-1]))
+	}
+
+	enc, err := zstd.NewWriter(nil, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &zstdWriter{enc: enc}, nil
+}
+
+func (z *zstdWriter) Transform(block []byte) ([]byte, []byte, error) {
+	out := z.enc.EncodeAll(block, getBlockBuf(len(block))[:0])
+	return out, out, nil
+}
+
+// codecWriter compresses blocks with codec and prefixes them with tag.
+// Blocks that don't get smaller are stored as codecNone.
+type codecWriter struct {
+	tag   byte
+	codec blockTransform
+}
+
+func (c *codecWriter) Transform(block []byte) ([]byte, []byte, error) {
+	tag, payload := codecNone, block
+
+	if c.codec != nil {
+		out, _, err := c.codec.Transform(block)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		if len(out) < len(block) {
+			tag, payload = c.tag, out
+		}
+	}
+
+	out := getBlockBuf(len(payload) + 1)
+	out[0] = tag
+	copy(out[1:], payload)
+
+	return out[:len(payload)+1], out, nil
+}
+
+// codecReader decodes tagged blocks written by any codec.
+type codecReader struct {
+	lz4      lz4Reader
+	lz4Frame lz4FrameReader
+	zstd     *zstd.Decoder
+}
+
+func newCodecReader(dicts [][]byte) (*codecReader, error) {
+	dec, err := zstd.NewReader(nil, zstd.WithDecoderDicts(dicts...))
+	if err != nil {
+		return nil, err
+	}
+
+	return &codecReader{zstd: dec}, nil
+}
+
+func (c *codecReader) Transform(block []byte) ([]byte, []byte, error) {
+	if len(block) == 0 {
+		return nil, nil, ErrCorruptBlock
+	}
+
+	switch block[0] {
+	case codecNone:
+		return block[1:], nil, nil
+	case codecLZ4:
+		return c.lz4.Transform(block[1:])
+	case codecLZ4Framed:
+		return c.lz4Frame.Transform(block[1:])
+	case codecZstd:
+		out, err := c.zstd.DecodeAll(block[1:], nil)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		return out, nil, nil
+	default:
+		return nil, nil, ErrUnknownCodec
+	}
+}
+
 func WithLZ4() func(f *FS) {
 	return func(f *FS) {
-		f.tocHeader.Compressed = true
-		f.blockAccess.write.compression = lz4Writer{}
-		f.blockAccess.read.compression = lz4Reader{}
+		f.codec = codecLZ4
+	}
+}
+
+// WithZstd compresses new blocks with zstd at the given level, using the
+// most recently trained dictionary if the repository has one. Only
+// repositories with tagged block frames support zstd.
+func WithZstd(level int) Option {
+	return Option(func(f *FS) {
+		f.codec = codecZstd
+		f.zstdLevel = level
+	})
+}
+
+// setupCompression installs the compression transforms for the codec
+// chosen by the options, according to the repository's block frame.
+func (f *FS) setupCompression() error {
+	f.blockAccess.write.compression = nil
+	f.blockAccess.read.compression = nil
+
+	if f.config.BlockFrame == blockFrameLegacy {
+		switch f.codec {
+		case codecNone:
+		case codecLZ4:
+			// The legacy frame can't compress larger blocks, it would
+			// store every one of them uncompressed.
+			if f.chunking.MaxBlock > maxFrameBlock {
+				return fmt.Errorf("max block %d exceeds the legacy lz4 frame limit of %d",
+					f.chunking.MaxBlock, maxFrameBlock)
+			}
+
+			f.tocHeader.Compressed = true
+			f.blockAccess.write.compression = lz4Writer{}
+			f.blockAccess.read.compression = lz4Reader{}
+		default:
+			return ErrTaggedFramesRequired
+		}
+
+		return nil
 	}
+
+	cw := &codecWriter{tag: f.codec}
+
+	switch f.codec {
+	case codecNone:
+	case codecLZ4:
+		cw.tag = codecLZ4Framed
+		cw.codec = lz4FrameWriter{}
+	case codecZstd:
+		zw, err := newZstdWriter(f.zstdLevel, f.config.ZstdDictionaries)
+		if err != nil {
+			return err
+		}
+
+		cw.codec = zw
+	default:
+		return ErrUnknownCodec
+	}
+
+	cr, err := newCodecReader(f.config.ZstdDictionaries)
+	if err != nil {
+		return err
+	}
+
+	f.tocHeader.Compressed = f.codec != codecNone
+	f.blockAccess.write.compression = cw
+	f.blockAccess.read.compression = cr
+
+	return nil
+}
+
+// MaxDictionarySize bounds the size of dictionaries built by
+// TrainZstdDictionary.
+const MaxDictionarySize = 64 << 10
+
+// TrainZstdDictionary builds a zstd dictionary from up to samples
+// randomly chosen blocks already in the repository and records it in the
+// repository config. Blocks written afterwards with WithZstd use it,
+// which helps most when blocks are small and similar to each other.
+// Blocks compressed with earlier dictionaries remain readable.
+func (f *FS) TrainZstdDictionary(samples int) error {
+	if f.readOnly {
+		return ErrReadOnly
+	}
+
+	if f.config.BlockFrame != blockFrameTagged {
+		return ErrTaggedFramesRequired
+	}
+
+	f.txnlock.Lock()
+	defer f.txnlock.Unlock()
+
+	f.blockslock.RLock()
+	ids := make([]BlockId, len(f.blocks.Blocks))
+	for i, blk := range f.blocks.Blocks {
+		ids[i] = blk.Id
+	}
+	f.blockslock.RUnlock()
+
+	rand.Shuffle(len(ids), func(i, j int) {
+		ids[i], ids[j] = ids[j], ids[i]
+	})
+
+	if len(ids) > samples {
+		ids = ids[:samples]
+	}
+
+	var input [][]byte
+
+	for _, id := range ids {
+		data, err := f.blockAccess.readBlock(id)
+		if err != nil {
+			return err
+		}
+
+		input = append(input, append([]byte(nil), data...))
+	}
+
+	level := f.zstdLevel
+	if level == 0 {
+		level = DefaultZstdLevel
+	}
+
+	d, err := dict.BuildZstdDict(input, dict.Options{
+		MaxDictSize: MaxDictionarySize,
+		HashBytes:   6,
+		ZstdLevel:   zstd.EncoderLevelFromZstd(level),
+	})
+	if err != nil {
+		return err
+	}
+
+	f.config.ZstdDictionaries = append(f.config.ZstdDictionaries, d)
+
+	err = f.writeConfig()
+	if err != nil {
+		return err
+	}
+
+	return f.setupCompression()
 }
@@ -0,0 +1,112 @@
+package yfs
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vektra/neko"
+)
+
+func TestCompression(t *testing.T) {
+	n := neko.Modern(t)
+
+	compressible := bytes.Repeat([]byte("compressible "), 20000)
+
+	incompressible := make([]byte, 200000)
+	rand.Read(incompressible)
+
+	// Only partly compressible, so compressing it can need more room
+	// than the stored block would.
+	mixed := make([]byte, 200000)
+	rand.Read(mixed[:100000])
+
+	n.It("round trips large blocks through the lz4 frame", func(t *testing.T) {
+		for _, block := range [][]byte{compressible, incompressible, mixed} {
+			out, _, err := lz4FrameWriter{}.Transform(block)
+			require.NoError(t, err)
+
+			assert.Equal(t, byte(lz4FrameVersion), out[0])
+
+			data, _, err := lz4FrameReader{}.Transform(out)
+			require.NoError(t, err)
+
+			assert.Equal(t, block, data)
+		}
+	})
+
+	n.It("stores large blocks uncompressed in the legacy lz4 frame", func(t *testing.T) {
+		for _, block := range [][]byte{compressible, incompressible} {
+			out, _, err := lz4Writer{}.Transform(block)
+			require.NoError(t, err)
+
+			assert.Equal(t, []byte{0, 0}, out[:2])
+
+			data, _, err := lz4Reader{}.Transform(out)
+			require.NoError(t, err)
+
+			assert.Equal(t, block, data)
+		}
+	})
+
+	n.It("still reads blocks tagged with the legacy lz4 frame", func(t *testing.T) {
+		block := compressible[:AverageBlock]
+
+		out, _, err := lz4Writer{}.Transform(block)
+		require.NoError(t, err)
+
+		cr, err := newCodecReader(nil)
+		require.NoError(t, err)
+
+		data, _, err := cr.Transform(append([]byte{codecLZ4}, out...))
+		require.NoError(t, err)
+
+		assert.Equal(t, block, data)
+	})
+
+	n.It("rejects unknown lz4 frame versions", func(t *testing.T) {
+		out, _, err := lz4FrameWriter{}.Transform(compressible)
+		require.NoError(t, err)
+
+		out[0] = 9
+
+		_, _, err = lz4FrameReader{}.Transform(out)
+		assert.Equal(t, ErrUnknownFrameVersion, err)
+	})
+
+	n.It("round trips partly compressible blocks through the legacy lz4 frame", func(t *testing.T) {
+		// Straddles the random and zero halves.
+		straddling := mixed[len(mixed)/2-maxFrameBlock/2:][:maxFrameBlock]
+
+		for _, block := range [][]byte{straddling, incompressible[:maxFrameBlock]} {
+			out, _, err := lz4Writer{}.Transform(block)
+			require.NoError(t, err)
+
+			assert.True(t, len(out) <= len(block)+2)
+
+			data, _, err := lz4Reader{}.Transform(out)
+			require.NoError(t, err)
+
+			assert.Equal(t, block, data)
+		}
+	})
+
+	n.It("rejects lz4 frames claiming huge lengths", func(t *testing.T) {
+		out, _, err := lz4FrameWriter{}.Transform(compressible)
+		require.NoError(t, err)
+
+		for _, plen := range []uint64{maxFrameLength + 1, 1 << 63, ^uint64(0)} {
+			frame := []byte{lz4FrameVersion}
+			frame = append(frame, make([]byte, binary.MaxVarintLen64)...)
+			frame = append(frame[:1+binary.PutUvarint(frame[1:], plen)], out[2:]...)
+
+			_, _, err = lz4FrameReader{}.Transform(frame)
+			assert.Equal(t, ErrCorruptBlock, err)
+		}
+	})
+
+	n.Meow()
+}
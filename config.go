@@ -0,0 +1,197 @@
+package yfs
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/aclements/go-rabin/rabin"
+	"github.com/evanphx/yfs/format"
+)
+
+// ChunkParams controls how content defined chunking splits data into
+// blocks. They are recorded in the repository config when it's created,
+// since changing them later defeats deduplication against existing
+// blocks.
+type ChunkParams struct {
+	Algorithm    format.ChunkAlgorithm
+	Window       int
+	AverageBlock int
+	MinBlock     int
+	MaxBlock     int
+	Polynomial   uint64
+}
+
+// DefaultChunkParams are used for repositories created without
+// WithChunking.
+var DefaultChunkParams = ChunkParams{
+	Algorithm:    format.Rabin,
+	Window:       Window,
+	AverageBlock: AverageBlock,
+	MinBlock:     MinBlock,
+	MaxBlock:     MaxBlock,
+	Polynomial:   rabin.Poly64,
+}
+
+var ErrChunkingMismatch = errors.New("chunking parameters differ from repository config")
+
+func (p ChunkParams) validate() error {
+	switch p.Algorithm {
+	case format.Rabin:
+		if p.Window <= 0 {
+			return fmt.Errorf("invalid chunking: window must be positive, got %d", p.Window)
+		}
+
+		if p.Polynomial == 0 {
+			return fmt.Errorf("invalid chunking: polynomial must be set")
+		}
+	case format.FastCDC:
+		// FastCDC has no window and uses the gear table instead of
+		// a polynomial.
+	default:
+		return fmt.Errorf("invalid chunking: unknown algorithm %d", p.Algorithm)
+	}
+
+	switch {
+	case p.MinBlock <= 0:
+		return fmt.Errorf("invalid chunking: min block must be positive, got %d", p.MinBlock)
+	case p.AverageBlock&(p.AverageBlock-1) != 0:
+		return fmt.Errorf("invalid chunking: average block must be a power of 2, got %d", p.AverageBlock)
+	case p.MinBlock > p.AverageBlock || p.AverageBlock > p.MaxBlock:
+		return fmt.Errorf("invalid chunking: need min <= average <= max, got %d/%d/%d",
+			p.MinBlock, p.AverageBlock, p.MaxBlock)
+	}
+
+	return nil
+}
+
+func (p ChunkParams) toProto() *format.ChunkParams {
+	return &format.ChunkParams{
+		Algorithm:    p.Algorithm,
+		Window:       int32(p.Window),
+		AverageBlock: int32(p.AverageBlock),
+		MinBlock:     int32(p.MinBlock),
+		MaxBlock:     int32(p.MaxBlock),
+		Polynomial:   p.Polynomial,
+	}
+}
+
+func chunkParamsFromProto(cp *format.ChunkParams) ChunkParams {
+	return ChunkParams{
+		Algorithm:    cp.Algorithm,
+		Window:       int(cp.Window),
+		AverageBlock: int(cp.AverageBlock),
+		MinBlock:     int(cp.MinBlock),
+		MaxBlock:     int(cp.MaxBlock),
+		Polynomial:   cp.Polynomial,
+	}
+}
+
+func (f *FS) configPath() string {
+	return filepath.Join(f.root, "config")
+}
+
+// readConfig loads the repository config, creating it from the options
+// given to NewFS if the repository doesn't have one yet.
+func (f *FS) readConfig() error {
+	data, err := ioutil.ReadFile(f.configPath())
+	if err != nil {
+		if !os.IsNotExist(err) {
+			return err
+		}
+
+		if f.chunking == nil {
+			f.chunking = &DefaultChunkParams
+		}
+
+		err = f.chunking.validate()
+		if err != nil {
+			return err
+		}
+
+		f.config.Chunking = f.chunking.toProto()
+
+		existing, err := f.hasData()
+		if err != nil {
+			return err
+		}
+
+		// Repositories that predate the config keep the block framing
+		// and encryption their blocks were written with.
+		if !existing {
+			f.config.BlockFrame = blockFrameTagged
+			f.config.Authenticated = true
+			f.config.Version = FormatVersion
+		}
+
+		if f.readOnly {
+			return nil
+		}
+
+		return f.writeConfig()
+	}
+
+	err = f.config.Unmarshal(data)
+	if err != nil {
+		return err
+	}
+
+	if f.config.Version > FormatVersion {
+		return ErrUnsupportedVersion
+	}
+
+	params := DefaultChunkParams
+
+	if f.config.Chunking != nil {
+		params = chunkParamsFromProto(f.config.Chunking)
+	}
+
+	if f.chunking != nil && *f.chunking != params {
+		return ErrChunkingMismatch
+	}
+
+	err = params.validate()
+	if err != nil {
+		return err
+	}
+
+	f.chunking = &params
+
+	return nil
+}
+
+// hasData reports if the repository already contains any heads or blocks.
+func (f *FS) hasData() (bool, error) {
+	if _, err := os.Stat(filepath.Join(f.root, "blocks.idx")); err == nil {
+		return true, nil
+	}
+
+	heads, err := ioutil.ReadDir(filepath.Join(f.root, "heads"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return false, nil
+		}
+
+		return false, err
+	}
+
+	return len(heads) > 0, nil
+}
+
+func (f *FS) writeConfig() error {
+	data, err := f.config.Marshal()
+	if err != nil {
+		return err
+	}
+
+	tmp := f.configPath() + ".tmp"
+
+	err = ioutil.WriteFile(tmp, data, 0644)
+	if err != nil {
+		return err
+	}
+
+	return os.Rename(tmp, f.configPath())
+}
@@ -6,7 +6,10 @@ import (
 	"crypto/rand"
 	"encoding/binary"
 	"io"
+	"sync"
+	"sync/atomic"
 
+	"github.com/golang/crypto/blake2b"
 	"golang.org/x/crypto/chacha20poly1305"
 	"golang.org/x/crypto/curve25519"
 )
@@ -94,19 +97,26 @@ type cryptWriter struct {
 	key []byte
 
 	cipher cipher.AEAD
-	nonce  uint64
+
+	// nonce is the last nonce used, advanced atomically since blocks
+	// are encrypted by several goroutines at once.
+	nonce uint64
 }
 
 const CryptoOverhead = 32 + 12
 
 func (c *cryptWriter) Transform(block []byte) ([]byte, []byte, error) {
-	c.nonce++
+	return c.TransformAD(block, nil)
+}
+
+func (c *cryptWriter) TransformAD(block, ad []byte) ([]byte, []byte, error) {
+	n := atomic.AddUint64(&c.nonce, 1)
 
 	out := getBlockBuf(len(block) + CryptoOverhead + c.cipher.Overhead())
 
 	copy(out, c.temp.pub[:])
 
-	nonce := nonceBytes(c.nonce)
+	nonce := nonceBytes(n)
 
 	// log.Printf("encryption key: %s", spew.Sdump(c.key))
 
@@ -116,7 +126,7 @@ func (c *cryptWriter) Transform(block []byte) ([]byte, []byte, error) {
 
 	space := out[CryptoOverhead:]
 
-	ct := c.cipher.Seal(space[:0], nonce, block, nil)
+	ct := c.cipher.Seal(space[:0], nonce, block, ad)
 
 	result := out[:CryptoOverhead+len(ct)]
 
@@ -125,29 +135,108 @@ func (c *cryptWriter) Transform(block []byte) ([]byte, []byte, error) {
 	return result, out, nil
 }
 
+// convergentWriter encrypts blocks like cryptWriter, but derives the
+// ephemeral key and nonce from a hash of the block keyed with a secret
+// derived from the master key. Identical blocks encrypt to identical
+// ciphertext, and cryptReader reads them unchanged.
+type convergentWriter struct {
+	pkey   *Key
+	secret []byte
+}
+
+func newConvergentWriter(key *Key) (*convergentWriter, error) {
+	h, err := blake2b.New256(key.priv[:])
+	if err != nil {
+		return nil, err
+	}
+
+	h.Write([]byte("yfs convergent encryption"))
+
+	return &convergentWriter{
+		pkey:   key,
+		secret: h.Sum(nil),
+	}, nil
+}
+
+func (c *convergentWriter) Transform(block []byte) ([]byte, []byte, error) {
+	return c.TransformAD(block, nil)
+}
+
+func (c *convergentWriter) TransformAD(block, ad []byte) ([]byte, []byte, error) {
+	h, err := blake2b.New(32+12, c.secret)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	h.Write(block)
+
+	seed := h.Sum(nil)
+
+	var temp Key
+
+	copy(temp.priv[:], seed[:32])
+	curve25519.ScalarBaseMult(&temp.pub, &temp.priv)
+
+	var dst [32]byte
+	curve25519.ScalarMult(&dst, &temp.priv, &c.pkey.pub)
+
+	cipher, err := chacha20poly1305.New(dst[:])
+	if err != nil {
+		return nil, nil, err
+	}
+
+	out := getBlockBuf(len(block) + CryptoOverhead + cipher.Overhead())
+
+	copy(out, temp.pub[:])
+	copy(out[32:], seed[32:])
+
+	ct := cipher.Seal(out[CryptoOverhead:CryptoOverhead], seed[32:], block, ad)
+
+	return out[:CryptoOverhead+len(ct)], out, nil
+}
+
 type cryptReader struct {
 	key *Key
 
+	// The key derived for the last block, which blocks written by the
+	// same writer share. Guarded by mu, since blocks are read in
+	// parallel.
+	mu      sync.Mutex
 	prevPub []byte
 	prevKey []byte
 }
 
 func (c *cryptReader) Transform(block []byte) ([]byte, []byte, error) {
+	return c.TransformAD(block, nil)
+}
+
+func (c *cryptReader) TransformAD(block, ad []byte) ([]byte, []byte, error) {
+	if len(block) < CryptoOverhead {
+		return nil, nil, ErrCorruptBlock
+	}
+
 	out := getBlockBuf(len(block) + CryptoOverhead)
 
 	var key []byte
 
+	c.mu.Lock()
 	if c.prevPub != nil && bytes.Equal(c.prevPub, block[:32]) {
 		key = c.prevKey
-	} else {
+	}
+	c.mu.Unlock()
+
+	if key == nil {
 		var dst, in, base [32]byte
 		copy(in[:], c.key.priv[:])
 		copy(base[:], block[:32])
 		curve25519.ScalarMult(&dst, &in, &base)
 
 		key = dst[:]
+
+		c.mu.Lock()
 		c.prevKey = key
-		c.prevPub = block[:32]
+		c.prevPub = append([]byte(nil), block[:32]...)
+		c.mu.Unlock()
 	}
 
 	// log.Printf("decryption key: %s", spew.Sdump(key))
@@ -159,7 +248,7 @@ func (c *cryptReader) Transform(block []byte) ([]byte, []byte, error) {
 		return nil, nil, err
 	}
 
-	pt, err := cipher.Open(out[:0], block[32:44], block[CryptoOverhead:], nil)
+	pt, err := cipher.Open(out[:0], block[32:44], block[CryptoOverhead:], ad)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -167,16 +256,140 @@ func (c *cryptReader) Transform(block []byte) ([]byte, []byte, error) {
 	return pt, out, nil
 }
 
+// WithEncryption encrypts the repository's blocks and heads. key must
+// either have a key slot in the repository or be its master key.
 func WithEncryption(key *Key) func(*FS) {
 	return func(fs *FS) {
-		cw, err := newCryptWriter(key)
-		if err != nil {
-			panic(err)
+		fs.encKey = key
+	}
+}
+
+// WithConvergentEncryption makes WithEncryption derive each block's
+// encryption key and nonce from a hash of its contents, keyed with a
+// secret derived from the repository master key. Identical blocks then
+// encrypt to identical ciphertext no matter which process writes them,
+// so writers sharing a block store agree on its contents.
+//
+// The cost is confidentiality: encryption becomes deterministic, and
+// ciphertext reveals which blocks are equal. Block ids, being unkeyed
+// hashes of the contents, already reveal that within a store, and
+// deduplication already lets anyone who can get data written confirm
+// that a guessed block is stored. Convergent ciphertext extends that to
+// every copy of a block under the same master key, in mirrors, backups
+// and old copies of the store alike, for anyone who can see them.
+// Outsiders without the master key can't compute the ciphertext of a
+// guessed block, and repositories with different master keys, including
+// one before and after RotateReencrypt, share nothing.
+//
+// Blocks are compressed before they're encrypted, so the key and nonce
+// are derived from the compressed bytes. Identical blocks only encrypt
+// identically when they're written with the same codec and level, and
+// without WithAdaptiveCompression, which can store a block compressed
+// in one file and as is in another. Deriving them from the block id
+// instead would reuse a key and nonce for different ciphertexts.
+//
+// Because each key is only used for one plaintext, deriving the nonce
+// as well doesn't weaken the cipher. Blocks written either way are
+// read the same way, so it can be turned on and off at any time.
+func WithConvergentEncryption() Option {
+	return Option(func(fs *FS) {
+		fs.convergent = true
+	})
+}
+
+func (f *FS) setupEncryption() error {
+	f.blockAccess.authenticated = f.config.Authenticated
+
+	if f.encKey == nil {
+		return nil
+	}
+
+	master, prev, err := f.masterKeyFor(f.encKey)
+	if err != nil {
+		return err
+	}
+
+	return f.useMasterKey(master, prev)
+}
+
+// useMasterKey encrypts new data to master. prev is the master key being
+// rotated away from, if any, data encrypted to it stays readable.
+func (f *FS) useMasterKey(master, prev *Key) error {
+	var (
+		cw  blockTransform
+		err error
+	)
+
+	if f.convergent {
+		cw, err = newConvergentWriter(master)
+	} else {
+		cw, err = newCryptWriter(master)
+	}
+	if err != nil {
+		return err
+	}
+
+	f.masterKey = master
+	f.prevMasterKey = prev
+	f.tocHeader.KeyId = master.Id()
+
+	f.blockAccess.write.encryption = cw
+	f.blockAccess.headKeys = [][]byte{headMACKey(master)}
+
+	if prev == nil {
+		f.blockAccess.read.encryption = &cryptReader{key: master}
+	} else {
+		f.blockAccess.headKeys = append(f.blockAccess.headKeys, headMACKey(prev))
+
+		f.blockAccess.read.encryption = keyringReader{
+			&cryptReader{key: master},
+			&cryptReader{key: prev},
 		}
+	}
 
-		fs.tocHeader.KeyId = key.pub[:]
+	return nil
+}
 
-		fs.blockAccess.write.encryption = cw
-		fs.blockAccess.read.encryption = &cryptReader{key: key}
+// headMACKey derives the key heads are authenticated with from a master
+// key.
+func headMACKey(master *Key) []byte {
+	h, err := blake2b.New256(master.priv[:])
+	if err != nil {
+		panic(err)
 	}
+
+	h.Write([]byte("yfs head mac"))
+
+	return h.Sum(nil)
+}
+
+// validKeyId reports if a head with key id id can be read.
+func (f *FS) validKeyId(id []byte) bool {
+	if bytes.Equal(f.tocHeader.KeyId, id) {
+		return true
+	}
+
+	return f.prevMasterKey != nil && bytes.Equal(f.prevMasterKey.pub[:], id)
+}
+
+// keyringReader decrypts blocks encrypted to any of its keys.
+type keyringReader []*cryptReader
+
+func (k keyringReader) Transform(block []byte) ([]byte, []byte, error) {
+	return k.TransformAD(block, nil)
+}
+
+func (k keyringReader) TransformAD(block, ad []byte) ([]byte, []byte, error) {
+	var err error
+
+	for _, cr := range k {
+		var out, buf []byte
+
+		out, buf, err = cr.TransformAD(block, ad)
+		if err == nil {
+			return out, buf, nil
+		}
+	}
+
+	return nil, nil, err
 }
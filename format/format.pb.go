@@ -1,40 +1,20 @@
-// Code generated by protoc-gen-gogo.
+// Code generated by protoc-gen-gogo. DO NOT EDIT.
 // source: format.proto
-// DO NOT EDIT!
-
-/*
-	Package format is a generated protocol buffer package.
-
-	It is generated from these files:
-		format.proto
-
-	It has these top-level messages:
-		TOCHeader
-		Block
-		BlockSet
-		TimeSpec
-		Entry
-		TOC
-		BlockInfo
-		BlockTOC
-*/
-package format
-
-import proto "github.com/gogo/protobuf/proto"
-import fmt "fmt"
-import math "math"
-
-import strconv "strconv"
-
-import bytes "bytes"
 
-import strings "strings"
-import github_com_gogo_protobuf_proto "github.com/gogo/protobuf/proto"
-import sort "sort"
-import reflect "reflect"
-import github_com_gogo_protobuf_sortkeys "github.com/gogo/protobuf/sortkeys"
+package format
 
-import io "io"
+import (
+	bytes "bytes"
+	fmt "fmt"
+	proto "github.com/gogo/protobuf/proto"
+	github_com_gogo_protobuf_sortkeys "github.com/gogo/protobuf/sortkeys"
+	io "io"
+	math "math"
+	math_bits "math/bits"
+	reflect "reflect"
+	strconv "strconv"
+	strings "strings"
+)
 
 // Reference imports to suppress errors if they are not otherwise used.
 var _ = proto.Marshal
@@ -45,7 +25,7 @@ var _ = math.Inf
 // is compatible with the proto package it is being compiled against.
 // A compilation error at this line likely means your copy of the
 // proto package needs to be updated.
-const _ = proto.GoGoProtoPackageIsVersion2 // please upgrade the proto package
+const _ = proto.GoGoProtoPackageIsVersion3 // please upgrade the proto package
 
 type Type int32
 
@@ -62,6 +42,7 @@ var Type_name = map[int32]string{
 	2: "Dir",
 	3: "Link",
 }
+
 var Type_value = map[string]int32{
 	"TombStone": 0,
 	"File":      1,
@@ -69,7 +50,30 @@ var Type_value = map[string]int32{
 	"Link":      3,
 }
 
-func (Type) EnumDescriptor() ([]byte, []int) { return fileDescriptorFormat, []int{0} }
+func (Type) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_9d9ed1f28583505e, []int{0}
+}
+
+type ChunkAlgorithm int32
+
+const (
+	Rabin   ChunkAlgorithm = 0
+	FastCDC ChunkAlgorithm = 1
+)
+
+var ChunkAlgorithm_name = map[int32]string{
+	0: "Rabin",
+	1: "FastCDC",
+}
+
+var ChunkAlgorithm_value = map[string]int32{
+	"Rabin":   0,
+	"FastCDC": 1,
+}
+
+func (ChunkAlgorithm) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_9d9ed1f28583505e, []int{1}
+}
 
 type TOCHeader struct {
 	KeyId      []byte `protobuf:"bytes,1,opt,name=key_id,json=keyId,proto3" json:"key_id,omitempty"`
@@ -77,29 +81,187 @@ type TOCHeader struct {
 	Sum        []byte `protobuf:"bytes,3,opt,name=sum,proto3" json:"sum,omitempty"`
 	TocSize    int64  `protobuf:"varint,4,opt,name=toc_size,json=tocSize,proto3" json:"toc_size,omitempty"`
 	BlocksSize int64  `protobuf:"varint,5,opt,name=blocks_size,json=blocksSize,proto3" json:"blocks_size,omitempty"`
+	Mac        []byte `protobuf:"bytes,6,opt,name=mac,proto3" json:"mac,omitempty"`
+	Signature  []byte `protobuf:"bytes,7,opt,name=signature,proto3" json:"signature,omitempty"`
+	Signer     []byte `protobuf:"bytes,8,opt,name=signer,proto3" json:"signer,omitempty"`
+	Version    uint32 `protobuf:"varint,9,opt,name=version,proto3" json:"version,omitempty"`
+}
+
+func (m *TOCHeader) Reset()      { *m = TOCHeader{} }
+func (*TOCHeader) ProtoMessage() {}
+func (*TOCHeader) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9d9ed1f28583505e, []int{0}
+}
+func (m *TOCHeader) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *TOCHeader) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_TOCHeader.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *TOCHeader) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TOCHeader.Merge(m, src)
+}
+func (m *TOCHeader) XXX_Size() int {
+	return m.Size()
+}
+func (m *TOCHeader) XXX_DiscardUnknown() {
+	xxx_messageInfo_TOCHeader.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TOCHeader proto.InternalMessageInfo
+
+func (m *TOCHeader) GetKeyId() []byte {
+	if m != nil {
+		return m.KeyId
+	}
+	return nil
+}
+
+func (m *TOCHeader) GetCompressed() bool {
+	if m != nil {
+		return m.Compressed
+	}
+	return false
+}
+
+func (m *TOCHeader) GetSum() []byte {
+	if m != nil {
+		return m.Sum
+	}
+	return nil
+}
+
+func (m *TOCHeader) GetTocSize() int64 {
+	if m != nil {
+		return m.TocSize
+	}
+	return 0
+}
+
+func (m *TOCHeader) GetBlocksSize() int64 {
+	if m != nil {
+		return m.BlocksSize
+	}
+	return 0
+}
+
+func (m *TOCHeader) GetMac() []byte {
+	if m != nil {
+		return m.Mac
+	}
+	return nil
+}
+
+func (m *TOCHeader) GetSignature() []byte {
+	if m != nil {
+		return m.Signature
+	}
+	return nil
+}
+
+func (m *TOCHeader) GetSigner() []byte {
+	if m != nil {
+		return m.Signer
+	}
+	return nil
 }
 
-func (m *TOCHeader) Reset()                    { *m = TOCHeader{} }
-func (*TOCHeader) ProtoMessage()               {}
-func (*TOCHeader) Descriptor() ([]byte, []int) { return fileDescriptorFormat, []int{0} }
+func (m *TOCHeader) GetVersion() uint32 {
+	if m != nil {
+		return m.Version
+	}
+	return 0
+}
 
 type Block struct {
 	Id []byte `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
 }
 
-func (m *Block) Reset()                    { *m = Block{} }
-func (*Block) ProtoMessage()               {}
-func (*Block) Descriptor() ([]byte, []int) { return fileDescriptorFormat, []int{1} }
+func (m *Block) Reset()      { *m = Block{} }
+func (*Block) ProtoMessage() {}
+func (*Block) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9d9ed1f28583505e, []int{1}
+}
+func (m *Block) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *Block) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_Block.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *Block) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Block.Merge(m, src)
+}
+func (m *Block) XXX_Size() int {
+	return m.Size()
+}
+func (m *Block) XXX_DiscardUnknown() {
+	xxx_messageInfo_Block.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Block proto.InternalMessageInfo
+
+func (m *Block) GetId() []byte {
+	if m != nil {
+		return m.Id
+	}
+	return nil
+}
 
 type BlockSet struct {
-	Blocks   []*Block `protobuf:"bytes,1,rep,name=blocks" json:"blocks,omitempty"`
+	Blocks   []*Block `protobuf:"bytes,1,rep,name=blocks,proto3" json:"blocks,omitempty"`
 	Sum      []byte   `protobuf:"bytes,2,opt,name=sum,proto3" json:"sum,omitempty"`
 	ByteSize int64    `protobuf:"varint,3,opt,name=byte_size,json=byteSize,proto3" json:"byte_size,omitempty"`
 }
 
-func (m *BlockSet) Reset()                    { *m = BlockSet{} }
-func (*BlockSet) ProtoMessage()               {}
-func (*BlockSet) Descriptor() ([]byte, []int) { return fileDescriptorFormat, []int{2} }
+func (m *BlockSet) Reset()      { *m = BlockSet{} }
+func (*BlockSet) ProtoMessage() {}
+func (*BlockSet) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9d9ed1f28583505e, []int{2}
+}
+func (m *BlockSet) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *BlockSet) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_BlockSet.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *BlockSet) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BlockSet.Merge(m, src)
+}
+func (m *BlockSet) XXX_Size() int {
+	return m.Size()
+}
+func (m *BlockSet) XXX_DiscardUnknown() {
+	xxx_messageInfo_BlockSet.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BlockSet proto.InternalMessageInfo
 
 func (m *BlockSet) GetBlocks() []*Block {
 	if m != nil {
@@ -108,31 +270,136 @@ func (m *BlockSet) GetBlocks() []*Block {
 	return nil
 }
 
+func (m *BlockSet) GetSum() []byte {
+	if m != nil {
+		return m.Sum
+	}
+	return nil
+}
+
+func (m *BlockSet) GetByteSize() int64 {
+	if m != nil {
+		return m.ByteSize
+	}
+	return 0
+}
+
 type TimeSpec struct {
 	Seconds     int64 `protobuf:"varint,1,opt,name=seconds,proto3" json:"seconds,omitempty"`
 	Nanoseconds int32 `protobuf:"varint,2,opt,name=nanoseconds,proto3" json:"nanoseconds,omitempty"`
 }
 
-func (m *TimeSpec) Reset()                    { *m = TimeSpec{} }
-func (*TimeSpec) ProtoMessage()               {}
-func (*TimeSpec) Descriptor() ([]byte, []int) { return fileDescriptorFormat, []int{3} }
+func (m *TimeSpec) Reset()      { *m = TimeSpec{} }
+func (*TimeSpec) ProtoMessage() {}
+func (*TimeSpec) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9d9ed1f28583505e, []int{3}
+}
+func (m *TimeSpec) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *TimeSpec) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_TimeSpec.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *TimeSpec) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TimeSpec.Merge(m, src)
+}
+func (m *TimeSpec) XXX_Size() int {
+	return m.Size()
+}
+func (m *TimeSpec) XXX_DiscardUnknown() {
+	xxx_messageInfo_TimeSpec.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TimeSpec proto.InternalMessageInfo
+
+func (m *TimeSpec) GetSeconds() int64 {
+	if m != nil {
+		return m.Seconds
+	}
+	return 0
+}
+
+func (m *TimeSpec) GetNanoseconds() int32 {
+	if m != nil {
+		return m.Nanoseconds
+	}
+	return 0
+}
 
 type Entry struct {
 	ByteSize   int64     `protobuf:"varint,1,opt,name=byte_size,json=byteSize,proto3" json:"byte_size,omitempty"`
 	Type       Type      `protobuf:"varint,2,opt,name=type,proto3,enum=format.Type" json:"type,omitempty"`
 	Hash       []byte    `protobuf:"bytes,3,opt,name=hash,proto3" json:"hash,omitempty"`
-	Blocks     *BlockSet `protobuf:"bytes,4,opt,name=blocks" json:"blocks,omitempty"`
+	Blocks     *BlockSet `protobuf:"bytes,4,opt,name=blocks,proto3" json:"blocks,omitempty"`
 	Uname      string    `protobuf:"bytes,5,opt,name=uname,proto3" json:"uname,omitempty"`
 	Gname      string    `protobuf:"bytes,6,opt,name=gname,proto3" json:"gname,omitempty"`
 	Flags      int32     `protobuf:"varint,7,opt,name=flags,proto3" json:"flags,omitempty"`
 	Perm       int32     `protobuf:"varint,8,opt,name=perm,proto3" json:"perm,omitempty"`
-	CreatedAt  *TimeSpec `protobuf:"bytes,9,opt,name=created_at,json=createdAt" json:"created_at,omitempty"`
-	ModifiedAt *TimeSpec `protobuf:"bytes,10,opt,name=modified_at,json=modifiedAt" json:"modified_at,omitempty"`
+	CreatedAt  *TimeSpec `protobuf:"bytes,9,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	ModifiedAt *TimeSpec `protobuf:"bytes,10,opt,name=modified_at,json=modifiedAt,proto3" json:"modified_at,omitempty"`
+}
+
+func (m *Entry) Reset()      { *m = Entry{} }
+func (*Entry) ProtoMessage() {}
+func (*Entry) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9d9ed1f28583505e, []int{4}
+}
+func (m *Entry) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *Entry) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_Entry.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *Entry) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Entry.Merge(m, src)
+}
+func (m *Entry) XXX_Size() int {
+	return m.Size()
+}
+func (m *Entry) XXX_DiscardUnknown() {
+	xxx_messageInfo_Entry.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Entry proto.InternalMessageInfo
+
+func (m *Entry) GetByteSize() int64 {
+	if m != nil {
+		return m.ByteSize
+	}
+	return 0
 }
 
-func (m *Entry) Reset()                    { *m = Entry{} }
-func (*Entry) ProtoMessage()               {}
-func (*Entry) Descriptor() ([]byte, []int) { return fileDescriptorFormat, []int{4} }
+func (m *Entry) GetType() Type {
+	if m != nil {
+		return m.Type
+	}
+	return TombStone
+}
+
+func (m *Entry) GetHash() []byte {
+	if m != nil {
+		return m.Hash
+	}
+	return nil
+}
 
 func (m *Entry) GetBlocks() *BlockSet {
 	if m != nil {
@@ -141,6 +408,34 @@ func (m *Entry) GetBlocks() *BlockSet {
 	return nil
 }
 
+func (m *Entry) GetUname() string {
+	if m != nil {
+		return m.Uname
+	}
+	return ""
+}
+
+func (m *Entry) GetGname() string {
+	if m != nil {
+		return m.Gname
+	}
+	return ""
+}
+
+func (m *Entry) GetFlags() int32 {
+	if m != nil {
+		return m.Flags
+	}
+	return 0
+}
+
+func (m *Entry) GetPerm() int32 {
+	if m != nil {
+		return m.Perm
+	}
+	return 0
+}
+
 func (m *Entry) GetCreatedAt() *TimeSpec {
 	if m != nil {
 		return m.CreatedAt
@@ -156,12 +451,40 @@ func (m *Entry) GetModifiedAt() *TimeSpec {
 }
 
 type TOC struct {
-	Paths map[string]*Entry `protobuf:"bytes,1,rep,name=paths" json:"paths,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value"`
+	Paths map[string]*Entry `protobuf:"bytes,1,rep,name=paths,proto3" json:"paths,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
 }
 
-func (m *TOC) Reset()                    { *m = TOC{} }
-func (*TOC) ProtoMessage()               {}
-func (*TOC) Descriptor() ([]byte, []int) { return fileDescriptorFormat, []int{5} }
+func (m *TOC) Reset()      { *m = TOC{} }
+func (*TOC) ProtoMessage() {}
+func (*TOC) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9d9ed1f28583505e, []int{5}
+}
+func (m *TOC) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *TOC) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_TOC.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *TOC) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TOC.Merge(m, src)
+}
+func (m *TOC) XXX_Size() int {
+	return m.Size()
+}
+func (m *TOC) XXX_DiscardUnknown() {
+	xxx_messageInfo_TOC.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TOC proto.InternalMessageInfo
 
 func (m *TOC) GetPaths() map[string]*Entry {
 	if m != nil {
@@ -170,6 +493,111 @@ func (m *TOC) GetPaths() map[string]*Entry {
 	return nil
 }
 
+// TOCNode is a node of the tree a head's TOC is stored as. Leaves have
+// the entries for a range of paths, interior nodes the nodes below them
+// in path order. A TOC is a leaf on the wire.
+type TOCNode struct {
+	Paths    map[string]*Entry `protobuf:"bytes,1,rep,name=paths,proto3" json:"paths,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	Children []*TOCChild       `protobuf:"bytes,2,rep,name=children,proto3" json:"children,omitempty"`
+}
+
+func (m *TOCNode) Reset()      { *m = TOCNode{} }
+func (*TOCNode) ProtoMessage() {}
+func (*TOCNode) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9d9ed1f28583505e, []int{6}
+}
+func (m *TOCNode) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *TOCNode) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_TOCNode.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *TOCNode) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TOCNode.Merge(m, src)
+}
+func (m *TOCNode) XXX_Size() int {
+	return m.Size()
+}
+func (m *TOCNode) XXX_DiscardUnknown() {
+	xxx_messageInfo_TOCNode.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TOCNode proto.InternalMessageInfo
+
+func (m *TOCNode) GetPaths() map[string]*Entry {
+	if m != nil {
+		return m.Paths
+	}
+	return nil
+}
+
+func (m *TOCNode) GetChildren() []*TOCChild {
+	if m != nil {
+		return m.Children
+	}
+	return nil
+}
+
+type TOCChild struct {
+	First string    `protobuf:"bytes,1,opt,name=first,proto3" json:"first,omitempty"`
+	Node  *BlockSet `protobuf:"bytes,2,opt,name=node,proto3" json:"node,omitempty"`
+}
+
+func (m *TOCChild) Reset()      { *m = TOCChild{} }
+func (*TOCChild) ProtoMessage() {}
+func (*TOCChild) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9d9ed1f28583505e, []int{7}
+}
+func (m *TOCChild) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *TOCChild) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_TOCChild.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *TOCChild) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TOCChild.Merge(m, src)
+}
+func (m *TOCChild) XXX_Size() int {
+	return m.Size()
+}
+func (m *TOCChild) XXX_DiscardUnknown() {
+	xxx_messageInfo_TOCChild.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TOCChild proto.InternalMessageInfo
+
+func (m *TOCChild) GetFirst() string {
+	if m != nil {
+		return m.First
+	}
+	return ""
+}
+
+func (m *TOCChild) GetNode() *BlockSet {
+	if m != nil {
+		return m.Node
+	}
+	return nil
+}
+
 type BlockInfo struct {
 	Id         []byte `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
 	ByteSize   int64  `protobuf:"varint,2,opt,name=byte_size,json=byteSize,proto3" json:"byte_size,omitempty"`
@@ -177,258 +605,782 @@ type BlockInfo struct {
 	References int64  `protobuf:"varint,4,opt,name=references,proto3" json:"references,omitempty"`
 }
 
-func (m *BlockInfo) Reset()                    { *m = BlockInfo{} }
-func (*BlockInfo) ProtoMessage()               {}
-func (*BlockInfo) Descriptor() ([]byte, []int) { return fileDescriptorFormat, []int{6} }
-
-type BlockTOC struct {
-	Blocks      []*BlockInfo `protobuf:"bytes,1,rep,name=blocks" json:"blocks,omitempty"`
-	BloomFilter []byte       `protobuf:"bytes,2,opt,name=bloom_filter,json=bloomFilter,proto3" json:"bloom_filter,omitempty"`
+func (m *BlockInfo) Reset()      { *m = BlockInfo{} }
+func (*BlockInfo) ProtoMessage() {}
+func (*BlockInfo) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9d9ed1f28583505e, []int{8}
+}
+func (m *BlockInfo) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *BlockInfo) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_BlockInfo.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *BlockInfo) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BlockInfo.Merge(m, src)
+}
+func (m *BlockInfo) XXX_Size() int {
+	return m.Size()
+}
+func (m *BlockInfo) XXX_DiscardUnknown() {
+	xxx_messageInfo_BlockInfo.DiscardUnknown(m)
 }
 
-func (m *BlockTOC) Reset()                    { *m = BlockTOC{} }
-func (*BlockTOC) ProtoMessage()               {}
-func (*BlockTOC) Descriptor() ([]byte, []int) { return fileDescriptorFormat, []int{7} }
+var xxx_messageInfo_BlockInfo proto.InternalMessageInfo
 
-func (m *BlockTOC) GetBlocks() []*BlockInfo {
+func (m *BlockInfo) GetId() []byte {
 	if m != nil {
-		return m.Blocks
+		return m.Id
 	}
 	return nil
 }
 
-func init() {
-	proto.RegisterType((*TOCHeader)(nil), "format.TOCHeader")
-	proto.RegisterType((*Block)(nil), "format.Block")
-	proto.RegisterType((*BlockSet)(nil), "format.BlockSet")
-	proto.RegisterType((*TimeSpec)(nil), "format.TimeSpec")
-	proto.RegisterType((*Entry)(nil), "format.Entry")
-	proto.RegisterType((*TOC)(nil), "format.TOC")
-	proto.RegisterType((*BlockInfo)(nil), "format.BlockInfo")
-	proto.RegisterType((*BlockTOC)(nil), "format.BlockTOC")
-	proto.RegisterEnum("format.Type", Type_name, Type_value)
+func (m *BlockInfo) GetByteSize() int64 {
+	if m != nil {
+		return m.ByteSize
+	}
+	return 0
 }
-func (x Type) String() string {
-	s, ok := Type_name[int32(x)]
-	if ok {
-		return s
+
+func (m *BlockInfo) GetCompSize() int64 {
+	if m != nil {
+		return m.CompSize
 	}
-	return strconv.Itoa(int(x))
+	return 0
 }
-func (this *TOCHeader) Equal(that interface{}) bool {
-	if that == nil {
-		if this == nil {
-			return true
-		}
-		return false
+
+func (m *BlockInfo) GetReferences() int64 {
+	if m != nil {
+		return m.References
 	}
+	return 0
+}
 
-	that1, ok := that.(*TOCHeader)
-	if !ok {
-		that2, ok := that.(TOCHeader)
-		if ok {
-			that1 = &that2
-		} else {
-			return false
-		}
-	}
-	if that1 == nil {
-		if this == nil {
-			return true
+type BlockTOC struct {
+	Blocks      []*BlockInfo `protobuf:"bytes,1,rep,name=blocks,proto3" json:"blocks,omitempty"`
+	BloomFilter []byte       `protobuf:"bytes,2,opt,name=bloom_filter,json=bloomFilter,proto3" json:"bloom_filter,omitempty"`
+}
+
+func (m *BlockTOC) Reset()      { *m = BlockTOC{} }
+func (*BlockTOC) ProtoMessage() {}
+func (*BlockTOC) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9d9ed1f28583505e, []int{9}
+}
+func (m *BlockTOC) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *BlockTOC) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_BlockTOC.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
 		}
-		return false
-	} else if this == nil {
-		return false
+		return b[:n], nil
 	}
-	if !bytes.Equal(this.KeyId, that1.KeyId) {
-		return false
+}
+func (m *BlockTOC) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BlockTOC.Merge(m, src)
+}
+func (m *BlockTOC) XXX_Size() int {
+	return m.Size()
+}
+func (m *BlockTOC) XXX_DiscardUnknown() {
+	xxx_messageInfo_BlockTOC.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BlockTOC proto.InternalMessageInfo
+
+func (m *BlockTOC) GetBlocks() []*BlockInfo {
+	if m != nil {
+		return m.Blocks
 	}
-	if this.Compressed != that1.Compressed {
-		return false
+	return nil
+}
+
+func (m *BlockTOC) GetBloomFilter() []byte {
+	if m != nil {
+		return m.BloomFilter
 	}
-	if !bytes.Equal(this.Sum, that1.Sum) {
-		return false
+	return nil
+}
+
+type PackLocation struct {
+	Id     []byte `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Pack   uint32 `protobuf:"varint,2,opt,name=pack,proto3" json:"pack,omitempty"`
+	Offset int64  `protobuf:"varint,3,opt,name=offset,proto3" json:"offset,omitempty"`
+	Length int64  `protobuf:"varint,4,opt,name=length,proto3" json:"length,omitempty"`
+}
+
+func (m *PackLocation) Reset()      { *m = PackLocation{} }
+func (*PackLocation) ProtoMessage() {}
+func (*PackLocation) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9d9ed1f28583505e, []int{10}
+}
+func (m *PackLocation) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *PackLocation) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_PackLocation.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
 	}
-	if this.TocSize != that1.TocSize {
-		return false
+}
+func (m *PackLocation) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PackLocation.Merge(m, src)
+}
+func (m *PackLocation) XXX_Size() int {
+	return m.Size()
+}
+func (m *PackLocation) XXX_DiscardUnknown() {
+	xxx_messageInfo_PackLocation.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PackLocation proto.InternalMessageInfo
+
+func (m *PackLocation) GetId() []byte {
+	if m != nil {
+		return m.Id
 	}
-	if this.BlocksSize != that1.BlocksSize {
-		return false
+	return nil
+}
+
+func (m *PackLocation) GetPack() uint32 {
+	if m != nil {
+		return m.Pack
 	}
-	return true
+	return 0
 }
-func (this *Block) Equal(that interface{}) bool {
-	if that == nil {
-		if this == nil {
-			return true
-		}
-		return false
+
+func (m *PackLocation) GetOffset() int64 {
+	if m != nil {
+		return m.Offset
 	}
+	return 0
+}
 
-	that1, ok := that.(*Block)
-	if !ok {
-		that2, ok := that.(Block)
-		if ok {
-			that1 = &that2
-		} else {
-			return false
-		}
+func (m *PackLocation) GetLength() int64 {
+	if m != nil {
+		return m.Length
 	}
-	if that1 == nil {
-		if this == nil {
-			return true
+	return 0
+}
+
+type PackIndex struct {
+	Blocks []*PackLocation `protobuf:"bytes,1,rep,name=blocks,proto3" json:"blocks,omitempty"`
+}
+
+func (m *PackIndex) Reset()      { *m = PackIndex{} }
+func (*PackIndex) ProtoMessage() {}
+func (*PackIndex) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9d9ed1f28583505e, []int{11}
+}
+func (m *PackIndex) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *PackIndex) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_PackIndex.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
 		}
-		return false
-	} else if this == nil {
-		return false
+		return b[:n], nil
 	}
-	if !bytes.Equal(this.Id, that1.Id) {
-		return false
+}
+func (m *PackIndex) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PackIndex.Merge(m, src)
+}
+func (m *PackIndex) XXX_Size() int {
+	return m.Size()
+}
+func (m *PackIndex) XXX_DiscardUnknown() {
+	xxx_messageInfo_PackIndex.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PackIndex proto.InternalMessageInfo
+
+func (m *PackIndex) GetBlocks() []*PackLocation {
+	if m != nil {
+		return m.Blocks
 	}
-	return true
+	return nil
 }
-func (this *BlockSet) Equal(that interface{}) bool {
-	if that == nil {
-		if this == nil {
-			return true
+
+type ChunkParams struct {
+	Window       int32          `protobuf:"varint,1,opt,name=window,proto3" json:"window,omitempty"`
+	AverageBlock int32          `protobuf:"varint,2,opt,name=average_block,json=averageBlock,proto3" json:"average_block,omitempty"`
+	MinBlock     int32          `protobuf:"varint,3,opt,name=min_block,json=minBlock,proto3" json:"min_block,omitempty"`
+	MaxBlock     int32          `protobuf:"varint,4,opt,name=max_block,json=maxBlock,proto3" json:"max_block,omitempty"`
+	Polynomial   uint64         `protobuf:"varint,5,opt,name=polynomial,proto3" json:"polynomial,omitempty"`
+	Algorithm    ChunkAlgorithm `protobuf:"varint,6,opt,name=algorithm,proto3,enum=format.ChunkAlgorithm" json:"algorithm,omitempty"`
+}
+
+func (m *ChunkParams) Reset()      { *m = ChunkParams{} }
+func (*ChunkParams) ProtoMessage() {}
+func (*ChunkParams) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9d9ed1f28583505e, []int{12}
+}
+func (m *ChunkParams) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *ChunkParams) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_ChunkParams.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
 		}
-		return false
+		return b[:n], nil
 	}
+}
+func (m *ChunkParams) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ChunkParams.Merge(m, src)
+}
+func (m *ChunkParams) XXX_Size() int {
+	return m.Size()
+}
+func (m *ChunkParams) XXX_DiscardUnknown() {
+	xxx_messageInfo_ChunkParams.DiscardUnknown(m)
+}
 
-	that1, ok := that.(*BlockSet)
-	if !ok {
-		that2, ok := that.(BlockSet)
-		if ok {
-			that1 = &that2
-		} else {
-			return false
-		}
+var xxx_messageInfo_ChunkParams proto.InternalMessageInfo
+
+func (m *ChunkParams) GetWindow() int32 {
+	if m != nil {
+		return m.Window
 	}
-	if that1 == nil {
-		if this == nil {
-			return true
-		}
-		return false
-	} else if this == nil {
-		return false
+	return 0
+}
+
+func (m *ChunkParams) GetAverageBlock() int32 {
+	if m != nil {
+		return m.AverageBlock
 	}
-	if len(this.Blocks) != len(that1.Blocks) {
-		return false
+	return 0
+}
+
+func (m *ChunkParams) GetMinBlock() int32 {
+	if m != nil {
+		return m.MinBlock
 	}
-	for i := range this.Blocks {
-		if !this.Blocks[i].Equal(that1.Blocks[i]) {
-			return false
-		}
+	return 0
+}
+
+func (m *ChunkParams) GetMaxBlock() int32 {
+	if m != nil {
+		return m.MaxBlock
 	}
-	if !bytes.Equal(this.Sum, that1.Sum) {
-		return false
+	return 0
+}
+
+func (m *ChunkParams) GetPolynomial() uint64 {
+	if m != nil {
+		return m.Polynomial
 	}
-	if this.ByteSize != that1.ByteSize {
-		return false
+	return 0
+}
+
+func (m *ChunkParams) GetAlgorithm() ChunkAlgorithm {
+	if m != nil {
+		return m.Algorithm
 	}
-	return true
+	return Rabin
 }
-func (this *TimeSpec) Equal(that interface{}) bool {
-	if that == nil {
-		if this == nil {
-			return true
+
+type Config struct {
+	Chunking         *ChunkParams `protobuf:"bytes,1,opt,name=chunking,proto3" json:"chunking,omitempty"`
+	BlockFrame       uint32       `protobuf:"varint,2,opt,name=block_frame,json=blockFrame,proto3" json:"block_frame,omitempty"`
+	ZstdDictionaries [][]byte     `protobuf:"bytes,3,rep,name=zstd_dictionaries,json=zstdDictionaries,proto3" json:"zstd_dictionaries,omitempty"`
+	// Blocks are encrypted with their id as associated data, and heads
+	// with their header fields and a MAC.
+	Authenticated bool   `protobuf:"varint,4,opt,name=authenticated,proto3" json:"authenticated,omitempty"`
+	Version       uint32 `protobuf:"varint,5,opt,name=version,proto3" json:"version,omitempty"`
+	// Set while an upgrade from version 0 rewrites blocks and heads, which
+	// may still have the legacy frame until it's finished.
+	Upgrading bool `protobuf:"varint,6,opt,name=upgrading,proto3" json:"upgrading,omitempty"`
+}
+
+func (m *Config) Reset()      { *m = Config{} }
+func (*Config) ProtoMessage() {}
+func (*Config) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9d9ed1f28583505e, []int{13}
+}
+func (m *Config) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *Config) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_Config.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
 		}
-		return false
+		return b[:n], nil
 	}
+}
+func (m *Config) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Config.Merge(m, src)
+}
+func (m *Config) XXX_Size() int {
+	return m.Size()
+}
+func (m *Config) XXX_DiscardUnknown() {
+	xxx_messageInfo_Config.DiscardUnknown(m)
+}
 
-	that1, ok := that.(*TimeSpec)
-	if !ok {
-		that2, ok := that.(TimeSpec)
-		if ok {
-			that1 = &that2
-		} else {
-			return false
-		}
+var xxx_messageInfo_Config proto.InternalMessageInfo
+
+func (m *Config) GetChunking() *ChunkParams {
+	if m != nil {
+		return m.Chunking
 	}
-	if that1 == nil {
-		if this == nil {
-			return true
-		}
-		return false
-	} else if this == nil {
-		return false
+	return nil
+}
+
+func (m *Config) GetBlockFrame() uint32 {
+	if m != nil {
+		return m.BlockFrame
 	}
-	if this.Seconds != that1.Seconds {
-		return false
+	return 0
+}
+
+func (m *Config) GetZstdDictionaries() [][]byte {
+	if m != nil {
+		return m.ZstdDictionaries
 	}
-	if this.Nanoseconds != that1.Nanoseconds {
-		return false
+	return nil
+}
+
+func (m *Config) GetAuthenticated() bool {
+	if m != nil {
+		return m.Authenticated
 	}
-	return true
+	return false
 }
-func (this *Entry) Equal(that interface{}) bool {
-	if that == nil {
-		if this == nil {
-			return true
-		}
-		return false
+
+func (m *Config) GetVersion() uint32 {
+	if m != nil {
+		return m.Version
 	}
+	return 0
+}
 
-	that1, ok := that.(*Entry)
-	if !ok {
-		that2, ok := that.(Entry)
-		if ok {
-			that1 = &that2
-		} else {
-			return false
-		}
+func (m *Config) GetUpgrading() bool {
+	if m != nil {
+		return m.Upgrading
 	}
-	if that1 == nil {
-		if this == nil {
-			return true
+	return false
+}
+
+type KeyFile struct {
+	Version      uint32 `protobuf:"varint,1,opt,name=version,proto3" json:"version,omitempty"`
+	KeyId        []byte `protobuf:"bytes,2,opt,name=key_id,json=keyId,proto3" json:"key_id,omitempty"`
+	Salt         []byte `protobuf:"bytes,3,opt,name=salt,proto3" json:"salt,omitempty"`
+	ArgonTime    uint32 `protobuf:"varint,4,opt,name=argon_time,json=argonTime,proto3" json:"argon_time,omitempty"`
+	ArgonMemory  uint32 `protobuf:"varint,5,opt,name=argon_memory,json=argonMemory,proto3" json:"argon_memory,omitempty"`
+	ArgonThreads uint32 `protobuf:"varint,6,opt,name=argon_threads,json=argonThreads,proto3" json:"argon_threads,omitempty"`
+	Nonce        []byte `protobuf:"bytes,7,opt,name=nonce,proto3" json:"nonce,omitempty"`
+	SealedKey    []byte `protobuf:"bytes,8,opt,name=sealed_key,json=sealedKey,proto3" json:"sealed_key,omitempty"`
+}
+
+func (m *KeyFile) Reset()      { *m = KeyFile{} }
+func (*KeyFile) ProtoMessage() {}
+func (*KeyFile) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9d9ed1f28583505e, []int{14}
+}
+func (m *KeyFile) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *KeyFile) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_KeyFile.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
 		}
-		return false
-	} else if this == nil {
-		return false
-	}
-	if this.ByteSize != that1.ByteSize {
-		return false
+		return b[:n], nil
 	}
-	if this.Type != that1.Type {
-		return false
+}
+func (m *KeyFile) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_KeyFile.Merge(m, src)
+}
+func (m *KeyFile) XXX_Size() int {
+	return m.Size()
+}
+func (m *KeyFile) XXX_DiscardUnknown() {
+	xxx_messageInfo_KeyFile.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_KeyFile proto.InternalMessageInfo
+
+func (m *KeyFile) GetVersion() uint32 {
+	if m != nil {
+		return m.Version
 	}
-	if !bytes.Equal(this.Hash, that1.Hash) {
-		return false
+	return 0
+}
+
+func (m *KeyFile) GetKeyId() []byte {
+	if m != nil {
+		return m.KeyId
 	}
-	if !this.Blocks.Equal(that1.Blocks) {
-		return false
+	return nil
+}
+
+func (m *KeyFile) GetSalt() []byte {
+	if m != nil {
+		return m.Salt
 	}
-	if this.Uname != that1.Uname {
-		return false
+	return nil
+}
+
+func (m *KeyFile) GetArgonTime() uint32 {
+	if m != nil {
+		return m.ArgonTime
 	}
-	if this.Gname != that1.Gname {
-		return false
+	return 0
+}
+
+func (m *KeyFile) GetArgonMemory() uint32 {
+	if m != nil {
+		return m.ArgonMemory
 	}
-	if this.Flags != that1.Flags {
-		return false
+	return 0
+}
+
+func (m *KeyFile) GetArgonThreads() uint32 {
+	if m != nil {
+		return m.ArgonThreads
 	}
-	if this.Perm != that1.Perm {
-		return false
+	return 0
+}
+
+func (m *KeyFile) GetNonce() []byte {
+	if m != nil {
+		return m.Nonce
 	}
-	if !this.CreatedAt.Equal(that1.CreatedAt) {
-		return false
+	return nil
+}
+
+func (m *KeyFile) GetSealedKey() []byte {
+	if m != nil {
+		return m.SealedKey
 	}
-	if !this.ModifiedAt.Equal(that1.ModifiedAt) {
-		return false
+	return nil
+}
+
+type KeySlot struct {
+	KeyId      []byte `protobuf:"bytes,1,opt,name=key_id,json=keyId,proto3" json:"key_id,omitempty"`
+	Name       string `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Ephemeral  []byte `protobuf:"bytes,3,opt,name=ephemeral,proto3" json:"ephemeral,omitempty"`
+	Nonce      []byte `protobuf:"bytes,4,opt,name=nonce,proto3" json:"nonce,omitempty"`
+	WrappedKey []byte `protobuf:"bytes,5,opt,name=wrapped_key,json=wrappedKey,proto3" json:"wrapped_key,omitempty"`
+}
+
+func (m *KeySlot) Reset()      { *m = KeySlot{} }
+func (*KeySlot) ProtoMessage() {}
+func (*KeySlot) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9d9ed1f28583505e, []int{15}
+}
+func (m *KeySlot) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *KeySlot) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_KeySlot.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
 	}
-	return true
 }
-func (this *TOC) Equal(that interface{}) bool {
-	if that == nil {
-		if this == nil {
-			return true
+func (m *KeySlot) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_KeySlot.Merge(m, src)
+}
+func (m *KeySlot) XXX_Size() int {
+	return m.Size()
+}
+func (m *KeySlot) XXX_DiscardUnknown() {
+	xxx_messageInfo_KeySlot.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_KeySlot proto.InternalMessageInfo
+
+func (m *KeySlot) GetKeyId() []byte {
+	if m != nil {
+		return m.KeyId
+	}
+	return nil
+}
+
+func (m *KeySlot) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *KeySlot) GetEphemeral() []byte {
+	if m != nil {
+		return m.Ephemeral
+	}
+	return nil
+}
+
+func (m *KeySlot) GetNonce() []byte {
+	if m != nil {
+		return m.Nonce
+	}
+	return nil
+}
+
+func (m *KeySlot) GetWrappedKey() []byte {
+	if m != nil {
+		return m.WrappedKey
+	}
+	return nil
+}
+
+type KeySlots struct {
+	MasterId []byte     `protobuf:"bytes,1,opt,name=master_id,json=masterId,proto3" json:"master_id,omitempty"`
+	Slots    []*KeySlot `protobuf:"bytes,2,rep,name=slots,proto3" json:"slots,omitempty"`
+	// Set while the master key is being rotated. next_master holds the
+	// new master key wrapped for the old one.
+	NextMasterId []byte     `protobuf:"bytes,3,opt,name=next_master_id,json=nextMasterId,proto3" json:"next_master_id,omitempty"`
+	NextMaster   *KeySlot   `protobuf:"bytes,4,opt,name=next_master,json=nextMaster,proto3" json:"next_master,omitempty"`
+	NextSlots    []*KeySlot `protobuf:"bytes,5,rep,name=next_slots,json=nextSlots,proto3" json:"next_slots,omitempty"`
+}
+
+func (m *KeySlots) Reset()      { *m = KeySlots{} }
+func (*KeySlots) ProtoMessage() {}
+func (*KeySlots) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9d9ed1f28583505e, []int{16}
+}
+func (m *KeySlots) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *KeySlots) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_KeySlots.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
 		}
-		return false
+		return b[:n], nil
 	}
+}
+func (m *KeySlots) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_KeySlots.Merge(m, src)
+}
+func (m *KeySlots) XXX_Size() int {
+	return m.Size()
+}
+func (m *KeySlots) XXX_DiscardUnknown() {
+	xxx_messageInfo_KeySlots.DiscardUnknown(m)
+}
 
-	that1, ok := that.(*TOC)
+var xxx_messageInfo_KeySlots proto.InternalMessageInfo
+
+func (m *KeySlots) GetMasterId() []byte {
+	if m != nil {
+		return m.MasterId
+	}
+	return nil
+}
+
+func (m *KeySlots) GetSlots() []*KeySlot {
+	if m != nil {
+		return m.Slots
+	}
+	return nil
+}
+
+func (m *KeySlots) GetNextMasterId() []byte {
+	if m != nil {
+		return m.NextMasterId
+	}
+	return nil
+}
+
+func (m *KeySlots) GetNextMaster() *KeySlot {
+	if m != nil {
+		return m.NextMaster
+	}
+	return nil
+}
+
+func (m *KeySlots) GetNextSlots() []*KeySlot {
+	if m != nil {
+		return m.NextSlots
+	}
+	return nil
+}
+
+func init() {
+	proto.RegisterEnum("format.Type", Type_name, Type_value)
+	proto.RegisterEnum("format.ChunkAlgorithm", ChunkAlgorithm_name, ChunkAlgorithm_value)
+	proto.RegisterType((*TOCHeader)(nil), "format.TOCHeader")
+	proto.RegisterType((*Block)(nil), "format.Block")
+	proto.RegisterType((*BlockSet)(nil), "format.BlockSet")
+	proto.RegisterType((*TimeSpec)(nil), "format.TimeSpec")
+	proto.RegisterType((*Entry)(nil), "format.Entry")
+	proto.RegisterType((*TOC)(nil), "format.TOC")
+	proto.RegisterMapType((map[string]*Entry)(nil), "format.TOC.PathsEntry")
+	proto.RegisterType((*TOCNode)(nil), "format.TOCNode")
+	proto.RegisterMapType((map[string]*Entry)(nil), "format.TOCNode.PathsEntry")
+	proto.RegisterType((*TOCChild)(nil), "format.TOCChild")
+	proto.RegisterType((*BlockInfo)(nil), "format.BlockInfo")
+	proto.RegisterType((*BlockTOC)(nil), "format.BlockTOC")
+	proto.RegisterType((*PackLocation)(nil), "format.PackLocation")
+	proto.RegisterType((*PackIndex)(nil), "format.PackIndex")
+	proto.RegisterType((*ChunkParams)(nil), "format.ChunkParams")
+	proto.RegisterType((*Config)(nil), "format.Config")
+	proto.RegisterType((*KeyFile)(nil), "format.KeyFile")
+	proto.RegisterType((*KeySlot)(nil), "format.KeySlot")
+	proto.RegisterType((*KeySlots)(nil), "format.KeySlots")
+}
+
+func init() { proto.RegisterFile("format.proto", fileDescriptor_9d9ed1f28583505e) }
+
+var fileDescriptor_9d9ed1f28583505e = []byte{
+	// 1301 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xac, 0x56, 0x4f, 0x93, 0xdb, 0xc4,
+	0x12, 0xb7, 0x2c, 0xcb, 0x6b, 0xb5, 0xed, 0x7d, 0xce, 0xbc, 0xbc, 0x3c, 0xbf, 0xe4, 0xa1, 0x18,
+	0x25, 0xa9, 0x32, 0x21, 0xb5, 0x09, 0x4b, 0x0e, 0xc0, 0x2d, 0x71, 0x58, 0xd8, 0x4a, 0xc2, 0xa6,
+	0xb4, 0x7b, 0xe0, 0x66, 0xc6, 0xd2, 0xd8, 0x9e, 0xb2, 0x34, 0xe3, 0x1a, 0x8d, 0x93, 0x75, 0x8a,
+	0x03, 0x57, 0x6e, 0x7c, 0x0c, 0xce, 0x7c, 0x00, 0xce, 0x1c, 0x73, 0xa2, 0x52, 0x9c, 0x88, 0x53,
+	0x54, 0x51, 0x9c, 0x52, 0x7c, 0x02, 0x6a, 0xfe, 0xc8, 0xb6, 0x76, 0xc9, 0x8d, 0x9b, 0xfa, 0xf7,
+	0xeb, 0xe9, 0xf9, 0xf5, 0x4c, 0x4f, 0xb7, 0xa0, 0x35, 0xe6, 0x22, 0xc3, 0x72, 0x6f, 0x2e, 0xb8,
+	0xe4, 0xa8, 0x6e, 0xac, 0xf0, 0x4f, 0x07, 0xfc, 0x93, 0xa3, 0xc1, 0xe7, 0x04, 0x27, 0x44, 0xa0,
+	0xff, 0x40, 0x7d, 0x46, 0x96, 0x43, 0x9a, 0x74, 0x9d, 0x9e, 0xd3, 0x6f, 0x45, 0xde, 0x8c, 0x2c,
+	0x0f, 0x13, 0x14, 0x00, 0xc4, 0x3c, 0x9b, 0x0b, 0x92, 0xe7, 0x24, 0xe9, 0x56, 0x7b, 0x4e, 0xbf,
+	0x11, 0x6d, 0x21, 0xa8, 0x03, 0x6e, 0xbe, 0xc8, 0xba, 0xae, 0x5e, 0xa3, 0x3e, 0xd1, 0xff, 0xa0,
+	0x21, 0x79, 0x3c, 0xcc, 0xe9, 0x73, 0xd2, 0xad, 0xf5, 0x9c, 0xbe, 0x1b, 0xed, 0x48, 0x1e, 0x1f,
+	0xd3, 0xe7, 0x04, 0x5d, 0x85, 0xe6, 0x28, 0xe5, 0xf1, 0x2c, 0x37, 0xac, 0xa7, 0x59, 0x30, 0x90,
+	0x76, 0xe8, 0x80, 0x9b, 0xe1, 0xb8, 0x5b, 0x37, 0xd1, 0x32, 0x1c, 0xa3, 0xff, 0x83, 0x9f, 0xd3,
+	0x09, 0xc3, 0x72, 0x21, 0x48, 0x77, 0x47, 0xe3, 0x1b, 0x00, 0x5d, 0x82, 0xba, 0x32, 0x88, 0xe8,
+	0x36, 0x34, 0x65, 0x2d, 0xd4, 0x85, 0x9d, 0xa7, 0x44, 0xe4, 0x94, 0xb3, 0xae, 0xdf, 0x73, 0xfa,
+	0xed, 0xa8, 0x30, 0xc3, 0xff, 0x82, 0x77, 0x5f, 0xed, 0x87, 0x76, 0xa1, 0xba, 0xce, 0xb5, 0x4a,
+	0x93, 0xf0, 0x2b, 0x68, 0x68, 0xe2, 0x98, 0x48, 0x74, 0x03, 0xea, 0x46, 0x54, 0xd7, 0xe9, 0xb9,
+	0xfd, 0xe6, 0x7e, 0x7b, 0xcf, 0x1e, 0xa0, 0xf6, 0x88, 0x2c, 0x59, 0xe4, 0x5e, 0xdd, 0xe4, 0x7e,
+	0x05, 0xfc, 0xd1, 0x52, 0x12, 0x93, 0x9e, 0xab, 0xd3, 0x6b, 0x28, 0x40, 0x25, 0x17, 0x1e, 0x40,
+	0xe3, 0x84, 0x66, 0xe4, 0x78, 0x4e, 0x62, 0x25, 0x30, 0x27, 0x31, 0x67, 0x49, 0xae, 0x25, 0xb8,
+	0x51, 0x61, 0xa2, 0x1e, 0x34, 0x19, 0x66, 0xbc, 0x60, 0x55, 0x70, 0x2f, 0xda, 0x86, 0xc2, 0x1f,
+	0xab, 0xe0, 0x7d, 0xca, 0xa4, 0x58, 0x96, 0xb7, 0x73, 0xca, 0xdb, 0xa1, 0x1e, 0xd4, 0xe4, 0x72,
+	0x4e, 0x74, 0x84, 0xdd, 0xfd, 0x56, 0x91, 0xc2, 0xc9, 0x72, 0x4e, 0x22, 0xcd, 0x20, 0x04, 0xb5,
+	0x29, 0xce, 0xa7, 0xf6, 0xf2, 0xf4, 0x37, 0xea, 0xaf, 0x53, 0x57, 0x77, 0xd7, 0xdc, 0xef, 0x94,
+	0x52, 0x3f, 0x26, 0x72, 0x9d, 0xfd, 0x45, 0xf0, 0x16, 0x0c, 0x67, 0xe6, 0x1a, 0xfd, 0xc8, 0x18,
+	0x0a, 0x9d, 0x68, 0xb4, 0x6e, 0xd0, 0x49, 0x81, 0x8e, 0x53, 0x3c, 0xc9, 0xf5, 0x0d, 0x7a, 0x91,
+	0x31, 0xd4, 0xfe, 0x73, 0x22, 0x32, 0x7d, 0x77, 0x5e, 0xa4, 0xbf, 0xd1, 0x6d, 0x80, 0x58, 0x10,
+	0x2c, 0x49, 0x32, 0xc4, 0xb2, 0xeb, 0x97, 0x35, 0x14, 0xc7, 0x17, 0xf9, 0xd6, 0xe7, 0x9e, 0x44,
+	0x1f, 0x40, 0x33, 0xe3, 0x09, 0x1d, 0x53, 0xb3, 0x02, 0xde, 0xb2, 0x02, 0x0a, 0xa7, 0x7b, 0x32,
+	0xfc, 0x1a, 0xdc, 0x93, 0xa3, 0x01, 0xba, 0x05, 0xde, 0x1c, 0xcb, 0x69, 0x71, 0xc9, 0x97, 0xd6,
+	0x6b, 0x8e, 0x06, 0x7b, 0x4f, 0x14, 0xa1, 0x0f, 0x39, 0x32, 0x4e, 0x97, 0x3f, 0x03, 0xd8, 0x80,
+	0xea, 0xea, 0x67, 0x64, 0xa9, 0xcf, 0xdc, 0x8f, 0xd4, 0x27, 0xba, 0x06, 0xde, 0x53, 0x9c, 0x2e,
+	0xcc, 0x79, 0x6f, 0x95, 0x8c, 0x0d, 0xa2, 0xb9, 0x4f, 0xaa, 0x1f, 0x39, 0xe1, 0x0f, 0x0e, 0xec,
+	0x9c, 0x1c, 0x0d, 0xbe, 0xe0, 0x09, 0x41, 0x77, 0xca, 0x12, 0x2e, 0x6f, 0x49, 0x50, 0xfc, 0x79,
+	0x19, 0xe8, 0x16, 0x34, 0xe2, 0x29, 0x4d, 0x13, 0x41, 0x58, 0xb7, 0xda, 0x73, 0x4b, 0xb9, 0x1e,
+	0x0d, 0x06, 0x8a, 0x8a, 0xd6, 0x1e, 0xff, 0x9c, 0x68, 0x55, 0xbb, 0x36, 0xbc, 0xbe, 0x4c, 0x2a,
+	0x72, 0x69, 0x03, 0x19, 0x03, 0x5d, 0x87, 0x1a, 0xe3, 0x49, 0x11, 0xe9, 0x7c, 0xd9, 0x68, 0x36,
+	0x5c, 0x80, 0xaf, 0x91, 0x43, 0x36, 0xe6, 0x67, 0x9f, 0x60, 0xb9, 0x9c, 0xab, 0x67, 0xca, 0xf9,
+	0x0a, 0xf8, 0xaa, 0xed, 0x94, 0x9e, 0x96, 0x02, 0x34, 0x19, 0x00, 0x08, 0x32, 0x26, 0x82, 0xb0,
+	0x98, 0xe4, 0xb6, 0xeb, 0x6c, 0x21, 0xe1, 0x97, 0xf6, 0x71, 0xab, 0x6b, 0x7f, 0xef, 0xcc, 0xe3,
+	0xbe, 0x50, 0x92, 0xaa, 0x84, 0xad, 0x4b, 0xfc, 0x5d, 0x68, 0x8d, 0x52, 0xce, 0xb3, 0xe1, 0x98,
+	0xa6, 0x92, 0x08, 0xfb, 0xd2, 0x9b, 0x1a, 0x3b, 0xd0, 0x50, 0x38, 0x82, 0xd6, 0x13, 0x1c, 0xcf,
+	0x1e, 0xf1, 0x18, 0x4b, 0xca, 0xd9, 0xb9, 0x9c, 0x54, 0x8d, 0xe3, 0x78, 0xa6, 0x97, 0xb6, 0x23,
+	0xfd, 0xad, 0xba, 0x16, 0x1f, 0x8f, 0x73, 0x22, 0x6d, 0x1e, 0xd6, 0x52, 0x78, 0x4a, 0xd8, 0x44,
+	0x4e, 0x6d, 0x06, 0xd6, 0x0a, 0x3f, 0x06, 0x5f, 0xed, 0x71, 0xc8, 0x12, 0x72, 0x8a, 0x6e, 0x9d,
+	0x91, 0x7f, 0xb1, 0x90, 0xbf, 0x2d, 0xa3, 0xc8, 0x20, 0xfc, 0xc5, 0x81, 0xe6, 0x60, 0xba, 0x60,
+	0xb3, 0x27, 0x58, 0xe0, 0x2c, 0x57, 0x5b, 0x3c, 0xa3, 0x2c, 0xe1, 0xcf, 0xb4, 0x44, 0x2f, 0xb2,
+	0x16, 0xba, 0x06, 0x6d, 0xfc, 0x94, 0x08, 0x3c, 0x21, 0x43, 0xbd, 0xd2, 0xf6, 0x9d, 0x96, 0x05,
+	0x4d, 0xcb, 0xbc, 0x02, 0x7e, 0x46, 0x99, 0x75, 0x70, 0xb5, 0x43, 0x23, 0xa3, 0x6c, 0x43, 0xe2,
+	0x53, 0x4b, 0xd6, 0x2c, 0x89, 0x4f, 0x0d, 0x19, 0x00, 0xcc, 0x79, 0xba, 0x64, 0x3c, 0xa3, 0x38,
+	0xd5, 0x0d, 0xa3, 0x16, 0x6d, 0x21, 0xe8, 0x2e, 0xf8, 0x38, 0x9d, 0x70, 0x41, 0xe5, 0x34, 0xd3,
+	0x9d, 0x63, 0x77, 0xf3, 0x1c, 0xb5, 0xfc, 0x7b, 0x05, 0x1b, 0x6d, 0x1c, 0xc3, 0xdf, 0x1c, 0xa8,
+	0x0f, 0x38, 0x1b, 0xd3, 0x09, 0xba, 0xad, 0x9e, 0xc5, 0x82, 0xcd, 0x28, 0x9b, 0xe8, 0xcc, 0x9a,
+	0xfb, 0xff, 0x2e, 0xad, 0x37, 0xe9, 0x47, 0x6b, 0xa7, 0xf5, 0x28, 0x1a, 0x8e, 0x85, 0xea, 0x56,
+	0xe6, 0x7a, 0xcc, 0x28, 0x3a, 0x50, 0x08, 0x7a, 0x1f, 0x2e, 0x3c, 0xcf, 0x65, 0x32, 0x4c, 0x68,
+	0xac, 0x4e, 0x14, 0x0b, 0x4a, 0xf2, 0xae, 0xdb, 0x73, 0xfb, 0xad, 0xa8, 0xa3, 0x88, 0x07, 0x5b,
+	0x38, 0xba, 0x0e, 0x6d, 0xbc, 0x90, 0x53, 0xc2, 0x24, 0x8d, 0x55, 0x5f, 0xd2, 0x07, 0xd0, 0x88,
+	0xca, 0xe0, 0xf6, 0x54, 0xf2, 0x4a, 0x53, 0x49, 0x4d, 0xb9, 0xc5, 0x7c, 0x22, 0x70, 0xa2, 0xf4,
+	0xd7, 0xf5, 0xda, 0x0d, 0x10, 0xfe, 0xe1, 0xc0, 0xce, 0x43, 0xb2, 0x3c, 0xa0, 0x29, 0xd9, 0x8e,
+	0xe1, 0x94, 0x63, 0x6c, 0x06, 0x78, 0x75, 0x7b, 0x80, 0x23, 0xa8, 0xe5, 0x38, 0x95, 0x45, 0x93,
+	0x57, 0xdf, 0xe8, 0x1d, 0x00, 0x2c, 0x26, 0x9c, 0x0d, 0x25, 0xcd, 0xcc, 0x90, 0x6e, 0x47, 0xbe,
+	0x46, 0x54, 0xbf, 0x54, 0x65, 0x6f, 0xe8, 0x8c, 0x64, 0x5c, 0x2c, 0xad, 0xd8, 0xa6, 0xc6, 0x1e,
+	0x6b, 0x48, 0xd7, 0x8b, 0x89, 0x30, 0x15, 0x04, 0x27, 0xb9, 0x16, 0xdd, 0x8e, 0xcc, 0xba, 0x13,
+	0x83, 0xa9, 0x46, 0xc1, 0x38, 0x8b, 0x8b, 0xb9, 0x6d, 0x0c, 0xb5, 0x79, 0x4e, 0x70, 0x4a, 0x92,
+	0xa1, 0x6a, 0x46, 0x0d, 0x3b, 0xd2, 0x35, 0xf2, 0x90, 0x2c, 0xc3, 0x6f, 0x4d, 0xb2, 0xc7, 0x29,
+	0x97, 0x6f, 0xfb, 0x27, 0x41, 0x50, 0x63, 0xc5, 0xa5, 0xf9, 0x91, 0xfe, 0x56, 0x27, 0x48, 0xe6,
+	0x53, 0x92, 0x11, 0x81, 0x53, 0x9b, 0xeb, 0x06, 0xd8, 0x28, 0xa9, 0x6d, 0x2b, 0xb9, 0x0a, 0xcd,
+	0x67, 0x02, 0xcf, 0xe7, 0x56, 0x8a, 0xa7, 0x39, 0xb0, 0x90, 0xd2, 0xf2, 0xb3, 0x03, 0x0d, 0xab,
+	0x25, 0x37, 0x05, 0x9e, 0x4b, 0x22, 0x36, 0x7a, 0x1a, 0x06, 0x38, 0x4c, 0xd0, 0x0d, 0xf0, 0x72,
+	0xe5, 0x65, 0x7b, 0xf2, 0xbf, 0x8a, 0xe2, 0xb3, 0xab, 0x23, 0xc3, 0xa2, 0xeb, 0xb0, 0xcb, 0xc8,
+	0xa9, 0x1c, 0x6e, 0x02, 0x19, 0xa9, 0x2d, 0x85, 0x3e, 0x2e, 0x82, 0xdd, 0x81, 0xe6, 0x96, 0x97,
+	0x1d, 0xc4, 0xe7, 0x42, 0xc2, 0x66, 0x0d, 0xda, 0x03, 0x6d, 0x0d, 0x8d, 0x06, 0xef, 0xef, 0x35,
+	0xf8, 0xca, 0x45, 0xe7, 0x72, 0x73, 0x1f, 0x6a, 0xea, 0x3f, 0x00, 0xb5, 0xc1, 0x3f, 0xe1, 0xd9,
+	0xe8, 0x58, 0x72, 0x46, 0x3a, 0x15, 0xd4, 0x80, 0x9a, 0x2a, 0xb2, 0x8e, 0x83, 0x76, 0xc0, 0x7d,
+	0x40, 0x45, 0xa7, 0xaa, 0xa0, 0x47, 0x94, 0xcd, 0x3a, 0xee, 0xcd, 0x3e, 0xec, 0x96, 0x9f, 0x22,
+	0xf2, 0xc1, 0x8b, 0xf0, 0x88, 0xb2, 0x4e, 0x05, 0x35, 0x61, 0xe7, 0x00, 0xe7, 0x72, 0xf0, 0x60,
+	0xd0, 0x71, 0xee, 0xdf, 0x7d, 0xf1, 0x2a, 0xa8, 0xbc, 0x7c, 0x15, 0x54, 0xde, 0xbc, 0x0a, 0x9c,
+	0x6f, 0x56, 0x81, 0xf3, 0xfd, 0x2a, 0x70, 0x7e, 0x5a, 0x05, 0xce, 0x8b, 0x55, 0xe0, 0xfc, 0xba,
+	0x0a, 0x9c, 0xdf, 0x57, 0x41, 0xe5, 0xcd, 0x2a, 0x70, 0xbe, 0x7b, 0x1d, 0x54, 0x5e, 0xbc, 0x0e,
+	0x2a, 0x2f, 0x5f, 0x07, 0x95, 0x51, 0x5d, 0xff, 0x9d, 0x7e, 0xf8, 0xd7, 0x00, 0xc8, 0x1f, 0x90,
+	0xdd, 0xad, 0x0a, 0x00, 0x00,
+}
+
+func (x Type) String() string {
+	s, ok := Type_name[int32(x)]
+	if ok {
+		return s
+	}
+	return strconv.Itoa(int(x))
+}
+func (x ChunkAlgorithm) String() string {
+	s, ok := ChunkAlgorithm_name[int32(x)]
+	if ok {
+		return s
+	}
+	return strconv.Itoa(int(x))
+}
+func (this *TOCHeader) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*TOCHeader)
 	if !ok {
-		that2, ok := that.(TOC)
+		that2, ok := that.(TOCHeader)
 		if ok {
 			that1 = &that2
 		} else {
@@ -436,34 +1388,47 @@ func (this *TOC) Equal(that interface{}) bool {
 		}
 	}
 	if that1 == nil {
-		if this == nil {
-			return true
-		}
-		return false
+		return this == nil
 	} else if this == nil {
 		return false
 	}
-	if len(this.Paths) != len(that1.Paths) {
+	if !bytes.Equal(this.KeyId, that1.KeyId) {
 		return false
 	}
-	for i := range this.Paths {
-		if !this.Paths[i].Equal(that1.Paths[i]) {
-			return false
-		}
+	if this.Compressed != that1.Compressed {
+		return false
+	}
+	if !bytes.Equal(this.Sum, that1.Sum) {
+		return false
+	}
+	if this.TocSize != that1.TocSize {
+		return false
+	}
+	if this.BlocksSize != that1.BlocksSize {
+		return false
+	}
+	if !bytes.Equal(this.Mac, that1.Mac) {
+		return false
+	}
+	if !bytes.Equal(this.Signature, that1.Signature) {
+		return false
+	}
+	if !bytes.Equal(this.Signer, that1.Signer) {
+		return false
+	}
+	if this.Version != that1.Version {
+		return false
 	}
 	return true
 }
-func (this *BlockInfo) Equal(that interface{}) bool {
+func (this *Block) Equal(that interface{}) bool {
 	if that == nil {
-		if this == nil {
-			return true
-		}
-		return false
+		return this == nil
 	}
 
-	that1, ok := that.(*BlockInfo)
+	that1, ok := that.(*Block)
 	if !ok {
-		that2, ok := that.(BlockInfo)
+		that2, ok := that.(Block)
 		if ok {
 			that1 = &that2
 		} else {
@@ -471,38 +1436,23 @@ func (this *BlockInfo) Equal(that interface{}) bool {
 		}
 	}
 	if that1 == nil {
-		if this == nil {
-			return true
-		}
-		return false
+		return this == nil
 	} else if this == nil {
 		return false
 	}
 	if !bytes.Equal(this.Id, that1.Id) {
 		return false
 	}
-	if this.ByteSize != that1.ByteSize {
-		return false
-	}
-	if this.CompSize != that1.CompSize {
-		return false
-	}
-	if this.References != that1.References {
-		return false
-	}
 	return true
 }
-func (this *BlockTOC) Equal(that interface{}) bool {
+func (this *BlockSet) Equal(that interface{}) bool {
 	if that == nil {
-		if this == nil {
-			return true
-		}
-		return false
+		return this == nil
 	}
 
-	that1, ok := that.(*BlockTOC)
+	that1, ok := that.(*BlockSet)
 	if !ok {
-		that2, ok := that.(BlockTOC)
+		that2, ok := that.(BlockSet)
 		if ok {
 			that1 = &that2
 		} else {
@@ -510,10 +1460,7 @@ func (this *BlockTOC) Equal(that interface{}) bool {
 		}
 	}
 	if that1 == nil {
-		if this == nil {
-			return true
-		}
-		return false
+		return this == nil
 	} else if this == nil {
 		return false
 	}
@@ -525,779 +1472,606 @@ func (this *BlockTOC) Equal(that interface{}) bool {
 			return false
 		}
 	}
-	if !bytes.Equal(this.BloomFilter, that1.BloomFilter) {
+	if !bytes.Equal(this.Sum, that1.Sum) {
+		return false
+	}
+	if this.ByteSize != that1.ByteSize {
 		return false
 	}
 	return true
 }
-func (this *TOCHeader) GoString() string {
-	if this == nil {
-		return "nil"
+func (this *TimeSpec) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
 	}
-	s := make([]string, 0, 9)
-	s = append(s, "&format.TOCHeader{")
-	s = append(s, "KeyId: "+fmt.Sprintf("%#v", this.KeyId)+",\n")
-	s = append(s, "Compressed: "+fmt.Sprintf("%#v", this.Compressed)+",\n")
-	s = append(s, "Sum: "+fmt.Sprintf("%#v", this.Sum)+",\n")
-	s = append(s, "TocSize: "+fmt.Sprintf("%#v", this.TocSize)+",\n")
-	s = append(s, "BlocksSize: "+fmt.Sprintf("%#v", this.BlocksSize)+",\n")
-	s = append(s, "}")
-	return strings.Join(s, "")
-}
-func (this *Block) GoString() string {
-	if this == nil {
-		return "nil"
+
+	that1, ok := that.(*TimeSpec)
+	if !ok {
+		that2, ok := that.(TimeSpec)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
 	}
-	s := make([]string, 0, 5)
-	s = append(s, "&format.Block{")
-	s = append(s, "Id: "+fmt.Sprintf("%#v", this.Id)+",\n")
-	s = append(s, "}")
-	return strings.Join(s, "")
-}
-func (this *BlockSet) GoString() string {
-	if this == nil {
-		return "nil"
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
 	}
-	s := make([]string, 0, 7)
-	s = append(s, "&format.BlockSet{")
-	if this.Blocks != nil {
-		s = append(s, "Blocks: "+fmt.Sprintf("%#v", this.Blocks)+",\n")
+	if this.Seconds != that1.Seconds {
+		return false
 	}
-	s = append(s, "Sum: "+fmt.Sprintf("%#v", this.Sum)+",\n")
-	s = append(s, "ByteSize: "+fmt.Sprintf("%#v", this.ByteSize)+",\n")
-	s = append(s, "}")
-	return strings.Join(s, "")
+	if this.Nanoseconds != that1.Nanoseconds {
+		return false
+	}
+	return true
 }
-func (this *TimeSpec) GoString() string {
-	if this == nil {
-		return "nil"
+func (this *Entry) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
 	}
-	s := make([]string, 0, 6)
-	s = append(s, "&format.TimeSpec{")
-	s = append(s, "Seconds: "+fmt.Sprintf("%#v", this.Seconds)+",\n")
-	s = append(s, "Nanoseconds: "+fmt.Sprintf("%#v", this.Nanoseconds)+",\n")
-	s = append(s, "}")
-	return strings.Join(s, "")
-}
-func (this *Entry) GoString() string {
-	if this == nil {
-		return "nil"
+
+	that1, ok := that.(*Entry)
+	if !ok {
+		that2, ok := that.(Entry)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
 	}
-	s := make([]string, 0, 14)
-	s = append(s, "&format.Entry{")
-	s = append(s, "ByteSize: "+fmt.Sprintf("%#v", this.ByteSize)+",\n")
-	s = append(s, "Type: "+fmt.Sprintf("%#v", this.Type)+",\n")
-	s = append(s, "Hash: "+fmt.Sprintf("%#v", this.Hash)+",\n")
-	if this.Blocks != nil {
-		s = append(s, "Blocks: "+fmt.Sprintf("%#v", this.Blocks)+",\n")
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
 	}
-	s = append(s, "Uname: "+fmt.Sprintf("%#v", this.Uname)+",\n")
-	s = append(s, "Gname: "+fmt.Sprintf("%#v", this.Gname)+",\n")
-	s = append(s, "Flags: "+fmt.Sprintf("%#v", this.Flags)+",\n")
-	s = append(s, "Perm: "+fmt.Sprintf("%#v", this.Perm)+",\n")
-	if this.CreatedAt != nil {
-		s = append(s, "CreatedAt: "+fmt.Sprintf("%#v", this.CreatedAt)+",\n")
+	if this.ByteSize != that1.ByteSize {
+		return false
 	}
-	if this.ModifiedAt != nil {
-		s = append(s, "ModifiedAt: "+fmt.Sprintf("%#v", this.ModifiedAt)+",\n")
+	if this.Type != that1.Type {
+		return false
 	}
-	s = append(s, "}")
-	return strings.Join(s, "")
-}
-func (this *TOC) GoString() string {
-	if this == nil {
-		return "nil"
+	if !bytes.Equal(this.Hash, that1.Hash) {
+		return false
 	}
-	s := make([]string, 0, 5)
-	s = append(s, "&format.TOC{")
-	keysForPaths := make([]string, 0, len(this.Paths))
-	for k, _ := range this.Paths {
-		keysForPaths = append(keysForPaths, k)
+	if !this.Blocks.Equal(that1.Blocks) {
+		return false
 	}
-	github_com_gogo_protobuf_sortkeys.Strings(keysForPaths)
-	mapStringForPaths := "map[string]*Entry{"
-	for _, k := range keysForPaths {
-		mapStringForPaths += fmt.Sprintf("%#v: %#v,", k, this.Paths[k])
+	if this.Uname != that1.Uname {
+		return false
 	}
-	mapStringForPaths += "}"
-	if this.Paths != nil {
-		s = append(s, "Paths: "+mapStringForPaths+",\n")
+	if this.Gname != that1.Gname {
+		return false
 	}
-	s = append(s, "}")
-	return strings.Join(s, "")
-}
-func (this *BlockInfo) GoString() string {
-	if this == nil {
-		return "nil"
+	if this.Flags != that1.Flags {
+		return false
 	}
-	s := make([]string, 0, 8)
-	s = append(s, "&format.BlockInfo{")
-	s = append(s, "Id: "+fmt.Sprintf("%#v", this.Id)+",\n")
-	s = append(s, "ByteSize: "+fmt.Sprintf("%#v", this.ByteSize)+",\n")
-	s = append(s, "CompSize: "+fmt.Sprintf("%#v", this.CompSize)+",\n")
-	s = append(s, "References: "+fmt.Sprintf("%#v", this.References)+",\n")
-	s = append(s, "}")
-	return strings.Join(s, "")
-}
-func (this *BlockTOC) GoString() string {
-	if this == nil {
-		return "nil"
+	if this.Perm != that1.Perm {
+		return false
 	}
-	s := make([]string, 0, 6)
-	s = append(s, "&format.BlockTOC{")
-	if this.Blocks != nil {
-		s = append(s, "Blocks: "+fmt.Sprintf("%#v", this.Blocks)+",\n")
+	if !this.CreatedAt.Equal(that1.CreatedAt) {
+		return false
 	}
-	s = append(s, "BloomFilter: "+fmt.Sprintf("%#v", this.BloomFilter)+",\n")
-	s = append(s, "}")
-	return strings.Join(s, "")
-}
-func valueToGoStringFormat(v interface{}, typ string) string {
-	rv := reflect.ValueOf(v)
-	if rv.IsNil() {
-		return "nil"
+	if !this.ModifiedAt.Equal(that1.ModifiedAt) {
+		return false
 	}
-	pv := reflect.Indirect(rv).Interface()
-	return fmt.Sprintf("func(v %v) *%v { return &v } ( %#v )", typ, typ, pv)
+	return true
 }
-func extensionToGoStringFormat(m github_com_gogo_protobuf_proto.Message) string {
-	e := github_com_gogo_protobuf_proto.GetUnsafeExtensionsMap(m)
-	if e == nil {
-		return "nil"
+func (this *TOC) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
 	}
-	s := "proto.NewUnsafeXXX_InternalExtensions(map[int32]proto.Extension{"
-	keys := make([]int, 0, len(e))
-	for k := range e {
-		keys = append(keys, int(k))
+
+	that1, ok := that.(*TOC)
+	if !ok {
+		that2, ok := that.(TOC)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
 	}
-	sort.Ints(keys)
-	ss := []string{}
-	for _, k := range keys {
-		ss = append(ss, strconv.Itoa(k)+": "+e[int32(k)].GoString())
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
 	}
-	s += strings.Join(ss, ",") + "})"
-	return s
-}
-func (m *TOCHeader) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalTo(dAtA)
-	if err != nil {
-		return nil, err
+	if len(this.Paths) != len(that1.Paths) {
+		return false
 	}
-	return dAtA[:n], nil
+	for i := range this.Paths {
+		if !this.Paths[i].Equal(that1.Paths[i]) {
+			return false
+		}
+	}
+	return true
 }
-
-func (m *TOCHeader) MarshalTo(dAtA []byte) (int, error) {
-	var i int
-	_ = i
-	var l int
-	_ = l
-	if len(m.KeyId) > 0 {
-		dAtA[i] = 0xa
-		i++
-		i = encodeVarintFormat(dAtA, i, uint64(len(m.KeyId)))
-		i += copy(dAtA[i:], m.KeyId)
+func (this *TOCNode) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
 	}
-	if m.Compressed {
-		dAtA[i] = 0x10
-		i++
-		if m.Compressed {
-			dAtA[i] = 1
+
+	that1, ok := that.(*TOCNode)
+	if !ok {
+		that2, ok := that.(TOCNode)
+		if ok {
+			that1 = &that2
 		} else {
-			dAtA[i] = 0
+			return false
 		}
-		i++
 	}
-	if len(m.Sum) > 0 {
-		dAtA[i] = 0x1a
-		i++
-		i = encodeVarintFormat(dAtA, i, uint64(len(m.Sum)))
-		i += copy(dAtA[i:], m.Sum)
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
 	}
-	if m.TocSize != 0 {
-		dAtA[i] = 0x20
-		i++
-		i = encodeVarintFormat(dAtA, i, uint64(m.TocSize))
+	if len(this.Paths) != len(that1.Paths) {
+		return false
 	}
-	if m.BlocksSize != 0 {
-		dAtA[i] = 0x28
-		i++
-		i = encodeVarintFormat(dAtA, i, uint64(m.BlocksSize))
+	for i := range this.Paths {
+		if !this.Paths[i].Equal(that1.Paths[i]) {
+			return false
+		}
 	}
-	return i, nil
-}
-
-func (m *Block) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalTo(dAtA)
-	if err != nil {
-		return nil, err
+	if len(this.Children) != len(that1.Children) {
+		return false
 	}
-	return dAtA[:n], nil
-}
-
-func (m *Block) MarshalTo(dAtA []byte) (int, error) {
-	var i int
-	_ = i
-	var l int
-	_ = l
-	if len(m.Id) > 0 {
-		dAtA[i] = 0xa
-		i++
-		i = encodeVarintFormat(dAtA, i, uint64(len(m.Id)))
-		i += copy(dAtA[i:], m.Id)
+	for i := range this.Children {
+		if !this.Children[i].Equal(that1.Children[i]) {
+			return false
+		}
 	}
-	return i, nil
+	return true
 }
-
-func (m *BlockSet) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalTo(dAtA)
-	if err != nil {
-		return nil, err
+func (this *TOCChild) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
 	}
-	return dAtA[:n], nil
-}
 
-func (m *BlockSet) MarshalTo(dAtA []byte) (int, error) {
-	var i int
-	_ = i
-	var l int
-	_ = l
-	if len(m.Blocks) > 0 {
-		for _, msg := range m.Blocks {
-			dAtA[i] = 0xa
-			i++
-			i = encodeVarintFormat(dAtA, i, uint64(msg.Size()))
-			n, err := msg.MarshalTo(dAtA[i:])
-			if err != nil {
-				return 0, err
-			}
-			i += n
+	that1, ok := that.(*TOCChild)
+	if !ok {
+		that2, ok := that.(TOCChild)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
 		}
 	}
-	if len(m.Sum) > 0 {
-		dAtA[i] = 0x12
-		i++
-		i = encodeVarintFormat(dAtA, i, uint64(len(m.Sum)))
-		i += copy(dAtA[i:], m.Sum)
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
 	}
-	if m.ByteSize != 0 {
-		dAtA[i] = 0x18
-		i++
-		i = encodeVarintFormat(dAtA, i, uint64(m.ByteSize))
+	if this.First != that1.First {
+		return false
 	}
-	return i, nil
-}
-
-func (m *TimeSpec) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalTo(dAtA)
-	if err != nil {
-		return nil, err
+	if !this.Node.Equal(that1.Node) {
+		return false
 	}
-	return dAtA[:n], nil
+	return true
 }
-
-func (m *TimeSpec) MarshalTo(dAtA []byte) (int, error) {
-	var i int
-	_ = i
-	var l int
-	_ = l
-	if m.Seconds != 0 {
-		dAtA[i] = 0x8
-		i++
-		i = encodeVarintFormat(dAtA, i, uint64(m.Seconds))
-	}
-	if m.Nanoseconds != 0 {
-		dAtA[i] = 0x10
-		i++
-		i = encodeVarintFormat(dAtA, i, uint64(m.Nanoseconds))
+func (this *BlockInfo) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
 	}
-	return i, nil
-}
 
-func (m *Entry) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalTo(dAtA)
-	if err != nil {
-		return nil, err
+	that1, ok := that.(*BlockInfo)
+	if !ok {
+		that2, ok := that.(BlockInfo)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
 	}
-	return dAtA[:n], nil
-}
-
-func (m *Entry) MarshalTo(dAtA []byte) (int, error) {
-	var i int
-	_ = i
-	var l int
-	_ = l
-	if m.ByteSize != 0 {
-		dAtA[i] = 0x8
-		i++
-		i = encodeVarintFormat(dAtA, i, uint64(m.ByteSize))
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
 	}
-	if m.Type != 0 {
-		dAtA[i] = 0x10
-		i++
-		i = encodeVarintFormat(dAtA, i, uint64(m.Type))
+	if !bytes.Equal(this.Id, that1.Id) {
+		return false
 	}
-	if len(m.Hash) > 0 {
-		dAtA[i] = 0x1a
-		i++
-		i = encodeVarintFormat(dAtA, i, uint64(len(m.Hash)))
-		i += copy(dAtA[i:], m.Hash)
+	if this.ByteSize != that1.ByteSize {
+		return false
 	}
-	if m.Blocks != nil {
-		dAtA[i] = 0x22
-		i++
-		i = encodeVarintFormat(dAtA, i, uint64(m.Blocks.Size()))
-		n1, err := m.Blocks.MarshalTo(dAtA[i:])
-		if err != nil {
-			return 0, err
-		}
-		i += n1
+	if this.CompSize != that1.CompSize {
+		return false
 	}
-	if len(m.Uname) > 0 {
-		dAtA[i] = 0x2a
-		i++
-		i = encodeVarintFormat(dAtA, i, uint64(len(m.Uname)))
-		i += copy(dAtA[i:], m.Uname)
+	if this.References != that1.References {
+		return false
 	}
-	if len(m.Gname) > 0 {
-		dAtA[i] = 0x32
-		i++
-		i = encodeVarintFormat(dAtA, i, uint64(len(m.Gname)))
-		i += copy(dAtA[i:], m.Gname)
+	return true
+}
+func (this *BlockTOC) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
 	}
-	if m.Flags != 0 {
-		dAtA[i] = 0x38
-		i++
-		i = encodeVarintFormat(dAtA, i, uint64(m.Flags))
+
+	that1, ok := that.(*BlockTOC)
+	if !ok {
+		that2, ok := that.(BlockTOC)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
 	}
-	if m.Perm != 0 {
-		dAtA[i] = 0x40
-		i++
-		i = encodeVarintFormat(dAtA, i, uint64(m.Perm))
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
 	}
-	if m.CreatedAt != nil {
-		dAtA[i] = 0x4a
-		i++
-		i = encodeVarintFormat(dAtA, i, uint64(m.CreatedAt.Size()))
-		n2, err := m.CreatedAt.MarshalTo(dAtA[i:])
-		if err != nil {
-			return 0, err
-		}
-		i += n2
+	if len(this.Blocks) != len(that1.Blocks) {
+		return false
 	}
-	if m.ModifiedAt != nil {
-		dAtA[i] = 0x52
-		i++
-		i = encodeVarintFormat(dAtA, i, uint64(m.ModifiedAt.Size()))
-		n3, err := m.ModifiedAt.MarshalTo(dAtA[i:])
-		if err != nil {
-			return 0, err
+	for i := range this.Blocks {
+		if !this.Blocks[i].Equal(that1.Blocks[i]) {
+			return false
 		}
-		i += n3
 	}
-	return i, nil
-}
-
-func (m *TOC) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalTo(dAtA)
-	if err != nil {
-		return nil, err
+	if !bytes.Equal(this.BloomFilter, that1.BloomFilter) {
+		return false
 	}
-	return dAtA[:n], nil
+	return true
 }
+func (this *PackLocation) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
 
-func (m *TOC) MarshalTo(dAtA []byte) (int, error) {
-	var i int
-	_ = i
-	var l int
-	_ = l
-	if len(m.Paths) > 0 {
-		for k, _ := range m.Paths {
-			dAtA[i] = 0xa
-			i++
-			v := m.Paths[k]
-			msgSize := 0
-			if v != nil {
-				msgSize = v.Size()
-				msgSize += 1 + sovFormat(uint64(msgSize))
-			}
-			mapSize := 1 + len(k) + sovFormat(uint64(len(k))) + msgSize
-			i = encodeVarintFormat(dAtA, i, uint64(mapSize))
-			dAtA[i] = 0xa
-			i++
-			i = encodeVarintFormat(dAtA, i, uint64(len(k)))
-			i += copy(dAtA[i:], k)
-			if v != nil {
-				dAtA[i] = 0x12
-				i++
-				i = encodeVarintFormat(dAtA, i, uint64(v.Size()))
-				n4, err := v.MarshalTo(dAtA[i:])
-				if err != nil {
-					return 0, err
-				}
-				i += n4
-			}
+	that1, ok := that.(*PackLocation)
+	if !ok {
+		that2, ok := that.(PackLocation)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
 		}
 	}
-	return i, nil
-}
-
-func (m *BlockInfo) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalTo(dAtA)
-	if err != nil {
-		return nil, err
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
 	}
-	return dAtA[:n], nil
-}
-
-func (m *BlockInfo) MarshalTo(dAtA []byte) (int, error) {
-	var i int
-	_ = i
-	var l int
-	_ = l
-	if len(m.Id) > 0 {
-		dAtA[i] = 0xa
-		i++
-		i = encodeVarintFormat(dAtA, i, uint64(len(m.Id)))
-		i += copy(dAtA[i:], m.Id)
+	if !bytes.Equal(this.Id, that1.Id) {
+		return false
 	}
-	if m.ByteSize != 0 {
-		dAtA[i] = 0x10
-		i++
-		i = encodeVarintFormat(dAtA, i, uint64(m.ByteSize))
+	if this.Pack != that1.Pack {
+		return false
 	}
-	if m.CompSize != 0 {
-		dAtA[i] = 0x18
-		i++
-		i = encodeVarintFormat(dAtA, i, uint64(m.CompSize))
+	if this.Offset != that1.Offset {
+		return false
 	}
-	if m.References != 0 {
-		dAtA[i] = 0x20
-		i++
-		i = encodeVarintFormat(dAtA, i, uint64(m.References))
+	if this.Length != that1.Length {
+		return false
 	}
-	return i, nil
+	return true
 }
-
-func (m *BlockTOC) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalTo(dAtA)
-	if err != nil {
-		return nil, err
+func (this *PackIndex) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
 	}
-	return dAtA[:n], nil
-}
 
-func (m *BlockTOC) MarshalTo(dAtA []byte) (int, error) {
-	var i int
-	_ = i
-	var l int
-	_ = l
-	if len(m.Blocks) > 0 {
-		for _, msg := range m.Blocks {
-			dAtA[i] = 0xa
-			i++
-			i = encodeVarintFormat(dAtA, i, uint64(msg.Size()))
-			n, err := msg.MarshalTo(dAtA[i:])
-			if err != nil {
-				return 0, err
-			}
-			i += n
+	that1, ok := that.(*PackIndex)
+	if !ok {
+		that2, ok := that.(PackIndex)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
 		}
 	}
-	if len(m.BloomFilter) > 0 {
-		dAtA[i] = 0x12
-		i++
-		i = encodeVarintFormat(dAtA, i, uint64(len(m.BloomFilter)))
-		i += copy(dAtA[i:], m.BloomFilter)
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if len(this.Blocks) != len(that1.Blocks) {
+		return false
 	}
-	return i, nil
+	for i := range this.Blocks {
+		if !this.Blocks[i].Equal(that1.Blocks[i]) {
+			return false
+		}
+	}
+	return true
 }
+func (this *ChunkParams) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
 
-func encodeFixed64Format(dAtA []byte, offset int, v uint64) int {
-	dAtA[offset] = uint8(v)
-	dAtA[offset+1] = uint8(v >> 8)
-	dAtA[offset+2] = uint8(v >> 16)
-	dAtA[offset+3] = uint8(v >> 24)
-	dAtA[offset+4] = uint8(v >> 32)
-	dAtA[offset+5] = uint8(v >> 40)
-	dAtA[offset+6] = uint8(v >> 48)
-	dAtA[offset+7] = uint8(v >> 56)
-	return offset + 8
-}
-func encodeFixed32Format(dAtA []byte, offset int, v uint32) int {
-	dAtA[offset] = uint8(v)
-	dAtA[offset+1] = uint8(v >> 8)
-	dAtA[offset+2] = uint8(v >> 16)
-	dAtA[offset+3] = uint8(v >> 24)
-	return offset + 4
-}
-func encodeVarintFormat(dAtA []byte, offset int, v uint64) int {
-	for v >= 1<<7 {
-		dAtA[offset] = uint8(v&0x7f | 0x80)
-		v >>= 7
-		offset++
+	that1, ok := that.(*ChunkParams)
+	if !ok {
+		that2, ok := that.(ChunkParams)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
 	}
-	dAtA[offset] = uint8(v)
-	return offset + 1
-}
-func (m *TOCHeader) Size() (n int) {
-	var l int
-	_ = l
-	l = len(m.KeyId)
-	if l > 0 {
-		n += 1 + l + sovFormat(uint64(l))
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
 	}
-	if m.Compressed {
-		n += 2
+	if this.Window != that1.Window {
+		return false
 	}
-	l = len(m.Sum)
-	if l > 0 {
-		n += 1 + l + sovFormat(uint64(l))
+	if this.AverageBlock != that1.AverageBlock {
+		return false
 	}
-	if m.TocSize != 0 {
-		n += 1 + sovFormat(uint64(m.TocSize))
+	if this.MinBlock != that1.MinBlock {
+		return false
 	}
-	if m.BlocksSize != 0 {
-		n += 1 + sovFormat(uint64(m.BlocksSize))
+	if this.MaxBlock != that1.MaxBlock {
+		return false
 	}
-	return n
-}
-
-func (m *Block) Size() (n int) {
-	var l int
-	_ = l
-	l = len(m.Id)
-	if l > 0 {
-		n += 1 + l + sovFormat(uint64(l))
+	if this.Polynomial != that1.Polynomial {
+		return false
 	}
-	return n
+	if this.Algorithm != that1.Algorithm {
+		return false
+	}
+	return true
 }
-
-func (m *BlockSet) Size() (n int) {
-	var l int
-	_ = l
-	if len(m.Blocks) > 0 {
-		for _, e := range m.Blocks {
-			l = e.Size()
-			n += 1 + l + sovFormat(uint64(l))
-		}
+func (this *Config) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
 	}
-	l = len(m.Sum)
-	if l > 0 {
-		n += 1 + l + sovFormat(uint64(l))
+
+	that1, ok := that.(*Config)
+	if !ok {
+		that2, ok := that.(Config)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
 	}
-	if m.ByteSize != 0 {
-		n += 1 + sovFormat(uint64(m.ByteSize))
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
 	}
-	return n
-}
-
-func (m *TimeSpec) Size() (n int) {
-	var l int
-	_ = l
-	if m.Seconds != 0 {
-		n += 1 + sovFormat(uint64(m.Seconds))
+	if !this.Chunking.Equal(that1.Chunking) {
+		return false
 	}
-	if m.Nanoseconds != 0 {
-		n += 1 + sovFormat(uint64(m.Nanoseconds))
+	if this.BlockFrame != that1.BlockFrame {
+		return false
 	}
-	return n
+	if len(this.ZstdDictionaries) != len(that1.ZstdDictionaries) {
+		return false
+	}
+	for i := range this.ZstdDictionaries {
+		if !bytes.Equal(this.ZstdDictionaries[i], that1.ZstdDictionaries[i]) {
+			return false
+		}
+	}
+	if this.Authenticated != that1.Authenticated {
+		return false
+	}
+	if this.Version != that1.Version {
+		return false
+	}
+	if this.Upgrading != that1.Upgrading {
+		return false
+	}
+	return true
 }
+func (this *KeyFile) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
 
-func (m *Entry) Size() (n int) {
-	var l int
-	_ = l
-	if m.ByteSize != 0 {
-		n += 1 + sovFormat(uint64(m.ByteSize))
+	that1, ok := that.(*KeyFile)
+	if !ok {
+		that2, ok := that.(KeyFile)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
 	}
-	if m.Type != 0 {
-		n += 1 + sovFormat(uint64(m.Type))
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
 	}
-	l = len(m.Hash)
-	if l > 0 {
-		n += 1 + l + sovFormat(uint64(l))
+	if this.Version != that1.Version {
+		return false
 	}
-	if m.Blocks != nil {
-		l = m.Blocks.Size()
-		n += 1 + l + sovFormat(uint64(l))
+	if !bytes.Equal(this.KeyId, that1.KeyId) {
+		return false
 	}
-	l = len(m.Uname)
-	if l > 0 {
-		n += 1 + l + sovFormat(uint64(l))
+	if !bytes.Equal(this.Salt, that1.Salt) {
+		return false
 	}
-	l = len(m.Gname)
-	if l > 0 {
-		n += 1 + l + sovFormat(uint64(l))
+	if this.ArgonTime != that1.ArgonTime {
+		return false
 	}
-	if m.Flags != 0 {
-		n += 1 + sovFormat(uint64(m.Flags))
+	if this.ArgonMemory != that1.ArgonMemory {
+		return false
 	}
-	if m.Perm != 0 {
-		n += 1 + sovFormat(uint64(m.Perm))
+	if this.ArgonThreads != that1.ArgonThreads {
+		return false
 	}
-	if m.CreatedAt != nil {
-		l = m.CreatedAt.Size()
-		n += 1 + l + sovFormat(uint64(l))
+	if !bytes.Equal(this.Nonce, that1.Nonce) {
+		return false
 	}
-	if m.ModifiedAt != nil {
-		l = m.ModifiedAt.Size()
-		n += 1 + l + sovFormat(uint64(l))
+	if !bytes.Equal(this.SealedKey, that1.SealedKey) {
+		return false
 	}
-	return n
+	return true
 }
+func (this *KeySlot) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
 
-func (m *TOC) Size() (n int) {
-	var l int
-	_ = l
-	if len(m.Paths) > 0 {
-		for k, v := range m.Paths {
-			_ = k
-			_ = v
-			l = 0
-			if v != nil {
-				l = v.Size()
-				l += 1 + sovFormat(uint64(l))
-			}
-			mapEntrySize := 1 + len(k) + sovFormat(uint64(len(k))) + l
-			n += mapEntrySize + 1 + sovFormat(uint64(mapEntrySize))
+	that1, ok := that.(*KeySlot)
+	if !ok {
+		that2, ok := that.(KeySlot)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
 		}
 	}
-	return n
-}
-
-func (m *BlockInfo) Size() (n int) {
-	var l int
-	_ = l
-	l = len(m.Id)
-	if l > 0 {
-		n += 1 + l + sovFormat(uint64(l))
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
 	}
-	if m.ByteSize != 0 {
-		n += 1 + sovFormat(uint64(m.ByteSize))
+	if !bytes.Equal(this.KeyId, that1.KeyId) {
+		return false
 	}
-	if m.CompSize != 0 {
-		n += 1 + sovFormat(uint64(m.CompSize))
+	if this.Name != that1.Name {
+		return false
 	}
-	if m.References != 0 {
-		n += 1 + sovFormat(uint64(m.References))
+	if !bytes.Equal(this.Ephemeral, that1.Ephemeral) {
+		return false
 	}
-	return n
+	if !bytes.Equal(this.Nonce, that1.Nonce) {
+		return false
+	}
+	if !bytes.Equal(this.WrappedKey, that1.WrappedKey) {
+		return false
+	}
+	return true
 }
+func (this *KeySlots) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
 
-func (m *BlockTOC) Size() (n int) {
-	var l int
-	_ = l
-	if len(m.Blocks) > 0 {
-		for _, e := range m.Blocks {
-			l = e.Size()
-			n += 1 + l + sovFormat(uint64(l))
+	that1, ok := that.(*KeySlots)
+	if !ok {
+		that2, ok := that.(KeySlots)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
 		}
 	}
-	l = len(m.BloomFilter)
-	if l > 0 {
-		n += 1 + l + sovFormat(uint64(l))
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
 	}
-	return n
-}
-
-func sovFormat(x uint64) (n int) {
-	for {
-		n++
-		x >>= 7
-		if x == 0 {
-			break
+	if !bytes.Equal(this.MasterId, that1.MasterId) {
+		return false
+	}
+	if len(this.Slots) != len(that1.Slots) {
+		return false
+	}
+	for i := range this.Slots {
+		if !this.Slots[i].Equal(that1.Slots[i]) {
+			return false
 		}
 	}
-	return n
-}
-func sozFormat(x uint64) (n int) {
-	return sovFormat(uint64((x << 1) ^ uint64((int64(x) >> 63))))
+	if !bytes.Equal(this.NextMasterId, that1.NextMasterId) {
+		return false
+	}
+	if !this.NextMaster.Equal(that1.NextMaster) {
+		return false
+	}
+	if len(this.NextSlots) != len(that1.NextSlots) {
+		return false
+	}
+	for i := range this.NextSlots {
+		if !this.NextSlots[i].Equal(that1.NextSlots[i]) {
+			return false
+		}
+	}
+	return true
 }
-func (this *TOCHeader) String() string {
+func (this *TOCHeader) GoString() string {
 	if this == nil {
 		return "nil"
 	}
-	s := strings.Join([]string{`&TOCHeader{`,
-		`KeyId:` + fmt.Sprintf("%v", this.KeyId) + `,`,
-		`Compressed:` + fmt.Sprintf("%v", this.Compressed) + `,`,
-		`Sum:` + fmt.Sprintf("%v", this.Sum) + `,`,
-		`TocSize:` + fmt.Sprintf("%v", this.TocSize) + `,`,
-		`BlocksSize:` + fmt.Sprintf("%v", this.BlocksSize) + `,`,
-		`}`,
-	}, "")
-	return s
+	s := make([]string, 0, 13)
+	s = append(s, "&format.TOCHeader{")
+	s = append(s, "KeyId: "+fmt.Sprintf("%#v", this.KeyId)+",\n")
+	s = append(s, "Compressed: "+fmt.Sprintf("%#v", this.Compressed)+",\n")
+	s = append(s, "Sum: "+fmt.Sprintf("%#v", this.Sum)+",\n")
+	s = append(s, "TocSize: "+fmt.Sprintf("%#v", this.TocSize)+",\n")
+	s = append(s, "BlocksSize: "+fmt.Sprintf("%#v", this.BlocksSize)+",\n")
+	s = append(s, "Mac: "+fmt.Sprintf("%#v", this.Mac)+",\n")
+	s = append(s, "Signature: "+fmt.Sprintf("%#v", this.Signature)+",\n")
+	s = append(s, "Signer: "+fmt.Sprintf("%#v", this.Signer)+",\n")
+	s = append(s, "Version: "+fmt.Sprintf("%#v", this.Version)+",\n")
+	s = append(s, "}")
+	return strings.Join(s, "")
 }
-func (this *Block) String() string {
+func (this *Block) GoString() string {
 	if this == nil {
 		return "nil"
 	}
-	s := strings.Join([]string{`&Block{`,
-		`Id:` + fmt.Sprintf("%v", this.Id) + `,`,
-		`}`,
-	}, "")
-	return s
+	s := make([]string, 0, 5)
+	s = append(s, "&format.Block{")
+	s = append(s, "Id: "+fmt.Sprintf("%#v", this.Id)+",\n")
+	s = append(s, "}")
+	return strings.Join(s, "")
 }
-func (this *BlockSet) String() string {
+func (this *BlockSet) GoString() string {
 	if this == nil {
 		return "nil"
 	}
-	s := strings.Join([]string{`&BlockSet{`,
-		`Blocks:` + strings.Replace(fmt.Sprintf("%v", this.Blocks), "Block", "Block", 1) + `,`,
-		`Sum:` + fmt.Sprintf("%v", this.Sum) + `,`,
-		`ByteSize:` + fmt.Sprintf("%v", this.ByteSize) + `,`,
-		`}`,
-	}, "")
-	return s
+	s := make([]string, 0, 7)
+	s = append(s, "&format.BlockSet{")
+	if this.Blocks != nil {
+		s = append(s, "Blocks: "+fmt.Sprintf("%#v", this.Blocks)+",\n")
+	}
+	s = append(s, "Sum: "+fmt.Sprintf("%#v", this.Sum)+",\n")
+	s = append(s, "ByteSize: "+fmt.Sprintf("%#v", this.ByteSize)+",\n")
+	s = append(s, "}")
+	return strings.Join(s, "")
 }
-func (this *TimeSpec) String() string {
+func (this *TimeSpec) GoString() string {
 	if this == nil {
 		return "nil"
 	}
-	s := strings.Join([]string{`&TimeSpec{`,
-		`Seconds:` + fmt.Sprintf("%v", this.Seconds) + `,`,
-		`Nanoseconds:` + fmt.Sprintf("%v", this.Nanoseconds) + `,`,
-		`}`,
-	}, "")
-	return s
+	s := make([]string, 0, 6)
+	s = append(s, "&format.TimeSpec{")
+	s = append(s, "Seconds: "+fmt.Sprintf("%#v", this.Seconds)+",\n")
+	s = append(s, "Nanoseconds: "+fmt.Sprintf("%#v", this.Nanoseconds)+",\n")
+	s = append(s, "}")
+	return strings.Join(s, "")
 }
-func (this *Entry) String() string {
+func (this *Entry) GoString() string {
 	if this == nil {
 		return "nil"
 	}
-	s := strings.Join([]string{`&Entry{`,
-		`ByteSize:` + fmt.Sprintf("%v", this.ByteSize) + `,`,
-		`Type:` + fmt.Sprintf("%v", this.Type) + `,`,
-		`Hash:` + fmt.Sprintf("%v", this.Hash) + `,`,
-		`Blocks:` + strings.Replace(fmt.Sprintf("%v", this.Blocks), "BlockSet", "BlockSet", 1) + `,`,
-		`Uname:` + fmt.Sprintf("%v", this.Uname) + `,`,
-		`Gname:` + fmt.Sprintf("%v", this.Gname) + `,`,
-		`Flags:` + fmt.Sprintf("%v", this.Flags) + `,`,
-		`Perm:` + fmt.Sprintf("%v", this.Perm) + `,`,
-		`CreatedAt:` + strings.Replace(fmt.Sprintf("%v", this.CreatedAt), "TimeSpec", "TimeSpec", 1) + `,`,
-		`ModifiedAt:` + strings.Replace(fmt.Sprintf("%v", this.ModifiedAt), "TimeSpec", "TimeSpec", 1) + `,`,
-		`}`,
-	}, "")
-	return s
+	s := make([]string, 0, 14)
+	s = append(s, "&format.Entry{")
+	s = append(s, "ByteSize: "+fmt.Sprintf("%#v", this.ByteSize)+",\n")
+	s = append(s, "Type: "+fmt.Sprintf("%#v", this.Type)+",\n")
+	s = append(s, "Hash: "+fmt.Sprintf("%#v", this.Hash)+",\n")
+	if this.Blocks != nil {
+		s = append(s, "Blocks: "+fmt.Sprintf("%#v", this.Blocks)+",\n")
+	}
+	s = append(s, "Uname: "+fmt.Sprintf("%#v", this.Uname)+",\n")
+	s = append(s, "Gname: "+fmt.Sprintf("%#v", this.Gname)+",\n")
+	s = append(s, "Flags: "+fmt.Sprintf("%#v", this.Flags)+",\n")
+	s = append(s, "Perm: "+fmt.Sprintf("%#v", this.Perm)+",\n")
+	if this.CreatedAt != nil {
+		s = append(s, "CreatedAt: "+fmt.Sprintf("%#v", this.CreatedAt)+",\n")
+	}
+	if this.ModifiedAt != nil {
+		s = append(s, "ModifiedAt: "+fmt.Sprintf("%#v", this.ModifiedAt)+",\n")
+	}
+	s = append(s, "}")
+	return strings.Join(s, "")
 }
-func (this *TOC) String() string {
+func (this *TOC) GoString() string {
 	if this == nil {
 		return "nil"
 	}
+	s := make([]string, 0, 5)
+	s = append(s, "&format.TOC{")
 	keysForPaths := make([]string, 0, len(this.Paths))
 	for k, _ := range this.Paths {
 		keysForPaths = append(keysForPaths, k)
@@ -1305,48 +2079,3216 @@ func (this *TOC) String() string {
 	github_com_gogo_protobuf_sortkeys.Strings(keysForPaths)
 	mapStringForPaths := "map[string]*Entry{"
 	for _, k := range keysForPaths {
-		mapStringForPaths += fmt.Sprintf("%v: %v,", k, this.Paths[k])
+		mapStringForPaths += fmt.Sprintf("%#v: %#v,", k, this.Paths[k])
+	}
+	mapStringForPaths += "}"
+	if this.Paths != nil {
+		s = append(s, "Paths: "+mapStringForPaths+",\n")
+	}
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *TOCNode) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 6)
+	s = append(s, "&format.TOCNode{")
+	keysForPaths := make([]string, 0, len(this.Paths))
+	for k, _ := range this.Paths {
+		keysForPaths = append(keysForPaths, k)
+	}
+	github_com_gogo_protobuf_sortkeys.Strings(keysForPaths)
+	mapStringForPaths := "map[string]*Entry{"
+	for _, k := range keysForPaths {
+		mapStringForPaths += fmt.Sprintf("%#v: %#v,", k, this.Paths[k])
+	}
+	mapStringForPaths += "}"
+	if this.Paths != nil {
+		s = append(s, "Paths: "+mapStringForPaths+",\n")
+	}
+	if this.Children != nil {
+		s = append(s, "Children: "+fmt.Sprintf("%#v", this.Children)+",\n")
+	}
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *TOCChild) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 6)
+	s = append(s, "&format.TOCChild{")
+	s = append(s, "First: "+fmt.Sprintf("%#v", this.First)+",\n")
+	if this.Node != nil {
+		s = append(s, "Node: "+fmt.Sprintf("%#v", this.Node)+",\n")
+	}
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *BlockInfo) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 8)
+	s = append(s, "&format.BlockInfo{")
+	s = append(s, "Id: "+fmt.Sprintf("%#v", this.Id)+",\n")
+	s = append(s, "ByteSize: "+fmt.Sprintf("%#v", this.ByteSize)+",\n")
+	s = append(s, "CompSize: "+fmt.Sprintf("%#v", this.CompSize)+",\n")
+	s = append(s, "References: "+fmt.Sprintf("%#v", this.References)+",\n")
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *BlockTOC) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 6)
+	s = append(s, "&format.BlockTOC{")
+	if this.Blocks != nil {
+		s = append(s, "Blocks: "+fmt.Sprintf("%#v", this.Blocks)+",\n")
+	}
+	s = append(s, "BloomFilter: "+fmt.Sprintf("%#v", this.BloomFilter)+",\n")
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *PackLocation) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 8)
+	s = append(s, "&format.PackLocation{")
+	s = append(s, "Id: "+fmt.Sprintf("%#v", this.Id)+",\n")
+	s = append(s, "Pack: "+fmt.Sprintf("%#v", this.Pack)+",\n")
+	s = append(s, "Offset: "+fmt.Sprintf("%#v", this.Offset)+",\n")
+	s = append(s, "Length: "+fmt.Sprintf("%#v", this.Length)+",\n")
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *PackIndex) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 5)
+	s = append(s, "&format.PackIndex{")
+	if this.Blocks != nil {
+		s = append(s, "Blocks: "+fmt.Sprintf("%#v", this.Blocks)+",\n")
+	}
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *ChunkParams) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 10)
+	s = append(s, "&format.ChunkParams{")
+	s = append(s, "Window: "+fmt.Sprintf("%#v", this.Window)+",\n")
+	s = append(s, "AverageBlock: "+fmt.Sprintf("%#v", this.AverageBlock)+",\n")
+	s = append(s, "MinBlock: "+fmt.Sprintf("%#v", this.MinBlock)+",\n")
+	s = append(s, "MaxBlock: "+fmt.Sprintf("%#v", this.MaxBlock)+",\n")
+	s = append(s, "Polynomial: "+fmt.Sprintf("%#v", this.Polynomial)+",\n")
+	s = append(s, "Algorithm: "+fmt.Sprintf("%#v", this.Algorithm)+",\n")
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *Config) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 10)
+	s = append(s, "&format.Config{")
+	if this.Chunking != nil {
+		s = append(s, "Chunking: "+fmt.Sprintf("%#v", this.Chunking)+",\n")
+	}
+	s = append(s, "BlockFrame: "+fmt.Sprintf("%#v", this.BlockFrame)+",\n")
+	s = append(s, "ZstdDictionaries: "+fmt.Sprintf("%#v", this.ZstdDictionaries)+",\n")
+	s = append(s, "Authenticated: "+fmt.Sprintf("%#v", this.Authenticated)+",\n")
+	s = append(s, "Version: "+fmt.Sprintf("%#v", this.Version)+",\n")
+	s = append(s, "Upgrading: "+fmt.Sprintf("%#v", this.Upgrading)+",\n")
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *KeyFile) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 12)
+	s = append(s, "&format.KeyFile{")
+	s = append(s, "Version: "+fmt.Sprintf("%#v", this.Version)+",\n")
+	s = append(s, "KeyId: "+fmt.Sprintf("%#v", this.KeyId)+",\n")
+	s = append(s, "Salt: "+fmt.Sprintf("%#v", this.Salt)+",\n")
+	s = append(s, "ArgonTime: "+fmt.Sprintf("%#v", this.ArgonTime)+",\n")
+	s = append(s, "ArgonMemory: "+fmt.Sprintf("%#v", this.ArgonMemory)+",\n")
+	s = append(s, "ArgonThreads: "+fmt.Sprintf("%#v", this.ArgonThreads)+",\n")
+	s = append(s, "Nonce: "+fmt.Sprintf("%#v", this.Nonce)+",\n")
+	s = append(s, "SealedKey: "+fmt.Sprintf("%#v", this.SealedKey)+",\n")
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *KeySlot) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 9)
+	s = append(s, "&format.KeySlot{")
+	s = append(s, "KeyId: "+fmt.Sprintf("%#v", this.KeyId)+",\n")
+	s = append(s, "Name: "+fmt.Sprintf("%#v", this.Name)+",\n")
+	s = append(s, "Ephemeral: "+fmt.Sprintf("%#v", this.Ephemeral)+",\n")
+	s = append(s, "Nonce: "+fmt.Sprintf("%#v", this.Nonce)+",\n")
+	s = append(s, "WrappedKey: "+fmt.Sprintf("%#v", this.WrappedKey)+",\n")
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *KeySlots) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 9)
+	s = append(s, "&format.KeySlots{")
+	s = append(s, "MasterId: "+fmt.Sprintf("%#v", this.MasterId)+",\n")
+	if this.Slots != nil {
+		s = append(s, "Slots: "+fmt.Sprintf("%#v", this.Slots)+",\n")
+	}
+	s = append(s, "NextMasterId: "+fmt.Sprintf("%#v", this.NextMasterId)+",\n")
+	if this.NextMaster != nil {
+		s = append(s, "NextMaster: "+fmt.Sprintf("%#v", this.NextMaster)+",\n")
+	}
+	if this.NextSlots != nil {
+		s = append(s, "NextSlots: "+fmt.Sprintf("%#v", this.NextSlots)+",\n")
+	}
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func valueToGoStringFormat(v interface{}, typ string) string {
+	rv := reflect.ValueOf(v)
+	if rv.IsNil() {
+		return "nil"
+	}
+	pv := reflect.Indirect(rv).Interface()
+	return fmt.Sprintf("func(v %v) *%v { return &v } ( %#v )", typ, typ, pv)
+}
+func (m *TOCHeader) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *TOCHeader) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *TOCHeader) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.Version != 0 {
+		i = encodeVarintFormat(dAtA, i, uint64(m.Version))
+		i--
+		dAtA[i] = 0x48
+	}
+	if len(m.Signer) > 0 {
+		i -= len(m.Signer)
+		copy(dAtA[i:], m.Signer)
+		i = encodeVarintFormat(dAtA, i, uint64(len(m.Signer)))
+		i--
+		dAtA[i] = 0x42
+	}
+	if len(m.Signature) > 0 {
+		i -= len(m.Signature)
+		copy(dAtA[i:], m.Signature)
+		i = encodeVarintFormat(dAtA, i, uint64(len(m.Signature)))
+		i--
+		dAtA[i] = 0x3a
+	}
+	if len(m.Mac) > 0 {
+		i -= len(m.Mac)
+		copy(dAtA[i:], m.Mac)
+		i = encodeVarintFormat(dAtA, i, uint64(len(m.Mac)))
+		i--
+		dAtA[i] = 0x32
+	}
+	if m.BlocksSize != 0 {
+		i = encodeVarintFormat(dAtA, i, uint64(m.BlocksSize))
+		i--
+		dAtA[i] = 0x28
+	}
+	if m.TocSize != 0 {
+		i = encodeVarintFormat(dAtA, i, uint64(m.TocSize))
+		i--
+		dAtA[i] = 0x20
+	}
+	if len(m.Sum) > 0 {
+		i -= len(m.Sum)
+		copy(dAtA[i:], m.Sum)
+		i = encodeVarintFormat(dAtA, i, uint64(len(m.Sum)))
+		i--
+		dAtA[i] = 0x1a
+	}
+	if m.Compressed {
+		i--
+		if m.Compressed {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i--
+		dAtA[i] = 0x10
+	}
+	if len(m.KeyId) > 0 {
+		i -= len(m.KeyId)
+		copy(dAtA[i:], m.KeyId)
+		i = encodeVarintFormat(dAtA, i, uint64(len(m.KeyId)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *Block) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *Block) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *Block) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Id) > 0 {
+		i -= len(m.Id)
+		copy(dAtA[i:], m.Id)
+		i = encodeVarintFormat(dAtA, i, uint64(len(m.Id)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *BlockSet) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *BlockSet) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *BlockSet) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.ByteSize != 0 {
+		i = encodeVarintFormat(dAtA, i, uint64(m.ByteSize))
+		i--
+		dAtA[i] = 0x18
+	}
+	if len(m.Sum) > 0 {
+		i -= len(m.Sum)
+		copy(dAtA[i:], m.Sum)
+		i = encodeVarintFormat(dAtA, i, uint64(len(m.Sum)))
+		i--
+		dAtA[i] = 0x12
+	}
+	if len(m.Blocks) > 0 {
+		for iNdEx := len(m.Blocks) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Blocks[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintFormat(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0xa
+		}
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *TimeSpec) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *TimeSpec) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *TimeSpec) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.Nanoseconds != 0 {
+		i = encodeVarintFormat(dAtA, i, uint64(m.Nanoseconds))
+		i--
+		dAtA[i] = 0x10
+	}
+	if m.Seconds != 0 {
+		i = encodeVarintFormat(dAtA, i, uint64(m.Seconds))
+		i--
+		dAtA[i] = 0x8
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *Entry) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *Entry) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *Entry) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.ModifiedAt != nil {
+		{
+			size, err := m.ModifiedAt.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintFormat(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x52
+	}
+	if m.CreatedAt != nil {
+		{
+			size, err := m.CreatedAt.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintFormat(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x4a
+	}
+	if m.Perm != 0 {
+		i = encodeVarintFormat(dAtA, i, uint64(m.Perm))
+		i--
+		dAtA[i] = 0x40
+	}
+	if m.Flags != 0 {
+		i = encodeVarintFormat(dAtA, i, uint64(m.Flags))
+		i--
+		dAtA[i] = 0x38
+	}
+	if len(m.Gname) > 0 {
+		i -= len(m.Gname)
+		copy(dAtA[i:], m.Gname)
+		i = encodeVarintFormat(dAtA, i, uint64(len(m.Gname)))
+		i--
+		dAtA[i] = 0x32
+	}
+	if len(m.Uname) > 0 {
+		i -= len(m.Uname)
+		copy(dAtA[i:], m.Uname)
+		i = encodeVarintFormat(dAtA, i, uint64(len(m.Uname)))
+		i--
+		dAtA[i] = 0x2a
+	}
+	if m.Blocks != nil {
+		{
+			size, err := m.Blocks.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintFormat(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x22
+	}
+	if len(m.Hash) > 0 {
+		i -= len(m.Hash)
+		copy(dAtA[i:], m.Hash)
+		i = encodeVarintFormat(dAtA, i, uint64(len(m.Hash)))
+		i--
+		dAtA[i] = 0x1a
+	}
+	if m.Type != 0 {
+		i = encodeVarintFormat(dAtA, i, uint64(m.Type))
+		i--
+		dAtA[i] = 0x10
+	}
+	if m.ByteSize != 0 {
+		i = encodeVarintFormat(dAtA, i, uint64(m.ByteSize))
+		i--
+		dAtA[i] = 0x8
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *TOC) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *TOC) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *TOC) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Paths) > 0 {
+		for k := range m.Paths {
+			v := m.Paths[k]
+			baseI := i
+			if v != nil {
+				{
+					size, err := v.MarshalToSizedBuffer(dAtA[:i])
+					if err != nil {
+						return 0, err
+					}
+					i -= size
+					i = encodeVarintFormat(dAtA, i, uint64(size))
+				}
+				i--
+				dAtA[i] = 0x12
+			}
+			i -= len(k)
+			copy(dAtA[i:], k)
+			i = encodeVarintFormat(dAtA, i, uint64(len(k)))
+			i--
+			dAtA[i] = 0xa
+			i = encodeVarintFormat(dAtA, i, uint64(baseI-i))
+			i--
+			dAtA[i] = 0xa
+		}
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *TOCNode) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *TOCNode) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *TOCNode) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Children) > 0 {
+		for iNdEx := len(m.Children) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Children[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintFormat(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x12
+		}
+	}
+	if len(m.Paths) > 0 {
+		for k := range m.Paths {
+			v := m.Paths[k]
+			baseI := i
+			if v != nil {
+				{
+					size, err := v.MarshalToSizedBuffer(dAtA[:i])
+					if err != nil {
+						return 0, err
+					}
+					i -= size
+					i = encodeVarintFormat(dAtA, i, uint64(size))
+				}
+				i--
+				dAtA[i] = 0x12
+			}
+			i -= len(k)
+			copy(dAtA[i:], k)
+			i = encodeVarintFormat(dAtA, i, uint64(len(k)))
+			i--
+			dAtA[i] = 0xa
+			i = encodeVarintFormat(dAtA, i, uint64(baseI-i))
+			i--
+			dAtA[i] = 0xa
+		}
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *TOCChild) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *TOCChild) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *TOCChild) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.Node != nil {
+		{
+			size, err := m.Node.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintFormat(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x12
+	}
+	if len(m.First) > 0 {
+		i -= len(m.First)
+		copy(dAtA[i:], m.First)
+		i = encodeVarintFormat(dAtA, i, uint64(len(m.First)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *BlockInfo) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *BlockInfo) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *BlockInfo) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.References != 0 {
+		i = encodeVarintFormat(dAtA, i, uint64(m.References))
+		i--
+		dAtA[i] = 0x20
+	}
+	if m.CompSize != 0 {
+		i = encodeVarintFormat(dAtA, i, uint64(m.CompSize))
+		i--
+		dAtA[i] = 0x18
+	}
+	if m.ByteSize != 0 {
+		i = encodeVarintFormat(dAtA, i, uint64(m.ByteSize))
+		i--
+		dAtA[i] = 0x10
+	}
+	if len(m.Id) > 0 {
+		i -= len(m.Id)
+		copy(dAtA[i:], m.Id)
+		i = encodeVarintFormat(dAtA, i, uint64(len(m.Id)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *BlockTOC) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *BlockTOC) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *BlockTOC) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.BloomFilter) > 0 {
+		i -= len(m.BloomFilter)
+		copy(dAtA[i:], m.BloomFilter)
+		i = encodeVarintFormat(dAtA, i, uint64(len(m.BloomFilter)))
+		i--
+		dAtA[i] = 0x12
+	}
+	if len(m.Blocks) > 0 {
+		for iNdEx := len(m.Blocks) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Blocks[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintFormat(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0xa
+		}
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *PackLocation) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *PackLocation) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *PackLocation) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.Length != 0 {
+		i = encodeVarintFormat(dAtA, i, uint64(m.Length))
+		i--
+		dAtA[i] = 0x20
+	}
+	if m.Offset != 0 {
+		i = encodeVarintFormat(dAtA, i, uint64(m.Offset))
+		i--
+		dAtA[i] = 0x18
+	}
+	if m.Pack != 0 {
+		i = encodeVarintFormat(dAtA, i, uint64(m.Pack))
+		i--
+		dAtA[i] = 0x10
+	}
+	if len(m.Id) > 0 {
+		i -= len(m.Id)
+		copy(dAtA[i:], m.Id)
+		i = encodeVarintFormat(dAtA, i, uint64(len(m.Id)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *PackIndex) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *PackIndex) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *PackIndex) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Blocks) > 0 {
+		for iNdEx := len(m.Blocks) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Blocks[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintFormat(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0xa
+		}
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *ChunkParams) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *ChunkParams) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *ChunkParams) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.Algorithm != 0 {
+		i = encodeVarintFormat(dAtA, i, uint64(m.Algorithm))
+		i--
+		dAtA[i] = 0x30
+	}
+	if m.Polynomial != 0 {
+		i = encodeVarintFormat(dAtA, i, uint64(m.Polynomial))
+		i--
+		dAtA[i] = 0x28
+	}
+	if m.MaxBlock != 0 {
+		i = encodeVarintFormat(dAtA, i, uint64(m.MaxBlock))
+		i--
+		dAtA[i] = 0x20
+	}
+	if m.MinBlock != 0 {
+		i = encodeVarintFormat(dAtA, i, uint64(m.MinBlock))
+		i--
+		dAtA[i] = 0x18
+	}
+	if m.AverageBlock != 0 {
+		i = encodeVarintFormat(dAtA, i, uint64(m.AverageBlock))
+		i--
+		dAtA[i] = 0x10
+	}
+	if m.Window != 0 {
+		i = encodeVarintFormat(dAtA, i, uint64(m.Window))
+		i--
+		dAtA[i] = 0x8
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *Config) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *Config) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *Config) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.Upgrading {
+		i--
+		if m.Upgrading {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i--
+		dAtA[i] = 0x30
+	}
+	if m.Version != 0 {
+		i = encodeVarintFormat(dAtA, i, uint64(m.Version))
+		i--
+		dAtA[i] = 0x28
+	}
+	if m.Authenticated {
+		i--
+		if m.Authenticated {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i--
+		dAtA[i] = 0x20
+	}
+	if len(m.ZstdDictionaries) > 0 {
+		for iNdEx := len(m.ZstdDictionaries) - 1; iNdEx >= 0; iNdEx-- {
+			i -= len(m.ZstdDictionaries[iNdEx])
+			copy(dAtA[i:], m.ZstdDictionaries[iNdEx])
+			i = encodeVarintFormat(dAtA, i, uint64(len(m.ZstdDictionaries[iNdEx])))
+			i--
+			dAtA[i] = 0x1a
+		}
+	}
+	if m.BlockFrame != 0 {
+		i = encodeVarintFormat(dAtA, i, uint64(m.BlockFrame))
+		i--
+		dAtA[i] = 0x10
+	}
+	if m.Chunking != nil {
+		{
+			size, err := m.Chunking.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintFormat(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *KeyFile) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *KeyFile) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *KeyFile) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.SealedKey) > 0 {
+		i -= len(m.SealedKey)
+		copy(dAtA[i:], m.SealedKey)
+		i = encodeVarintFormat(dAtA, i, uint64(len(m.SealedKey)))
+		i--
+		dAtA[i] = 0x42
+	}
+	if len(m.Nonce) > 0 {
+		i -= len(m.Nonce)
+		copy(dAtA[i:], m.Nonce)
+		i = encodeVarintFormat(dAtA, i, uint64(len(m.Nonce)))
+		i--
+		dAtA[i] = 0x3a
+	}
+	if m.ArgonThreads != 0 {
+		i = encodeVarintFormat(dAtA, i, uint64(m.ArgonThreads))
+		i--
+		dAtA[i] = 0x30
+	}
+	if m.ArgonMemory != 0 {
+		i = encodeVarintFormat(dAtA, i, uint64(m.ArgonMemory))
+		i--
+		dAtA[i] = 0x28
+	}
+	if m.ArgonTime != 0 {
+		i = encodeVarintFormat(dAtA, i, uint64(m.ArgonTime))
+		i--
+		dAtA[i] = 0x20
+	}
+	if len(m.Salt) > 0 {
+		i -= len(m.Salt)
+		copy(dAtA[i:], m.Salt)
+		i = encodeVarintFormat(dAtA, i, uint64(len(m.Salt)))
+		i--
+		dAtA[i] = 0x1a
+	}
+	if len(m.KeyId) > 0 {
+		i -= len(m.KeyId)
+		copy(dAtA[i:], m.KeyId)
+		i = encodeVarintFormat(dAtA, i, uint64(len(m.KeyId)))
+		i--
+		dAtA[i] = 0x12
+	}
+	if m.Version != 0 {
+		i = encodeVarintFormat(dAtA, i, uint64(m.Version))
+		i--
+		dAtA[i] = 0x8
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *KeySlot) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *KeySlot) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *KeySlot) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.WrappedKey) > 0 {
+		i -= len(m.WrappedKey)
+		copy(dAtA[i:], m.WrappedKey)
+		i = encodeVarintFormat(dAtA, i, uint64(len(m.WrappedKey)))
+		i--
+		dAtA[i] = 0x2a
+	}
+	if len(m.Nonce) > 0 {
+		i -= len(m.Nonce)
+		copy(dAtA[i:], m.Nonce)
+		i = encodeVarintFormat(dAtA, i, uint64(len(m.Nonce)))
+		i--
+		dAtA[i] = 0x22
+	}
+	if len(m.Ephemeral) > 0 {
+		i -= len(m.Ephemeral)
+		copy(dAtA[i:], m.Ephemeral)
+		i = encodeVarintFormat(dAtA, i, uint64(len(m.Ephemeral)))
+		i--
+		dAtA[i] = 0x1a
+	}
+	if len(m.Name) > 0 {
+		i -= len(m.Name)
+		copy(dAtA[i:], m.Name)
+		i = encodeVarintFormat(dAtA, i, uint64(len(m.Name)))
+		i--
+		dAtA[i] = 0x12
+	}
+	if len(m.KeyId) > 0 {
+		i -= len(m.KeyId)
+		copy(dAtA[i:], m.KeyId)
+		i = encodeVarintFormat(dAtA, i, uint64(len(m.KeyId)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *KeySlots) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *KeySlots) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *KeySlots) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.NextSlots) > 0 {
+		for iNdEx := len(m.NextSlots) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.NextSlots[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintFormat(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x2a
+		}
+	}
+	if m.NextMaster != nil {
+		{
+			size, err := m.NextMaster.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintFormat(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x22
+	}
+	if len(m.NextMasterId) > 0 {
+		i -= len(m.NextMasterId)
+		copy(dAtA[i:], m.NextMasterId)
+		i = encodeVarintFormat(dAtA, i, uint64(len(m.NextMasterId)))
+		i--
+		dAtA[i] = 0x1a
+	}
+	if len(m.Slots) > 0 {
+		for iNdEx := len(m.Slots) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Slots[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintFormat(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x12
+		}
+	}
+	if len(m.MasterId) > 0 {
+		i -= len(m.MasterId)
+		copy(dAtA[i:], m.MasterId)
+		i = encodeVarintFormat(dAtA, i, uint64(len(m.MasterId)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func encodeVarintFormat(dAtA []byte, offset int, v uint64) int {
+	offset -= sovFormat(v)
+	base := offset
+	for v >= 1<<7 {
+		dAtA[offset] = uint8(v&0x7f | 0x80)
+		v >>= 7
+		offset++
+	}
+	dAtA[offset] = uint8(v)
+	return base
+}
+func (m *TOCHeader) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.KeyId)
+	if l > 0 {
+		n += 1 + l + sovFormat(uint64(l))
+	}
+	if m.Compressed {
+		n += 2
+	}
+	l = len(m.Sum)
+	if l > 0 {
+		n += 1 + l + sovFormat(uint64(l))
+	}
+	if m.TocSize != 0 {
+		n += 1 + sovFormat(uint64(m.TocSize))
+	}
+	if m.BlocksSize != 0 {
+		n += 1 + sovFormat(uint64(m.BlocksSize))
+	}
+	l = len(m.Mac)
+	if l > 0 {
+		n += 1 + l + sovFormat(uint64(l))
+	}
+	l = len(m.Signature)
+	if l > 0 {
+		n += 1 + l + sovFormat(uint64(l))
+	}
+	l = len(m.Signer)
+	if l > 0 {
+		n += 1 + l + sovFormat(uint64(l))
+	}
+	if m.Version != 0 {
+		n += 1 + sovFormat(uint64(m.Version))
+	}
+	return n
+}
+
+func (m *Block) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Id)
+	if l > 0 {
+		n += 1 + l + sovFormat(uint64(l))
+	}
+	return n
+}
+
+func (m *BlockSet) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if len(m.Blocks) > 0 {
+		for _, e := range m.Blocks {
+			l = e.Size()
+			n += 1 + l + sovFormat(uint64(l))
+		}
+	}
+	l = len(m.Sum)
+	if l > 0 {
+		n += 1 + l + sovFormat(uint64(l))
+	}
+	if m.ByteSize != 0 {
+		n += 1 + sovFormat(uint64(m.ByteSize))
+	}
+	return n
+}
+
+func (m *TimeSpec) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Seconds != 0 {
+		n += 1 + sovFormat(uint64(m.Seconds))
+	}
+	if m.Nanoseconds != 0 {
+		n += 1 + sovFormat(uint64(m.Nanoseconds))
+	}
+	return n
+}
+
+func (m *Entry) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.ByteSize != 0 {
+		n += 1 + sovFormat(uint64(m.ByteSize))
+	}
+	if m.Type != 0 {
+		n += 1 + sovFormat(uint64(m.Type))
+	}
+	l = len(m.Hash)
+	if l > 0 {
+		n += 1 + l + sovFormat(uint64(l))
+	}
+	if m.Blocks != nil {
+		l = m.Blocks.Size()
+		n += 1 + l + sovFormat(uint64(l))
+	}
+	l = len(m.Uname)
+	if l > 0 {
+		n += 1 + l + sovFormat(uint64(l))
+	}
+	l = len(m.Gname)
+	if l > 0 {
+		n += 1 + l + sovFormat(uint64(l))
+	}
+	if m.Flags != 0 {
+		n += 1 + sovFormat(uint64(m.Flags))
+	}
+	if m.Perm != 0 {
+		n += 1 + sovFormat(uint64(m.Perm))
+	}
+	if m.CreatedAt != nil {
+		l = m.CreatedAt.Size()
+		n += 1 + l + sovFormat(uint64(l))
+	}
+	if m.ModifiedAt != nil {
+		l = m.ModifiedAt.Size()
+		n += 1 + l + sovFormat(uint64(l))
+	}
+	return n
+}
+
+func (m *TOC) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if len(m.Paths) > 0 {
+		for k, v := range m.Paths {
+			_ = k
+			_ = v
+			l = 0
+			if v != nil {
+				l = v.Size()
+				l += 1 + sovFormat(uint64(l))
+			}
+			mapEntrySize := 1 + len(k) + sovFormat(uint64(len(k))) + l
+			n += mapEntrySize + 1 + sovFormat(uint64(mapEntrySize))
+		}
+	}
+	return n
+}
+
+func (m *TOCNode) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if len(m.Paths) > 0 {
+		for k, v := range m.Paths {
+			_ = k
+			_ = v
+			l = 0
+			if v != nil {
+				l = v.Size()
+				l += 1 + sovFormat(uint64(l))
+			}
+			mapEntrySize := 1 + len(k) + sovFormat(uint64(len(k))) + l
+			n += mapEntrySize + 1 + sovFormat(uint64(mapEntrySize))
+		}
+	}
+	if len(m.Children) > 0 {
+		for _, e := range m.Children {
+			l = e.Size()
+			n += 1 + l + sovFormat(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *TOCChild) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.First)
+	if l > 0 {
+		n += 1 + l + sovFormat(uint64(l))
+	}
+	if m.Node != nil {
+		l = m.Node.Size()
+		n += 1 + l + sovFormat(uint64(l))
+	}
+	return n
+}
+
+func (m *BlockInfo) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Id)
+	if l > 0 {
+		n += 1 + l + sovFormat(uint64(l))
+	}
+	if m.ByteSize != 0 {
+		n += 1 + sovFormat(uint64(m.ByteSize))
+	}
+	if m.CompSize != 0 {
+		n += 1 + sovFormat(uint64(m.CompSize))
+	}
+	if m.References != 0 {
+		n += 1 + sovFormat(uint64(m.References))
+	}
+	return n
+}
+
+func (m *BlockTOC) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if len(m.Blocks) > 0 {
+		for _, e := range m.Blocks {
+			l = e.Size()
+			n += 1 + l + sovFormat(uint64(l))
+		}
+	}
+	l = len(m.BloomFilter)
+	if l > 0 {
+		n += 1 + l + sovFormat(uint64(l))
+	}
+	return n
+}
+
+func (m *PackLocation) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Id)
+	if l > 0 {
+		n += 1 + l + sovFormat(uint64(l))
+	}
+	if m.Pack != 0 {
+		n += 1 + sovFormat(uint64(m.Pack))
+	}
+	if m.Offset != 0 {
+		n += 1 + sovFormat(uint64(m.Offset))
+	}
+	if m.Length != 0 {
+		n += 1 + sovFormat(uint64(m.Length))
+	}
+	return n
+}
+
+func (m *PackIndex) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if len(m.Blocks) > 0 {
+		for _, e := range m.Blocks {
+			l = e.Size()
+			n += 1 + l + sovFormat(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *ChunkParams) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Window != 0 {
+		n += 1 + sovFormat(uint64(m.Window))
+	}
+	if m.AverageBlock != 0 {
+		n += 1 + sovFormat(uint64(m.AverageBlock))
+	}
+	if m.MinBlock != 0 {
+		n += 1 + sovFormat(uint64(m.MinBlock))
+	}
+	if m.MaxBlock != 0 {
+		n += 1 + sovFormat(uint64(m.MaxBlock))
+	}
+	if m.Polynomial != 0 {
+		n += 1 + sovFormat(uint64(m.Polynomial))
+	}
+	if m.Algorithm != 0 {
+		n += 1 + sovFormat(uint64(m.Algorithm))
+	}
+	return n
+}
+
+func (m *Config) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Chunking != nil {
+		l = m.Chunking.Size()
+		n += 1 + l + sovFormat(uint64(l))
+	}
+	if m.BlockFrame != 0 {
+		n += 1 + sovFormat(uint64(m.BlockFrame))
+	}
+	if len(m.ZstdDictionaries) > 0 {
+		for _, b := range m.ZstdDictionaries {
+			l = len(b)
+			n += 1 + l + sovFormat(uint64(l))
+		}
+	}
+	if m.Authenticated {
+		n += 2
+	}
+	if m.Version != 0 {
+		n += 1 + sovFormat(uint64(m.Version))
+	}
+	if m.Upgrading {
+		n += 2
+	}
+	return n
+}
+
+func (m *KeyFile) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Version != 0 {
+		n += 1 + sovFormat(uint64(m.Version))
+	}
+	l = len(m.KeyId)
+	if l > 0 {
+		n += 1 + l + sovFormat(uint64(l))
+	}
+	l = len(m.Salt)
+	if l > 0 {
+		n += 1 + l + sovFormat(uint64(l))
+	}
+	if m.ArgonTime != 0 {
+		n += 1 + sovFormat(uint64(m.ArgonTime))
+	}
+	if m.ArgonMemory != 0 {
+		n += 1 + sovFormat(uint64(m.ArgonMemory))
+	}
+	if m.ArgonThreads != 0 {
+		n += 1 + sovFormat(uint64(m.ArgonThreads))
+	}
+	l = len(m.Nonce)
+	if l > 0 {
+		n += 1 + l + sovFormat(uint64(l))
+	}
+	l = len(m.SealedKey)
+	if l > 0 {
+		n += 1 + l + sovFormat(uint64(l))
+	}
+	return n
+}
+
+func (m *KeySlot) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.KeyId)
+	if l > 0 {
+		n += 1 + l + sovFormat(uint64(l))
+	}
+	l = len(m.Name)
+	if l > 0 {
+		n += 1 + l + sovFormat(uint64(l))
+	}
+	l = len(m.Ephemeral)
+	if l > 0 {
+		n += 1 + l + sovFormat(uint64(l))
+	}
+	l = len(m.Nonce)
+	if l > 0 {
+		n += 1 + l + sovFormat(uint64(l))
+	}
+	l = len(m.WrappedKey)
+	if l > 0 {
+		n += 1 + l + sovFormat(uint64(l))
+	}
+	return n
+}
+
+func (m *KeySlots) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.MasterId)
+	if l > 0 {
+		n += 1 + l + sovFormat(uint64(l))
+	}
+	if len(m.Slots) > 0 {
+		for _, e := range m.Slots {
+			l = e.Size()
+			n += 1 + l + sovFormat(uint64(l))
+		}
+	}
+	l = len(m.NextMasterId)
+	if l > 0 {
+		n += 1 + l + sovFormat(uint64(l))
+	}
+	if m.NextMaster != nil {
+		l = m.NextMaster.Size()
+		n += 1 + l + sovFormat(uint64(l))
+	}
+	if len(m.NextSlots) > 0 {
+		for _, e := range m.NextSlots {
+			l = e.Size()
+			n += 1 + l + sovFormat(uint64(l))
+		}
+	}
+	return n
+}
+
+func sovFormat(x uint64) (n int) {
+	return (math_bits.Len64(x|1) + 6) / 7
+}
+func sozFormat(x uint64) (n int) {
+	return sovFormat(uint64((x << 1) ^ uint64((int64(x) >> 63))))
+}
+func (this *TOCHeader) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&TOCHeader{`,
+		`KeyId:` + fmt.Sprintf("%v", this.KeyId) + `,`,
+		`Compressed:` + fmt.Sprintf("%v", this.Compressed) + `,`,
+		`Sum:` + fmt.Sprintf("%v", this.Sum) + `,`,
+		`TocSize:` + fmt.Sprintf("%v", this.TocSize) + `,`,
+		`BlocksSize:` + fmt.Sprintf("%v", this.BlocksSize) + `,`,
+		`Mac:` + fmt.Sprintf("%v", this.Mac) + `,`,
+		`Signature:` + fmt.Sprintf("%v", this.Signature) + `,`,
+		`Signer:` + fmt.Sprintf("%v", this.Signer) + `,`,
+		`Version:` + fmt.Sprintf("%v", this.Version) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *Block) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&Block{`,
+		`Id:` + fmt.Sprintf("%v", this.Id) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *BlockSet) String() string {
+	if this == nil {
+		return "nil"
+	}
+	repeatedStringForBlocks := "[]*Block{"
+	for _, f := range this.Blocks {
+		repeatedStringForBlocks += strings.Replace(f.String(), "Block", "Block", 1) + ","
+	}
+	repeatedStringForBlocks += "}"
+	s := strings.Join([]string{`&BlockSet{`,
+		`Blocks:` + repeatedStringForBlocks + `,`,
+		`Sum:` + fmt.Sprintf("%v", this.Sum) + `,`,
+		`ByteSize:` + fmt.Sprintf("%v", this.ByteSize) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *TimeSpec) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&TimeSpec{`,
+		`Seconds:` + fmt.Sprintf("%v", this.Seconds) + `,`,
+		`Nanoseconds:` + fmt.Sprintf("%v", this.Nanoseconds) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *Entry) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&Entry{`,
+		`ByteSize:` + fmt.Sprintf("%v", this.ByteSize) + `,`,
+		`Type:` + fmt.Sprintf("%v", this.Type) + `,`,
+		`Hash:` + fmt.Sprintf("%v", this.Hash) + `,`,
+		`Blocks:` + strings.Replace(this.Blocks.String(), "BlockSet", "BlockSet", 1) + `,`,
+		`Uname:` + fmt.Sprintf("%v", this.Uname) + `,`,
+		`Gname:` + fmt.Sprintf("%v", this.Gname) + `,`,
+		`Flags:` + fmt.Sprintf("%v", this.Flags) + `,`,
+		`Perm:` + fmt.Sprintf("%v", this.Perm) + `,`,
+		`CreatedAt:` + strings.Replace(this.CreatedAt.String(), "TimeSpec", "TimeSpec", 1) + `,`,
+		`ModifiedAt:` + strings.Replace(this.ModifiedAt.String(), "TimeSpec", "TimeSpec", 1) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *TOC) String() string {
+	if this == nil {
+		return "nil"
+	}
+	keysForPaths := make([]string, 0, len(this.Paths))
+	for k, _ := range this.Paths {
+		keysForPaths = append(keysForPaths, k)
+	}
+	github_com_gogo_protobuf_sortkeys.Strings(keysForPaths)
+	mapStringForPaths := "map[string]*Entry{"
+	for _, k := range keysForPaths {
+		mapStringForPaths += fmt.Sprintf("%v: %v,", k, this.Paths[k])
+	}
+	mapStringForPaths += "}"
+	s := strings.Join([]string{`&TOC{`,
+		`Paths:` + mapStringForPaths + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *TOCNode) String() string {
+	if this == nil {
+		return "nil"
+	}
+	repeatedStringForChildren := "[]*TOCChild{"
+	for _, f := range this.Children {
+		repeatedStringForChildren += strings.Replace(f.String(), "TOCChild", "TOCChild", 1) + ","
+	}
+	repeatedStringForChildren += "}"
+	keysForPaths := make([]string, 0, len(this.Paths))
+	for k, _ := range this.Paths {
+		keysForPaths = append(keysForPaths, k)
+	}
+	github_com_gogo_protobuf_sortkeys.Strings(keysForPaths)
+	mapStringForPaths := "map[string]*Entry{"
+	for _, k := range keysForPaths {
+		mapStringForPaths += fmt.Sprintf("%v: %v,", k, this.Paths[k])
+	}
+	mapStringForPaths += "}"
+	s := strings.Join([]string{`&TOCNode{`,
+		`Paths:` + mapStringForPaths + `,`,
+		`Children:` + repeatedStringForChildren + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *TOCChild) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&TOCChild{`,
+		`First:` + fmt.Sprintf("%v", this.First) + `,`,
+		`Node:` + strings.Replace(this.Node.String(), "BlockSet", "BlockSet", 1) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *BlockInfo) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&BlockInfo{`,
+		`Id:` + fmt.Sprintf("%v", this.Id) + `,`,
+		`ByteSize:` + fmt.Sprintf("%v", this.ByteSize) + `,`,
+		`CompSize:` + fmt.Sprintf("%v", this.CompSize) + `,`,
+		`References:` + fmt.Sprintf("%v", this.References) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *BlockTOC) String() string {
+	if this == nil {
+		return "nil"
+	}
+	repeatedStringForBlocks := "[]*BlockInfo{"
+	for _, f := range this.Blocks {
+		repeatedStringForBlocks += strings.Replace(f.String(), "BlockInfo", "BlockInfo", 1) + ","
+	}
+	repeatedStringForBlocks += "}"
+	s := strings.Join([]string{`&BlockTOC{`,
+		`Blocks:` + repeatedStringForBlocks + `,`,
+		`BloomFilter:` + fmt.Sprintf("%v", this.BloomFilter) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *PackLocation) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&PackLocation{`,
+		`Id:` + fmt.Sprintf("%v", this.Id) + `,`,
+		`Pack:` + fmt.Sprintf("%v", this.Pack) + `,`,
+		`Offset:` + fmt.Sprintf("%v", this.Offset) + `,`,
+		`Length:` + fmt.Sprintf("%v", this.Length) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *PackIndex) String() string {
+	if this == nil {
+		return "nil"
+	}
+	repeatedStringForBlocks := "[]*PackLocation{"
+	for _, f := range this.Blocks {
+		repeatedStringForBlocks += strings.Replace(f.String(), "PackLocation", "PackLocation", 1) + ","
+	}
+	repeatedStringForBlocks += "}"
+	s := strings.Join([]string{`&PackIndex{`,
+		`Blocks:` + repeatedStringForBlocks + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *ChunkParams) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&ChunkParams{`,
+		`Window:` + fmt.Sprintf("%v", this.Window) + `,`,
+		`AverageBlock:` + fmt.Sprintf("%v", this.AverageBlock) + `,`,
+		`MinBlock:` + fmt.Sprintf("%v", this.MinBlock) + `,`,
+		`MaxBlock:` + fmt.Sprintf("%v", this.MaxBlock) + `,`,
+		`Polynomial:` + fmt.Sprintf("%v", this.Polynomial) + `,`,
+		`Algorithm:` + fmt.Sprintf("%v", this.Algorithm) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *Config) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&Config{`,
+		`Chunking:` + strings.Replace(this.Chunking.String(), "ChunkParams", "ChunkParams", 1) + `,`,
+		`BlockFrame:` + fmt.Sprintf("%v", this.BlockFrame) + `,`,
+		`ZstdDictionaries:` + fmt.Sprintf("%v", this.ZstdDictionaries) + `,`,
+		`Authenticated:` + fmt.Sprintf("%v", this.Authenticated) + `,`,
+		`Version:` + fmt.Sprintf("%v", this.Version) + `,`,
+		`Upgrading:` + fmt.Sprintf("%v", this.Upgrading) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *KeyFile) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&KeyFile{`,
+		`Version:` + fmt.Sprintf("%v", this.Version) + `,`,
+		`KeyId:` + fmt.Sprintf("%v", this.KeyId) + `,`,
+		`Salt:` + fmt.Sprintf("%v", this.Salt) + `,`,
+		`ArgonTime:` + fmt.Sprintf("%v", this.ArgonTime) + `,`,
+		`ArgonMemory:` + fmt.Sprintf("%v", this.ArgonMemory) + `,`,
+		`ArgonThreads:` + fmt.Sprintf("%v", this.ArgonThreads) + `,`,
+		`Nonce:` + fmt.Sprintf("%v", this.Nonce) + `,`,
+		`SealedKey:` + fmt.Sprintf("%v", this.SealedKey) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *KeySlot) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&KeySlot{`,
+		`KeyId:` + fmt.Sprintf("%v", this.KeyId) + `,`,
+		`Name:` + fmt.Sprintf("%v", this.Name) + `,`,
+		`Ephemeral:` + fmt.Sprintf("%v", this.Ephemeral) + `,`,
+		`Nonce:` + fmt.Sprintf("%v", this.Nonce) + `,`,
+		`WrappedKey:` + fmt.Sprintf("%v", this.WrappedKey) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *KeySlots) String() string {
+	if this == nil {
+		return "nil"
+	}
+	repeatedStringForSlots := "[]*KeySlot{"
+	for _, f := range this.Slots {
+		repeatedStringForSlots += strings.Replace(f.String(), "KeySlot", "KeySlot", 1) + ","
+	}
+	repeatedStringForSlots += "}"
+	repeatedStringForNextSlots := "[]*KeySlot{"
+	for _, f := range this.NextSlots {
+		repeatedStringForNextSlots += strings.Replace(f.String(), "KeySlot", "KeySlot", 1) + ","
+	}
+	repeatedStringForNextSlots += "}"
+	s := strings.Join([]string{`&KeySlots{`,
+		`MasterId:` + fmt.Sprintf("%v", this.MasterId) + `,`,
+		`Slots:` + repeatedStringForSlots + `,`,
+		`NextMasterId:` + fmt.Sprintf("%v", this.NextMasterId) + `,`,
+		`NextMaster:` + strings.Replace(this.NextMaster.String(), "KeySlot", "KeySlot", 1) + `,`,
+		`NextSlots:` + repeatedStringForNextSlots + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func valueToStringFormat(v interface{}) string {
+	rv := reflect.ValueOf(v)
+	if rv.IsNil() {
+		return "nil"
+	}
+	pv := reflect.Indirect(rv).Interface()
+	return fmt.Sprintf("*%v", pv)
+}
+func (m *TOCHeader) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowFormat
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: TOCHeader: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: TOCHeader: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field KeyId", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowFormat
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthFormat
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthFormat
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.KeyId = append(m.KeyId[:0], dAtA[iNdEx:postIndex]...)
+			if m.KeyId == nil {
+				m.KeyId = []byte{}
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Compressed", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowFormat
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.Compressed = bool(v != 0)
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Sum", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowFormat
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthFormat
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthFormat
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Sum = append(m.Sum[:0], dAtA[iNdEx:postIndex]...)
+			if m.Sum == nil {
+				m.Sum = []byte{}
+			}
+			iNdEx = postIndex
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field TocSize", wireType)
+			}
+			m.TocSize = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowFormat
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.TocSize |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 5:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field BlocksSize", wireType)
+			}
+			m.BlocksSize = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowFormat
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.BlocksSize |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 6:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Mac", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowFormat
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthFormat
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthFormat
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Mac = append(m.Mac[:0], dAtA[iNdEx:postIndex]...)
+			if m.Mac == nil {
+				m.Mac = []byte{}
+			}
+			iNdEx = postIndex
+		case 7:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Signature", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowFormat
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthFormat
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthFormat
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Signature = append(m.Signature[:0], dAtA[iNdEx:postIndex]...)
+			if m.Signature == nil {
+				m.Signature = []byte{}
+			}
+			iNdEx = postIndex
+		case 8:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Signer", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowFormat
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthFormat
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthFormat
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Signer = append(m.Signer[:0], dAtA[iNdEx:postIndex]...)
+			if m.Signer == nil {
+				m.Signer = []byte{}
+			}
+			iNdEx = postIndex
+		case 9:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Version", wireType)
+			}
+			m.Version = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowFormat
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Version |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipFormat(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthFormat
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *Block) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowFormat
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: Block: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: Block: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Id", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowFormat
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthFormat
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthFormat
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Id = append(m.Id[:0], dAtA[iNdEx:postIndex]...)
+			if m.Id == nil {
+				m.Id = []byte{}
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipFormat(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthFormat
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *BlockSet) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowFormat
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: BlockSet: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: BlockSet: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Blocks", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowFormat
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthFormat
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthFormat
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Blocks = append(m.Blocks, &Block{})
+			if err := m.Blocks[len(m.Blocks)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Sum", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowFormat
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthFormat
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthFormat
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Sum = append(m.Sum[:0], dAtA[iNdEx:postIndex]...)
+			if m.Sum == nil {
+				m.Sum = []byte{}
+			}
+			iNdEx = postIndex
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ByteSize", wireType)
+			}
+			m.ByteSize = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowFormat
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.ByteSize |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipFormat(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthFormat
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *TimeSpec) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowFormat
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: TimeSpec: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: TimeSpec: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Seconds", wireType)
+			}
+			m.Seconds = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowFormat
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Seconds |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Nanoseconds", wireType)
+			}
+			m.Nanoseconds = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowFormat
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Nanoseconds |= int32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipFormat(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthFormat
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *Entry) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowFormat
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: Entry: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: Entry: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ByteSize", wireType)
+			}
+			m.ByteSize = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowFormat
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.ByteSize |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Type", wireType)
+			}
+			m.Type = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowFormat
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Type |= Type(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Hash", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowFormat
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthFormat
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthFormat
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Hash = append(m.Hash[:0], dAtA[iNdEx:postIndex]...)
+			if m.Hash == nil {
+				m.Hash = []byte{}
+			}
+			iNdEx = postIndex
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Blocks", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowFormat
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthFormat
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthFormat
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Blocks == nil {
+				m.Blocks = &BlockSet{}
+			}
+			if err := m.Blocks.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 5:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Uname", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowFormat
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthFormat
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthFormat
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Uname = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 6:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Gname", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowFormat
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthFormat
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthFormat
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Gname = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 7:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Flags", wireType)
+			}
+			m.Flags = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowFormat
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Flags |= int32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 8:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Perm", wireType)
+			}
+			m.Perm = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowFormat
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Perm |= int32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 9:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field CreatedAt", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowFormat
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthFormat
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthFormat
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.CreatedAt == nil {
+				m.CreatedAt = &TimeSpec{}
+			}
+			if err := m.CreatedAt.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 10:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ModifiedAt", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowFormat
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthFormat
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthFormat
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.ModifiedAt == nil {
+				m.ModifiedAt = &TimeSpec{}
+			}
+			if err := m.ModifiedAt.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipFormat(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthFormat
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *TOC) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowFormat
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: TOC: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: TOC: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Paths", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowFormat
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthFormat
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthFormat
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Paths == nil {
+				m.Paths = make(map[string]*Entry)
+			}
+			var mapkey string
+			var mapvalue *Entry
+			for iNdEx < postIndex {
+				entryPreIndex := iNdEx
+				var wire uint64
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return ErrIntOverflowFormat
+					}
+					if iNdEx >= l {
+						return io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					wire |= uint64(b&0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				fieldNum := int32(wire >> 3)
+				if fieldNum == 1 {
+					var stringLenmapkey uint64
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowFormat
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						stringLenmapkey |= uint64(b&0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+					intStringLenmapkey := int(stringLenmapkey)
+					if intStringLenmapkey < 0 {
+						return ErrInvalidLengthFormat
+					}
+					postStringIndexmapkey := iNdEx + intStringLenmapkey
+					if postStringIndexmapkey < 0 {
+						return ErrInvalidLengthFormat
+					}
+					if postStringIndexmapkey > l {
+						return io.ErrUnexpectedEOF
+					}
+					mapkey = string(dAtA[iNdEx:postStringIndexmapkey])
+					iNdEx = postStringIndexmapkey
+				} else if fieldNum == 2 {
+					var mapmsglen int
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowFormat
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						mapmsglen |= int(b&0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+					if mapmsglen < 0 {
+						return ErrInvalidLengthFormat
+					}
+					postmsgIndex := iNdEx + mapmsglen
+					if postmsgIndex < 0 {
+						return ErrInvalidLengthFormat
+					}
+					if postmsgIndex > l {
+						return io.ErrUnexpectedEOF
+					}
+					mapvalue = &Entry{}
+					if err := mapvalue.Unmarshal(dAtA[iNdEx:postmsgIndex]); err != nil {
+						return err
+					}
+					iNdEx = postmsgIndex
+				} else {
+					iNdEx = entryPreIndex
+					skippy, err := skipFormat(dAtA[iNdEx:])
+					if err != nil {
+						return err
+					}
+					if (skippy < 0) || (iNdEx+skippy) < 0 {
+						return ErrInvalidLengthFormat
+					}
+					if (iNdEx + skippy) > postIndex {
+						return io.ErrUnexpectedEOF
+					}
+					iNdEx += skippy
+				}
+			}
+			m.Paths[mapkey] = mapvalue
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipFormat(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthFormat
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
 	}
-	mapStringForPaths += "}"
-	s := strings.Join([]string{`&TOC{`,
-		`Paths:` + mapStringForPaths + `,`,
-		`}`,
-	}, "")
-	return s
-}
-func (this *BlockInfo) String() string {
-	if this == nil {
-		return "nil"
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
 	}
-	s := strings.Join([]string{`&BlockInfo{`,
-		`Id:` + fmt.Sprintf("%v", this.Id) + `,`,
-		`ByteSize:` + fmt.Sprintf("%v", this.ByteSize) + `,`,
-		`CompSize:` + fmt.Sprintf("%v", this.CompSize) + `,`,
-		`References:` + fmt.Sprintf("%v", this.References) + `,`,
-		`}`,
-	}, "")
-	return s
+	return nil
 }
-func (this *BlockTOC) String() string {
-	if this == nil {
-		return "nil"
+func (m *TOCNode) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowFormat
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: TOCNode: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: TOCNode: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Paths", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowFormat
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthFormat
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthFormat
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Paths == nil {
+				m.Paths = make(map[string]*Entry)
+			}
+			var mapkey string
+			var mapvalue *Entry
+			for iNdEx < postIndex {
+				entryPreIndex := iNdEx
+				var wire uint64
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return ErrIntOverflowFormat
+					}
+					if iNdEx >= l {
+						return io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					wire |= uint64(b&0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				fieldNum := int32(wire >> 3)
+				if fieldNum == 1 {
+					var stringLenmapkey uint64
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowFormat
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						stringLenmapkey |= uint64(b&0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+					intStringLenmapkey := int(stringLenmapkey)
+					if intStringLenmapkey < 0 {
+						return ErrInvalidLengthFormat
+					}
+					postStringIndexmapkey := iNdEx + intStringLenmapkey
+					if postStringIndexmapkey < 0 {
+						return ErrInvalidLengthFormat
+					}
+					if postStringIndexmapkey > l {
+						return io.ErrUnexpectedEOF
+					}
+					mapkey = string(dAtA[iNdEx:postStringIndexmapkey])
+					iNdEx = postStringIndexmapkey
+				} else if fieldNum == 2 {
+					var mapmsglen int
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowFormat
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						mapmsglen |= int(b&0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+					if mapmsglen < 0 {
+						return ErrInvalidLengthFormat
+					}
+					postmsgIndex := iNdEx + mapmsglen
+					if postmsgIndex < 0 {
+						return ErrInvalidLengthFormat
+					}
+					if postmsgIndex > l {
+						return io.ErrUnexpectedEOF
+					}
+					mapvalue = &Entry{}
+					if err := mapvalue.Unmarshal(dAtA[iNdEx:postmsgIndex]); err != nil {
+						return err
+					}
+					iNdEx = postmsgIndex
+				} else {
+					iNdEx = entryPreIndex
+					skippy, err := skipFormat(dAtA[iNdEx:])
+					if err != nil {
+						return err
+					}
+					if (skippy < 0) || (iNdEx+skippy) < 0 {
+						return ErrInvalidLengthFormat
+					}
+					if (iNdEx + skippy) > postIndex {
+						return io.ErrUnexpectedEOF
+					}
+					iNdEx += skippy
+				}
+			}
+			m.Paths[mapkey] = mapvalue
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Children", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowFormat
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthFormat
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthFormat
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Children = append(m.Children, &TOCChild{})
+			if err := m.Children[len(m.Children)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipFormat(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthFormat
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
 	}
-	s := strings.Join([]string{`&BlockTOC{`,
-		`Blocks:` + strings.Replace(fmt.Sprintf("%v", this.Blocks), "BlockInfo", "BlockInfo", 1) + `,`,
-		`BloomFilter:` + fmt.Sprintf("%v", this.BloomFilter) + `,`,
-		`}`,
-	}, "")
-	return s
-}
-func valueToStringFormat(v interface{}) string {
-	rv := reflect.ValueOf(v)
-	if rv.IsNil() {
-		return "nil"
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
 	}
-	pv := reflect.Indirect(rv).Interface()
-	return fmt.Sprintf("*%v", pv)
+	return nil
 }
-func (m *TOCHeader) Unmarshal(dAtA []byte) error {
+func (m *TOCChild) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -1361,7 +5303,7 @@ func (m *TOCHeader) Unmarshal(dAtA []byte) error {
 			}
 			b := dAtA[iNdEx]
 			iNdEx++
-			wire |= (uint64(b) & 0x7F) << shift
+			wire |= uint64(b&0x7F) << shift
 			if b < 0x80 {
 				break
 			}
@@ -1369,17 +5311,17 @@ func (m *TOCHeader) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: TOCHeader: wiretype end group for non-group")
+			return fmt.Errorf("proto: TOCChild: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: TOCHeader: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: TOCChild: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field KeyId", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field First", wireType)
 			}
-			var byteLen int
+			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowFormat
@@ -1389,28 +5331,29 @@ func (m *TOCHeader) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				byteLen |= (int(b) & 0x7F) << shift
+				stringLen |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if byteLen < 0 {
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthFormat
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
 				return ErrInvalidLengthFormat
 			}
-			postIndex := iNdEx + byteLen
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.KeyId = append(m.KeyId[:0], dAtA[iNdEx:postIndex]...)
-			if m.KeyId == nil {
-				m.KeyId = []byte{}
-			}
+			m.First = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
 		case 2:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Compressed", wireType)
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Node", wireType)
 			}
-			var v int
+			var msglen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowFormat
@@ -1420,15 +5363,81 @@ func (m *TOCHeader) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				v |= (int(b) & 0x7F) << shift
+				msglen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			m.Compressed = bool(v != 0)
-		case 3:
+			if msglen < 0 {
+				return ErrInvalidLengthFormat
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthFormat
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Node == nil {
+				m.Node = &BlockSet{}
+			}
+			if err := m.Node.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipFormat(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthFormat
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *BlockInfo) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowFormat
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: BlockInfo: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: BlockInfo: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Sum", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Id", wireType)
 			}
 			var byteLen int
 			for shift := uint(0); ; shift += 7 {
@@ -1440,7 +5449,7 @@ func (m *TOCHeader) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				byteLen |= (int(b) & 0x7F) << shift
+				byteLen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
@@ -1449,19 +5458,22 @@ func (m *TOCHeader) Unmarshal(dAtA []byte) error {
 				return ErrInvalidLengthFormat
 			}
 			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthFormat
+			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Sum = append(m.Sum[:0], dAtA[iNdEx:postIndex]...)
-			if m.Sum == nil {
-				m.Sum = []byte{}
+			m.Id = append(m.Id[:0], dAtA[iNdEx:postIndex]...)
+			if m.Id == nil {
+				m.Id = []byte{}
 			}
 			iNdEx = postIndex
-		case 4:
+		case 2:
 			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field TocSize", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field ByteSize", wireType)
 			}
-			m.TocSize = 0
+			m.ByteSize = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowFormat
@@ -1471,16 +5483,35 @@ func (m *TOCHeader) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.TocSize |= (int64(b) & 0x7F) << shift
+				m.ByteSize |= int64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-		case 5:
+		case 3:
 			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field BlocksSize", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field CompSize", wireType)
 			}
-			m.BlocksSize = 0
+			m.CompSize = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowFormat
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.CompSize |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field References", wireType)
+			}
+			m.References = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowFormat
@@ -1490,7 +5521,7 @@ func (m *TOCHeader) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.BlocksSize |= (int64(b) & 0x7F) << shift
+				m.References |= int64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
@@ -1501,7 +5532,7 @@ func (m *TOCHeader) Unmarshal(dAtA []byte) error {
 			if err != nil {
 				return err
 			}
-			if skippy < 0 {
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
 				return ErrInvalidLengthFormat
 			}
 			if (iNdEx + skippy) > l {
@@ -1516,7 +5547,7 @@ func (m *TOCHeader) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *Block) Unmarshal(dAtA []byte) error {
+func (m *BlockTOC) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -1531,7 +5562,7 @@ func (m *Block) Unmarshal(dAtA []byte) error {
 			}
 			b := dAtA[iNdEx]
 			iNdEx++
-			wire |= (uint64(b) & 0x7F) << shift
+			wire |= uint64(b&0x7F) << shift
 			if b < 0x80 {
 				break
 			}
@@ -1539,15 +5570,49 @@ func (m *Block) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: Block: wiretype end group for non-group")
+			return fmt.Errorf("proto: BlockTOC: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: Block: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: BlockTOC: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Id", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Blocks", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowFormat
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthFormat
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthFormat
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Blocks = append(m.Blocks, &BlockInfo{})
+			if err := m.Blocks[len(m.Blocks)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field BloomFilter", wireType)
 			}
 			var byteLen int
 			for shift := uint(0); ; shift += 7 {
@@ -1559,7 +5624,7 @@ func (m *Block) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				byteLen |= (int(b) & 0x7F) << shift
+				byteLen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
@@ -1568,12 +5633,15 @@ func (m *Block) Unmarshal(dAtA []byte) error {
 				return ErrInvalidLengthFormat
 			}
 			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthFormat
+			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Id = append(m.Id[:0], dAtA[iNdEx:postIndex]...)
-			if m.Id == nil {
-				m.Id = []byte{}
+			m.BloomFilter = append(m.BloomFilter[:0], dAtA[iNdEx:postIndex]...)
+			if m.BloomFilter == nil {
+				m.BloomFilter = []byte{}
 			}
 			iNdEx = postIndex
 		default:
@@ -1582,7 +5650,7 @@ func (m *Block) Unmarshal(dAtA []byte) error {
 			if err != nil {
 				return err
 			}
-			if skippy < 0 {
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
 				return ErrInvalidLengthFormat
 			}
 			if (iNdEx + skippy) > l {
@@ -1597,7 +5665,7 @@ func (m *Block) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *BlockSet) Unmarshal(dAtA []byte) error {
+func (m *PackLocation) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -1612,7 +5680,7 @@ func (m *BlockSet) Unmarshal(dAtA []byte) error {
 			}
 			b := dAtA[iNdEx]
 			iNdEx++
-			wire |= (uint64(b) & 0x7F) << shift
+			wire |= uint64(b&0x7F) << shift
 			if b < 0x80 {
 				break
 			}
@@ -1620,17 +5688,17 @@ func (m *BlockSet) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: BlockSet: wiretype end group for non-group")
+			return fmt.Errorf("proto: PackLocation: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: BlockSet: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: PackLocation: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Blocks", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Id", wireType)
 			}
-			var msglen int
+			var byteLen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowFormat
@@ -1640,28 +5708,31 @@ func (m *BlockSet) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= (int(b) & 0x7F) << shift
+				byteLen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if msglen < 0 {
+			if byteLen < 0 {
+				return ErrInvalidLengthFormat
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
 				return ErrInvalidLengthFormat
 			}
-			postIndex := iNdEx + msglen
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Blocks = append(m.Blocks, &Block{})
-			if err := m.Blocks[len(m.Blocks)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
-				return err
+			m.Id = append(m.Id[:0], dAtA[iNdEx:postIndex]...)
+			if m.Id == nil {
+				m.Id = []byte{}
 			}
 			iNdEx = postIndex
 		case 2:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Sum", wireType)
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Pack", wireType)
 			}
-			var byteLen int
+			m.Pack = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowFormat
@@ -1671,28 +5742,35 @@ func (m *BlockSet) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				byteLen |= (int(b) & 0x7F) << shift
+				m.Pack |= uint32(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if byteLen < 0 {
-				return ErrInvalidLengthFormat
-			}
-			postIndex := iNdEx + byteLen
-			if postIndex > l {
-				return io.ErrUnexpectedEOF
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Offset", wireType)
 			}
-			m.Sum = append(m.Sum[:0], dAtA[iNdEx:postIndex]...)
-			if m.Sum == nil {
-				m.Sum = []byte{}
+			m.Offset = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowFormat
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Offset |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
 			}
-			iNdEx = postIndex
-		case 3:
+		case 4:
 			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field ByteSize", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Length", wireType)
 			}
-			m.ByteSize = 0
+			m.Length = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowFormat
@@ -1702,7 +5780,7 @@ func (m *BlockSet) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.ByteSize |= (int64(b) & 0x7F) << shift
+				m.Length |= int64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
@@ -1713,7 +5791,7 @@ func (m *BlockSet) Unmarshal(dAtA []byte) error {
 			if err != nil {
 				return err
 			}
-			if skippy < 0 {
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
 				return ErrInvalidLengthFormat
 			}
 			if (iNdEx + skippy) > l {
@@ -1728,7 +5806,7 @@ func (m *BlockSet) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *TimeSpec) Unmarshal(dAtA []byte) error {
+func (m *PackIndex) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -1743,7 +5821,7 @@ func (m *TimeSpec) Unmarshal(dAtA []byte) error {
 			}
 			b := dAtA[iNdEx]
 			iNdEx++
-			wire |= (uint64(b) & 0x7F) << shift
+			wire |= uint64(b&0x7F) << shift
 			if b < 0x80 {
 				break
 			}
@@ -1751,17 +5829,17 @@ func (m *TimeSpec) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: TimeSpec: wiretype end group for non-group")
+			return fmt.Errorf("proto: PackIndex: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: TimeSpec: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: PackIndex: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Seconds", wireType)
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Blocks", wireType)
 			}
-			m.Seconds = 0
+			var msglen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowFormat
@@ -1771,37 +5849,33 @@ func (m *TimeSpec) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.Seconds |= (int64(b) & 0x7F) << shift
+				msglen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-		case 2:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Nanoseconds", wireType)
+			if msglen < 0 {
+				return ErrInvalidLengthFormat
 			}
-			m.Nanoseconds = 0
-			for shift := uint(0); ; shift += 7 {
-				if shift >= 64 {
-					return ErrIntOverflowFormat
-				}
-				if iNdEx >= l {
-					return io.ErrUnexpectedEOF
-				}
-				b := dAtA[iNdEx]
-				iNdEx++
-				m.Nanoseconds |= (int32(b) & 0x7F) << shift
-				if b < 0x80 {
-					break
-				}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthFormat
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
 			}
+			m.Blocks = append(m.Blocks, &PackLocation{})
+			if err := m.Blocks[len(m.Blocks)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipFormat(dAtA[iNdEx:])
 			if err != nil {
 				return err
 			}
-			if skippy < 0 {
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
 				return ErrInvalidLengthFormat
 			}
 			if (iNdEx + skippy) > l {
@@ -1816,7 +5890,7 @@ func (m *TimeSpec) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *Entry) Unmarshal(dAtA []byte) error {
+func (m *ChunkParams) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -1831,7 +5905,7 @@ func (m *Entry) Unmarshal(dAtA []byte) error {
 			}
 			b := dAtA[iNdEx]
 			iNdEx++
-			wire |= (uint64(b) & 0x7F) << shift
+			wire |= uint64(b&0x7F) << shift
 			if b < 0x80 {
 				break
 			}
@@ -1839,17 +5913,17 @@ func (m *Entry) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: Entry: wiretype end group for non-group")
+			return fmt.Errorf("proto: ChunkParams: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: Entry: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: ChunkParams: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
 			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field ByteSize", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Window", wireType)
 			}
-			m.ByteSize = 0
+			m.Window = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowFormat
@@ -1859,16 +5933,16 @@ func (m *Entry) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.ByteSize |= (int64(b) & 0x7F) << shift
+				m.Window |= int32(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
 		case 2:
 			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Type", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field AverageBlock", wireType)
 			}
-			m.Type = 0
+			m.AverageBlock = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowFormat
@@ -1878,16 +5952,16 @@ func (m *Entry) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.Type |= (Type(b) & 0x7F) << shift
+				m.AverageBlock |= int32(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
 		case 3:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Hash", wireType)
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field MinBlock", wireType)
 			}
-			var byteLen int
+			m.MinBlock = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowFormat
@@ -1897,26 +5971,121 @@ func (m *Entry) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				byteLen |= (int(b) & 0x7F) << shift
+				m.MinBlock |= int32(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if byteLen < 0 {
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field MaxBlock", wireType)
+			}
+			m.MaxBlock = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowFormat
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.MaxBlock |= int32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 5:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Polynomial", wireType)
+			}
+			m.Polynomial = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowFormat
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Polynomial |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 6:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Algorithm", wireType)
+			}
+			m.Algorithm = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowFormat
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Algorithm |= ChunkAlgorithm(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipFormat(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
 				return ErrInvalidLengthFormat
 			}
-			postIndex := iNdEx + byteLen
-			if postIndex > l {
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *Config) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowFormat
+			}
+			if iNdEx >= l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Hash = append(m.Hash[:0], dAtA[iNdEx:postIndex]...)
-			if m.Hash == nil {
-				m.Hash = []byte{}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
 			}
-			iNdEx = postIndex
-		case 4:
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: Config: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: Config: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Blocks", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Chunking", wireType)
 			}
 			var msglen int
 			for shift := uint(0); ; shift += 7 {
@@ -1928,7 +6097,7 @@ func (m *Entry) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= (int(b) & 0x7F) << shift
+				msglen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
@@ -1937,21 +6106,24 @@ func (m *Entry) Unmarshal(dAtA []byte) error {
 				return ErrInvalidLengthFormat
 			}
 			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthFormat
+			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if m.Blocks == nil {
-				m.Blocks = &BlockSet{}
+			if m.Chunking == nil {
+				m.Chunking = &ChunkParams{}
 			}
-			if err := m.Blocks.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+			if err := m.Chunking.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
 				return err
 			}
 			iNdEx = postIndex
-		case 5:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Uname", wireType)
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field BlockFrame", wireType)
 			}
-			var stringLen uint64
+			m.BlockFrame = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowFormat
@@ -1961,26 +6133,16 @@ func (m *Entry) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				stringLen |= (uint64(b) & 0x7F) << shift
+				m.BlockFrame |= uint32(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			intStringLen := int(stringLen)
-			if intStringLen < 0 {
-				return ErrInvalidLengthFormat
-			}
-			postIndex := iNdEx + intStringLen
-			if postIndex > l {
-				return io.ErrUnexpectedEOF
-			}
-			m.Uname = string(dAtA[iNdEx:postIndex])
-			iNdEx = postIndex
-		case 6:
+		case 3:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Gname", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field ZstdDictionaries", wireType)
 			}
-			var stringLen uint64
+			var byteLen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowFormat
@@ -1990,26 +6152,29 @@ func (m *Entry) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				stringLen |= (uint64(b) & 0x7F) << shift
+				byteLen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			intStringLen := int(stringLen)
-			if intStringLen < 0 {
+			if byteLen < 0 {
+				return ErrInvalidLengthFormat
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
 				return ErrInvalidLengthFormat
 			}
-			postIndex := iNdEx + intStringLen
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Gname = string(dAtA[iNdEx:postIndex])
+			m.ZstdDictionaries = append(m.ZstdDictionaries, make([]byte, postIndex-iNdEx))
+			copy(m.ZstdDictionaries[len(m.ZstdDictionaries)-1], dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
-		case 7:
+		case 4:
 			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Flags", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Authenticated", wireType)
 			}
-			m.Flags = 0
+			var v int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowFormat
@@ -2019,35 +6184,17 @@ func (m *Entry) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.Flags |= (int32(b) & 0x7F) << shift
+				v |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-		case 8:
+			m.Authenticated = bool(v != 0)
+		case 5:
 			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Perm", wireType)
-			}
-			m.Perm = 0
-			for shift := uint(0); ; shift += 7 {
-				if shift >= 64 {
-					return ErrIntOverflowFormat
-				}
-				if iNdEx >= l {
-					return io.ErrUnexpectedEOF
-				}
-				b := dAtA[iNdEx]
-				iNdEx++
-				m.Perm |= (int32(b) & 0x7F) << shift
-				if b < 0x80 {
-					break
-				}
-			}
-		case 9:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field CreatedAt", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Version", wireType)
 			}
-			var msglen int
+			m.Version = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowFormat
@@ -2057,30 +6204,16 @@ func (m *Entry) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= (int(b) & 0x7F) << shift
+				m.Version |= uint32(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if msglen < 0 {
-				return ErrInvalidLengthFormat
-			}
-			postIndex := iNdEx + msglen
-			if postIndex > l {
-				return io.ErrUnexpectedEOF
-			}
-			if m.CreatedAt == nil {
-				m.CreatedAt = &TimeSpec{}
-			}
-			if err := m.CreatedAt.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
-				return err
-			}
-			iNdEx = postIndex
-		case 10:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field ModifiedAt", wireType)
+		case 6:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Upgrading", wireType)
 			}
-			var msglen int
+			var v int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowFormat
@@ -2090,32 +6223,19 @@ func (m *Entry) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= (int(b) & 0x7F) << shift
+				v |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if msglen < 0 {
-				return ErrInvalidLengthFormat
-			}
-			postIndex := iNdEx + msglen
-			if postIndex > l {
-				return io.ErrUnexpectedEOF
-			}
-			if m.ModifiedAt == nil {
-				m.ModifiedAt = &TimeSpec{}
-			}
-			if err := m.ModifiedAt.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
-				return err
-			}
-			iNdEx = postIndex
+			m.Upgrading = bool(v != 0)
 		default:
 			iNdEx = preIndex
 			skippy, err := skipFormat(dAtA[iNdEx:])
 			if err != nil {
 				return err
 			}
-			if skippy < 0 {
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
 				return ErrInvalidLengthFormat
 			}
 			if (iNdEx + skippy) > l {
@@ -2130,7 +6250,7 @@ func (m *Entry) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *TOC) Unmarshal(dAtA []byte) error {
+func (m *KeyFile) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -2145,7 +6265,7 @@ func (m *TOC) Unmarshal(dAtA []byte) error {
 			}
 			b := dAtA[iNdEx]
 			iNdEx++
-			wire |= (uint64(b) & 0x7F) << shift
+			wire |= uint64(b&0x7F) << shift
 			if b < 0x80 {
 				break
 			}
@@ -2153,17 +6273,36 @@ func (m *TOC) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: TOC: wiretype end group for non-group")
+			return fmt.Errorf("proto: KeyFile: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: TOC: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: KeyFile: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Version", wireType)
+			}
+			m.Version = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowFormat
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Version |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 2:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Paths", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field KeyId", wireType)
 			}
-			var msglen int
+			var byteLen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowFormat
@@ -2173,19 +6312,31 @@ func (m *TOC) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= (int(b) & 0x7F) << shift
+				byteLen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if msglen < 0 {
+			if byteLen < 0 {
+				return ErrInvalidLengthFormat
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
 				return ErrInvalidLengthFormat
 			}
-			postIndex := iNdEx + msglen
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			var keykey uint64
+			m.KeyId = append(m.KeyId[:0], dAtA[iNdEx:postIndex]...)
+			if m.KeyId == nil {
+				m.KeyId = []byte{}
+			}
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Salt", wireType)
+			}
+			var byteLen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowFormat
@@ -2195,12 +6346,31 @@ func (m *TOC) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				keykey |= (uint64(b) & 0x7F) << shift
+				byteLen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			var stringLenmapkey uint64
+			if byteLen < 0 {
+				return ErrInvalidLengthFormat
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthFormat
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Salt = append(m.Salt[:0], dAtA[iNdEx:postIndex]...)
+			if m.Salt == nil {
+				m.Salt = []byte{}
+			}
+			iNdEx = postIndex
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ArgonTime", wireType)
+			}
+			m.ArgonTime = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowFormat
@@ -2210,74 +6380,115 @@ func (m *TOC) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				stringLenmapkey |= (uint64(b) & 0x7F) << shift
+				m.ArgonTime |= uint32(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			intStringLenmapkey := int(stringLenmapkey)
-			if intStringLenmapkey < 0 {
-				return ErrInvalidLengthFormat
+		case 5:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ArgonMemory", wireType)
 			}
-			postStringIndexmapkey := iNdEx + intStringLenmapkey
-			if postStringIndexmapkey > l {
-				return io.ErrUnexpectedEOF
+			m.ArgonMemory = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowFormat
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.ArgonMemory |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
 			}
-			mapkey := string(dAtA[iNdEx:postStringIndexmapkey])
-			iNdEx = postStringIndexmapkey
-			if m.Paths == nil {
-				m.Paths = make(map[string]*Entry)
+		case 6:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ArgonThreads", wireType)
 			}
-			if iNdEx < postIndex {
-				var valuekey uint64
-				for shift := uint(0); ; shift += 7 {
-					if shift >= 64 {
-						return ErrIntOverflowFormat
-					}
-					if iNdEx >= l {
-						return io.ErrUnexpectedEOF
-					}
-					b := dAtA[iNdEx]
-					iNdEx++
-					valuekey |= (uint64(b) & 0x7F) << shift
-					if b < 0x80 {
-						break
-					}
+			m.ArgonThreads = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowFormat
 				}
-				var mapmsglen int
-				for shift := uint(0); ; shift += 7 {
-					if shift >= 64 {
-						return ErrIntOverflowFormat
-					}
-					if iNdEx >= l {
-						return io.ErrUnexpectedEOF
-					}
-					b := dAtA[iNdEx]
-					iNdEx++
-					mapmsglen |= (int(b) & 0x7F) << shift
-					if b < 0x80 {
-						break
-					}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.ArgonThreads |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 7:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Nonce", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowFormat
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
 				}
-				if mapmsglen < 0 {
-					return ErrInvalidLengthFormat
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
 				}
-				postmsgIndex := iNdEx + mapmsglen
-				if mapmsglen < 0 {
-					return ErrInvalidLengthFormat
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthFormat
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthFormat
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Nonce = append(m.Nonce[:0], dAtA[iNdEx:postIndex]...)
+			if m.Nonce == nil {
+				m.Nonce = []byte{}
+			}
+			iNdEx = postIndex
+		case 8:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field SealedKey", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowFormat
 				}
-				if postmsgIndex > l {
+				if iNdEx >= l {
 					return io.ErrUnexpectedEOF
 				}
-				mapvalue := &Entry{}
-				if err := mapvalue.Unmarshal(dAtA[iNdEx:postmsgIndex]); err != nil {
-					return err
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
 				}
-				iNdEx = postmsgIndex
-				m.Paths[mapkey] = mapvalue
-			} else {
-				var mapvalue *Entry
-				m.Paths[mapkey] = mapvalue
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthFormat
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthFormat
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.SealedKey = append(m.SealedKey[:0], dAtA[iNdEx:postIndex]...)
+			if m.SealedKey == nil {
+				m.SealedKey = []byte{}
 			}
 			iNdEx = postIndex
 		default:
@@ -2286,7 +6497,7 @@ func (m *TOC) Unmarshal(dAtA []byte) error {
 			if err != nil {
 				return err
 			}
-			if skippy < 0 {
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
 				return ErrInvalidLengthFormat
 			}
 			if (iNdEx + skippy) > l {
@@ -2301,7 +6512,7 @@ func (m *TOC) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *BlockInfo) Unmarshal(dAtA []byte) error {
+func (m *KeySlot) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -2316,7 +6527,7 @@ func (m *BlockInfo) Unmarshal(dAtA []byte) error {
 			}
 			b := dAtA[iNdEx]
 			iNdEx++
-			wire |= (uint64(b) & 0x7F) << shift
+			wire |= uint64(b&0x7F) << shift
 			if b < 0x80 {
 				break
 			}
@@ -2324,15 +6535,15 @@ func (m *BlockInfo) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: BlockInfo: wiretype end group for non-group")
+			return fmt.Errorf("proto: KeySlot: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: BlockInfo: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: KeySlot: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Id", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field KeyId", wireType)
 			}
 			var byteLen int
 			for shift := uint(0); ; shift += 7 {
@@ -2344,7 +6555,7 @@ func (m *BlockInfo) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				byteLen |= (int(b) & 0x7F) << shift
+				byteLen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
@@ -2353,19 +6564,22 @@ func (m *BlockInfo) Unmarshal(dAtA []byte) error {
 				return ErrInvalidLengthFormat
 			}
 			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthFormat
+			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Id = append(m.Id[:0], dAtA[iNdEx:postIndex]...)
-			if m.Id == nil {
-				m.Id = []byte{}
+			m.KeyId = append(m.KeyId[:0], dAtA[iNdEx:postIndex]...)
+			if m.KeyId == nil {
+				m.KeyId = []byte{}
 			}
 			iNdEx = postIndex
 		case 2:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field ByteSize", wireType)
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Name", wireType)
 			}
-			m.ByteSize = 0
+			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowFormat
@@ -2375,16 +6589,29 @@ func (m *BlockInfo) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.ByteSize |= (int64(b) & 0x7F) << shift
+				stringLen |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthFormat
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthFormat
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Name = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
 		case 3:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field CompSize", wireType)
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Ephemeral", wireType)
 			}
-			m.CompSize = 0
+			var byteLen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowFormat
@@ -2394,16 +6621,65 @@ func (m *BlockInfo) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.CompSize |= (int64(b) & 0x7F) << shift
+				byteLen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
+			if byteLen < 0 {
+				return ErrInvalidLengthFormat
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthFormat
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Ephemeral = append(m.Ephemeral[:0], dAtA[iNdEx:postIndex]...)
+			if m.Ephemeral == nil {
+				m.Ephemeral = []byte{}
+			}
+			iNdEx = postIndex
 		case 4:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field References", wireType)
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Nonce", wireType)
 			}
-			m.References = 0
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowFormat
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthFormat
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthFormat
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Nonce = append(m.Nonce[:0], dAtA[iNdEx:postIndex]...)
+			if m.Nonce == nil {
+				m.Nonce = []byte{}
+			}
+			iNdEx = postIndex
+		case 5:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field WrappedKey", wireType)
+			}
+			var byteLen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowFormat
@@ -2413,18 +6689,33 @@ func (m *BlockInfo) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.References |= (int64(b) & 0x7F) << shift
+				byteLen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
+			if byteLen < 0 {
+				return ErrInvalidLengthFormat
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthFormat
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.WrappedKey = append(m.WrappedKey[:0], dAtA[iNdEx:postIndex]...)
+			if m.WrappedKey == nil {
+				m.WrappedKey = []byte{}
+			}
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipFormat(dAtA[iNdEx:])
 			if err != nil {
 				return err
 			}
-			if skippy < 0 {
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
 				return ErrInvalidLengthFormat
 			}
 			if (iNdEx + skippy) > l {
@@ -2439,7 +6730,7 @@ func (m *BlockInfo) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *BlockTOC) Unmarshal(dAtA []byte) error {
+func (m *KeySlots) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -2454,7 +6745,7 @@ func (m *BlockTOC) Unmarshal(dAtA []byte) error {
 			}
 			b := dAtA[iNdEx]
 			iNdEx++
-			wire |= (uint64(b) & 0x7F) << shift
+			wire |= uint64(b&0x7F) << shift
 			if b < 0x80 {
 				break
 			}
@@ -2462,15 +6753,49 @@ func (m *BlockTOC) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: BlockTOC: wiretype end group for non-group")
+			return fmt.Errorf("proto: KeySlots: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: BlockTOC: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: KeySlots: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Blocks", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field MasterId", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowFormat
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthFormat
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthFormat
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.MasterId = append(m.MasterId[:0], dAtA[iNdEx:postIndex]...)
+			if m.MasterId == nil {
+				m.MasterId = []byte{}
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Slots", wireType)
 			}
 			var msglen int
 			for shift := uint(0); ; shift += 7 {
@@ -2482,7 +6807,7 @@ func (m *BlockTOC) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= (int(b) & 0x7F) << shift
+				msglen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
@@ -2491,17 +6816,20 @@ func (m *BlockTOC) Unmarshal(dAtA []byte) error {
 				return ErrInvalidLengthFormat
 			}
 			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthFormat
+			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Blocks = append(m.Blocks, &BlockInfo{})
-			if err := m.Blocks[len(m.Blocks)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+			m.Slots = append(m.Slots, &KeySlot{})
+			if err := m.Slots[len(m.Slots)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
 				return err
 			}
 			iNdEx = postIndex
-		case 2:
+		case 3:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field BloomFilter", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field NextMasterId", wireType)
 			}
 			var byteLen int
 			for shift := uint(0); ; shift += 7 {
@@ -2513,7 +6841,7 @@ func (m *BlockTOC) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				byteLen |= (int(b) & 0x7F) << shift
+				byteLen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
@@ -2522,12 +6850,85 @@ func (m *BlockTOC) Unmarshal(dAtA []byte) error {
 				return ErrInvalidLengthFormat
 			}
 			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthFormat
+			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.BloomFilter = append(m.BloomFilter[:0], dAtA[iNdEx:postIndex]...)
-			if m.BloomFilter == nil {
-				m.BloomFilter = []byte{}
+			m.NextMasterId = append(m.NextMasterId[:0], dAtA[iNdEx:postIndex]...)
+			if m.NextMasterId == nil {
+				m.NextMasterId = []byte{}
+			}
+			iNdEx = postIndex
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field NextMaster", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowFormat
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthFormat
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthFormat
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.NextMaster == nil {
+				m.NextMaster = &KeySlot{}
+			}
+			if err := m.NextMaster.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 5:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field NextSlots", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowFormat
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthFormat
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthFormat
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.NextSlots = append(m.NextSlots, &KeySlot{})
+			if err := m.NextSlots[len(m.NextSlots)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
 			}
 			iNdEx = postIndex
 		default:
@@ -2536,7 +6937,7 @@ func (m *BlockTOC) Unmarshal(dAtA []byte) error {
 			if err != nil {
 				return err
 			}
-			if skippy < 0 {
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
 				return ErrInvalidLengthFormat
 			}
 			if (iNdEx + skippy) > l {
@@ -2554,6 +6955,7 @@ func (m *BlockTOC) Unmarshal(dAtA []byte) error {
 func skipFormat(dAtA []byte) (n int, err error) {
 	l := len(dAtA)
 	iNdEx := 0
+	depth := 0
 	for iNdEx < l {
 		var wire uint64
 		for shift := uint(0); ; shift += 7 {
@@ -2585,10 +6987,8 @@ func skipFormat(dAtA []byte) (n int, err error) {
 					break
 				}
 			}
-			return iNdEx, nil
 		case 1:
 			iNdEx += 8
-			return iNdEx, nil
 		case 2:
 			var length int
 			for shift := uint(0); ; shift += 7 {
@@ -2605,99 +7005,34 @@ func skipFormat(dAtA []byte) (n int, err error) {
 					break
 				}
 			}
-			iNdEx += length
 			if length < 0 {
 				return 0, ErrInvalidLengthFormat
 			}
-			return iNdEx, nil
+			iNdEx += length
 		case 3:
-			for {
-				var innerWire uint64
-				var start int = iNdEx
-				for shift := uint(0); ; shift += 7 {
-					if shift >= 64 {
-						return 0, ErrIntOverflowFormat
-					}
-					if iNdEx >= l {
-						return 0, io.ErrUnexpectedEOF
-					}
-					b := dAtA[iNdEx]
-					iNdEx++
-					innerWire |= (uint64(b) & 0x7F) << shift
-					if b < 0x80 {
-						break
-					}
-				}
-				innerWireType := int(innerWire & 0x7)
-				if innerWireType == 4 {
-					break
-				}
-				next, err := skipFormat(dAtA[start:])
-				if err != nil {
-					return 0, err
-				}
-				iNdEx = start + next
-			}
-			return iNdEx, nil
+			depth++
 		case 4:
-			return iNdEx, nil
+			if depth == 0 {
+				return 0, ErrUnexpectedEndOfGroupFormat
+			}
+			depth--
 		case 5:
 			iNdEx += 4
-			return iNdEx, nil
 		default:
 			return 0, fmt.Errorf("proto: illegal wireType %d", wireType)
 		}
+		if iNdEx < 0 {
+			return 0, ErrInvalidLengthFormat
+		}
+		if depth == 0 {
+			return iNdEx, nil
+		}
 	}
-	panic("unreachable")
+	return 0, io.ErrUnexpectedEOF
 }
 
 var (
-	ErrInvalidLengthFormat = fmt.Errorf("proto: negative length found during unmarshaling")
-	ErrIntOverflowFormat   = fmt.Errorf("proto: integer overflow")
+	ErrInvalidLengthFormat        = fmt.Errorf("proto: negative length found during unmarshaling")
+	ErrIntOverflowFormat          = fmt.Errorf("proto: integer overflow")
+	ErrUnexpectedEndOfGroupFormat = fmt.Errorf("proto: unexpected end of group")
 )
-
-func init() { proto.RegisterFile("format.proto", fileDescriptorFormat) }
-
-var fileDescriptorFormat = []byte{
-	// 636 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x74, 0x54, 0xcd, 0x6e, 0xd3, 0x40,
-	0x10, 0xce, 0xda, 0x71, 0x62, 0x4f, 0xd2, 0x2a, 0xac, 0xf8, 0x31, 0x54, 0x32, 0xc1, 0x08, 0x29,
-	0xa0, 0xaa, 0x88, 0x70, 0x41, 0xdc, 0xda, 0x42, 0xa1, 0x12, 0x52, 0x91, 0x93, 0x03, 0xb7, 0xe0,
-	0xd8, 0x93, 0xd6, 0x4a, 0xec, 0xb5, 0xec, 0x0d, 0x92, 0x2b, 0x0e, 0x3c, 0x42, 0x1f, 0x83, 0xa7,
-	0xe0, 0xcc, 0xb1, 0x47, 0x8e, 0xd4, 0x5c, 0x38, 0xf6, 0x11, 0xd0, 0xae, 0xed, 0x34, 0x8e, 0xc4,
-	0x6d, 0xe6, 0xfb, 0x66, 0x76, 0x66, 0xbe, 0x19, 0x1b, 0xba, 0x33, 0x96, 0x84, 0x2e, 0xdf, 0x8b,
-	0x13, 0xc6, 0x19, 0x6d, 0x15, 0x9e, 0x7d, 0x41, 0xc0, 0x18, 0x9f, 0x1c, 0xbe, 0x47, 0xd7, 0xc7,
-	0x84, 0xde, 0x81, 0xd6, 0x1c, 0xb3, 0x49, 0xe0, 0x9b, 0xa4, 0x4f, 0x06, 0x5d, 0x47, 0x9b, 0x63,
-	0x76, 0xec, 0x53, 0x0b, 0xc0, 0x63, 0x61, 0x9c, 0x60, 0x9a, 0xa2, 0x6f, 0x2a, 0x7d, 0x32, 0xd0,
-	0x9d, 0x35, 0x84, 0xf6, 0x40, 0x4d, 0x97, 0xa1, 0xa9, 0xca, 0x1c, 0x61, 0xd2, 0xfb, 0xa0, 0x73,
-	0xe6, 0x4d, 0xd2, 0xe0, 0x1c, 0xcd, 0x66, 0x9f, 0x0c, 0x54, 0xa7, 0xcd, 0x99, 0x37, 0x0a, 0xce,
-	0x91, 0x3e, 0x84, 0xce, 0x74, 0xc1, 0xbc, 0x79, 0x5a, 0xb0, 0x9a, 0x64, 0xa1, 0x80, 0x44, 0x80,
-	0x7d, 0x0f, 0xb4, 0x03, 0xe1, 0xd1, 0x6d, 0x50, 0x56, 0x9d, 0x28, 0x81, 0x6f, 0x7f, 0x06, 0x5d,
-	0x12, 0x23, 0xe4, 0xf4, 0x09, 0xb4, 0x8a, 0x14, 0x93, 0xf4, 0xd5, 0x41, 0x67, 0xb8, 0xb5, 0x57,
-	0x8e, 0x27, 0x23, 0x9c, 0x92, 0xac, 0x3a, 0x53, 0x6e, 0x3a, 0xdb, 0x01, 0x63, 0x9a, 0x71, 0x2c,
-	0x8a, 0xab, 0xb2, 0xb8, 0x2e, 0x00, 0x59, 0xfa, 0x08, 0xf4, 0x71, 0x10, 0xe2, 0x28, 0x46, 0x8f,
-	0x9a, 0xd0, 0x4e, 0xd1, 0x63, 0x91, 0x9f, 0xca, 0x16, 0x54, 0xa7, 0x72, 0x69, 0x1f, 0x3a, 0x91,
-	0x1b, 0xb1, 0x8a, 0x15, 0x8f, 0x6b, 0xce, 0x3a, 0x64, 0xff, 0x50, 0x40, 0x7b, 0x1b, 0xf1, 0x24,
-	0xab, 0x97, 0x23, 0xf5, 0x72, 0xb4, 0x0f, 0x4d, 0x9e, 0xc5, 0x28, 0x5f, 0xd8, 0x1e, 0x76, 0xab,
-	0x11, 0xc6, 0x59, 0x8c, 0x8e, 0x64, 0x28, 0x85, 0xe6, 0x99, 0x9b, 0x9e, 0x95, 0xd2, 0x4a, 0x9b,
-	0x0e, 0x56, 0xa3, 0x0b, 0x65, 0x3b, 0xc3, 0x5e, 0x6d, 0xf4, 0x11, 0xf2, 0xd5, 0xf4, 0xb7, 0x41,
-	0x5b, 0x46, 0x6e, 0x58, 0x88, 0x6c, 0x38, 0x85, 0x23, 0xd0, 0x53, 0x89, 0xb6, 0x0a, 0xf4, 0xb4,
-	0x42, 0x67, 0x0b, 0xf7, 0x34, 0x35, 0xdb, 0x72, 0x9c, 0xc2, 0x11, 0xf5, 0x63, 0x4c, 0x42, 0x53,
-	0x97, 0xa0, 0xb4, 0xe9, 0x73, 0x00, 0x2f, 0x41, 0x97, 0xa3, 0x3f, 0x71, 0xb9, 0x69, 0xd4, 0x7b,
-	0xa8, 0xe4, 0x73, 0x8c, 0x32, 0x66, 0x9f, 0xd3, 0x17, 0xd0, 0x09, 0x99, 0x1f, 0xcc, 0x82, 0x22,
-	0x03, 0xfe, 0x93, 0x01, 0x55, 0xd0, 0x3e, 0xb7, 0xbf, 0x82, 0x3a, 0x3e, 0x39, 0xa4, 0xbb, 0xa0,
-	0xc5, 0x2e, 0x3f, 0xab, 0x96, 0x7c, 0x77, 0x95, 0x73, 0x72, 0xb8, 0xf7, 0x51, 0x10, 0x52, 0x64,
-	0xa7, 0x08, 0x7a, 0xf0, 0x0e, 0xe0, 0x06, 0x14, 0xab, 0x9f, 0x63, 0x26, 0x35, 0x37, 0x1c, 0x61,
-	0xd2, 0xc7, 0xa0, 0x7d, 0x71, 0x17, 0xcb, 0x42, 0xef, 0xb5, 0x93, 0x29, 0x1f, 0x91, 0xdc, 0x6b,
-	0xe5, 0x15, 0xb1, 0x97, 0x60, 0x48, 0x2d, 0x8f, 0xa3, 0x19, 0xdb, 0xbc, 0xc2, 0xfa, 0x46, 0x95,
-	0x8d, 0x8d, 0xee, 0x80, 0x21, 0xbe, 0x8b, 0xda, 0x75, 0x09, 0x40, 0x92, 0x16, 0x40, 0x82, 0x33,
-	0x4c, 0x30, 0xf2, 0x30, 0x2d, 0x3f, 0x8b, 0x35, 0xc4, 0xfe, 0x54, 0xde, 0xb7, 0x98, 0xfc, 0xe9,
-	0xc6, 0x7d, 0xdf, 0xaa, 0x2d, 0x59, 0x34, 0xb6, 0xda, 0xf2, 0x23, 0xe8, 0x4e, 0x17, 0x8c, 0x85,
-	0x93, 0x59, 0xb0, 0xe0, 0x98, 0x94, 0xc7, 0xde, 0x91, 0xd8, 0x91, 0x84, 0x9e, 0x0d, 0xa1, 0x29,
-	0x8e, 0x8a, 0x6e, 0x81, 0x31, 0x66, 0xe1, 0x74, 0xc4, 0x59, 0x84, 0xbd, 0x06, 0xd5, 0xa1, 0x79,
-	0x14, 0x2c, 0xb0, 0x47, 0x68, 0x1b, 0xd4, 0x37, 0x41, 0xd2, 0x53, 0x04, 0xf4, 0x21, 0x88, 0xe6,
-	0x3d, 0xf5, 0x60, 0xf7, 0xf2, 0xca, 0x6a, 0xfc, 0xba, 0xb2, 0x1a, 0xd7, 0x57, 0x16, 0xf9, 0x96,
-	0x5b, 0xe4, 0x7b, 0x6e, 0x91, 0x9f, 0xb9, 0x45, 0x2e, 0x73, 0x8b, 0xfc, 0xce, 0x2d, 0xf2, 0x37,
-	0xb7, 0x1a, 0xd7, 0xb9, 0x45, 0x2e, 0xfe, 0x58, 0x8d, 0x69, 0x4b, 0xfe, 0x56, 0x5e, 0xfe, 0x0b,
-	0x00, 0x00, 0xff, 0xff, 0xf1, 0x9d, 0xce, 0xc3, 0x66, 0x04, 0x00, 0x00,
-}
@@ -1,17 +1,16 @@
 package yfs
 
 import (
-	"bytes"
+	"context"
 	"errors"
 	"io"
-	"io/ioutil"
 	"os"
 	"path/filepath"
 	"sync"
 
 	"github.com/aclements/go-rabin/rabin"
 	"github.com/evanphx/yfs/format"
-	"github.com/golang/crypto/blake2b"
+	"golang.org/x/crypto/ed25519"
 )
 
 const (
@@ -21,8 +20,6 @@ const (
 	MaxBlock     = 32 << 10
 )
 
-var table = rabin.NewTable(rabin.Poly64, Window)
-
 const (
 	File = 1
 	Dir  = 2
@@ -41,17 +38,57 @@ type FS struct {
 	tocPath string
 
 	toclock   sync.Mutex
-	toc       *format.TOC
+	toc       *tocTree
 	tocBlocks *format.BlockTOC
+	tocBloom  *bloomFilter
 
 	tocSet *format.BlockSet
 
-	blockslock sync.RWMutex
-	blocks     *format.BlockTOC
+	blockslock  sync.RWMutex
+	blocks      *format.BlockTOC
+	blocksBloom *bloomFilter
+
+	// blocksLoaded is set once blocks.idx has been read, which read
+	// only FSs put off until HasBlock needs it.
+	blocksLoaded bool
+
+	bloomFP float64
+
+	usePacks bool
+	packSize int64
+
+	config   format.Config
+	chunking *ChunkParams
+	table    *rabin.Table
+
+	codec     byte
+	zstdLevel int
+
+	adaptive  bool
+	compStats *compressionStats
+
+	encKey        *Key
+	masterKey     *Key
+	prevMasterKey *Key
+	convergent    bool
+
+	signingKey  ed25519.PrivateKey
+	trustedKeys []ed25519.PublicKey
 
 	tocHeader format.TOCHeader
 
 	blockAccess blockAccess
+
+	readOnly      bool
+	tocCacheNodes int
+
+	blockCacheSize int64
+	blockCache     *blockCache
+
+	readAhead    int
+	writeWorkers int
+
+	progress func(Progress)
 }
 
 const bufferSize = 1024
@@ -84,18 +121,10 @@ func putBlockBuf(buf []byte) {
 var DefaultHead = "primary"
 
 func NewFS(root string, opts ...Option) (*FS, error) {
-	err := os.MkdirAll(filepath.Join(root, "heads"), 0755)
-	if err != nil {
-		return nil, err
-	}
-
 	fs := &FS{
 		root:    root,
 		tocPath: filepath.Join("heads", DefaultHead),
-	}
-
-	fs.toc = &format.TOC{
-		Paths: make(map[string]*format.Entry),
+		bloomFP: DefaultBloomFalsePositive,
 	}
 
 	fs.tocBlocks = &format.BlockTOC{}
@@ -106,26 +135,125 @@ func NewFS(root string, opts ...Option) (*FS, error) {
 		opt(fs)
 	}
 
+	if !fs.readOnly {
+		err := os.MkdirAll(filepath.Join(root, "heads"), 0755)
+		if err != nil {
+			return nil, err
+		}
+	} else if fs.tocCacheNodes == 0 {
+		fs.tocCacheNodes = DefaultTOCCacheNodes
+	}
+
+	err := fs.checkSigningKeys()
+	if err != nil {
+		return nil, err
+	}
+
+	fs.compStats = &compressionStats{}
+	fs.blockAccess.stats = fs.compStats
+
+	if fs.blockCache == nil && fs.blockCacheSize > 0 {
+		fs.blockCache = newBlockCache(fs.blockCacheSize)
+	}
+
+	fs.blockAccess.cache = fs.blockCache
+
 	fs.blockAccess.root = filepath.Join(root, "blocks")
-	err = os.MkdirAll(fs.blockAccess.root, 0755)
+
+	if !fs.readOnly {
+		err = os.MkdirAll(fs.blockAccess.root, 0755)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err = fs.readConfig()
 	if err != nil {
 		return nil, err
 	}
 
-	err = fs.readTOC()
+	fs.tocHeader.Version = fs.config.Version
+
+	fs.toc = newTOCTree(&fs.blockAccess, fs.config.Version >= 3, fs.tocCacheNodes)
+
+	if fs.chunking.Algorithm == format.Rabin {
+		fs.table = rabin.NewTable(fs.chunking.Polynomial, fs.chunking.Window)
+	}
+
+	err = fs.setupCompression()
+	if err != nil {
+		return nil, err
+	}
+
+	err = fs.setupEncryption()
 	if err != nil {
 		return nil, err
 	}
 
-	err = fs.readBlocksTOC()
+	// The pack index is encrypted, so packs are opened once the
+	// transforms are set up.
+	if fs.blockAccess.store == nil {
+		packs := filepath.Join(root, "packs")
+
+		if _, err := os.Stat(filepath.Join(packs, "index")); err == nil {
+			fs.usePacks = true
+		} else if fs.readOnly {
+			// Without an index every block is loose, and a read only FS
+			// mustn't create one.
+			fs.usePacks = false
+		}
+
+		if fs.usePacks {
+			fs.blockAccess.store, err = newPackStore(packs, fileStore{root: fs.blockAccess.root}, fs.packSize, &fs.blockAccess)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	// An upgrade from version 0 was interrupted, so blocks and heads may
+	// have either frame. New heads are written as upgraded ones.
+	if fs.config.Upgrading {
+		fs.blockAccess.legacy, err = fs.legacyBlockAccess()
+		if err != nil {
+			return nil, err
+		}
+
+		fs.tocHeader.Version = 1
+	}
+
+	err = fs.readTOC()
 	if err != nil {
 		return nil, err
 	}
 
+	// Read only FSs never add blocks, so only need the index for
+	// HasBlock.
+	if !fs.readOnly {
+		err = fs.readBlocksTOC()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if fs.tocBloom == nil {
+		fs.tocBloom = buildBloomFilter(fs.tocBlocks.Blocks, fs.bloomFP)
+	}
+
+	if fs.blocksBloom == nil {
+		fs.blocksBloom = buildBloomFilter(fs.blocks.Blocks, fs.bloomFP)
+	}
+
 	return fs, nil
 }
 
+// Txn starts a transaction. Transactions on read only FSs can't write,
+// whatever write is.
 func (f *FS) Txn(write bool) *Txn {
+	if f.readOnly {
+		write = false
+	}
+
 	if write {
 		f.txnlock.Lock()
 	}
@@ -137,9 +265,11 @@ func (f *FS) Txn(write bool) *Txn {
 		root:    f.root,
 		tocPath: f.tocPath,
 
-		toc:       f.toc,
-		tocBlocks: f.tocBlocks,
-		blocks:    f.blocks,
+		toc:         f.toc,
+		tocBlocks:   f.tocBlocks,
+		tocBloom:    f.tocBloom,
+		blocks:      f.blocks,
+		blocksBloom: f.blocksBloom,
 
 		tocSet: f.tocSet,
 
@@ -161,6 +291,23 @@ func (f *FS) CopyFile(path string, of *os.File) error {
 	return txn.CopyFile(path, of)
 }
 
+// CopyFileContext is CopyFile, stopping between blocks once ctx is done.
+// Nothing is committed if it stops.
+func (f *FS) CopyFileContext(ctx context.Context, path string, of *os.File) error {
+	txn := f.Txn(true)
+
+	err := txn.CopyFileContext(ctx, path, of)
+	if err != nil {
+		if rerr := txn.rollback(); rerr != nil {
+			return rerr
+		}
+
+		return err
+	}
+
+	return txn.CommitContext(ctx)
+}
+
 func (f *FS) WriteFile(path string, r io.Reader) error {
 	txn := f.Txn(true)
 
@@ -169,14 +316,32 @@ func (f *FS) WriteFile(path string, r io.Reader) error {
 	return txn.WriteFile(path, r)
 }
 
+// WriteFileContext is WriteFile, stopping between blocks once ctx is done.
+// Nothing is committed if it stops.
+func (f *FS) WriteFileContext(ctx context.Context, path string, r io.Reader) error {
+	txn := f.Txn(true)
+
+	err := txn.WriteFileContext(ctx, path, r)
+	if err != nil {
+		if rerr := txn.rollback(); rerr != nil {
+			return rerr
+		}
+
+		return err
+	}
+
+	return txn.CommitContext(ctx)
+}
+
 var (
 	ErrCompressionMismatch = errors.New("compression setting mismatched")
 	ErrWrongEncryptionKey  = errors.New("wrong encryption key provided")
 	ErrCorruptTOC          = errors.New("table of contents is corrupt")
+	ErrHeadAuthentication  = errors.New("head failed authentication")
 )
 
 func (f *FS) readTOC() error {
-	data, err := ioutil.ReadFile(filepath.Join(f.root, f.tocPath))
+	hf, err := f.unmarshalTOC(filepath.Join(f.root, f.tocPath), !f.readOnly)
 	if err != nil {
 		if os.IsNotExist(err) {
 			return nil
@@ -184,116 +349,98 @@ func (f *FS) readTOC() error {
 		return err
 	}
 
-	var fheader format.TOCHeader
-
-	sz := data[0]
-
-	err = fheader.Unmarshal(data[1 : 1+sz])
-	if err != nil {
-		return err
-	}
-
-	if f.tocHeader.Compressed != fheader.Compressed {
-		return ErrCompressionMismatch
-	}
+	f.toc = hf.toc
 
-	if !bytes.Equal(f.tocHeader.KeyId, fheader.KeyId) {
-		return ErrWrongEncryptionKey
-	}
+	f.tocSet = hf.set
 
-	dataSum := blake2b.Sum256(data[256 : 256+fheader.TocSize])
-
-	if !bytes.Equal(fheader.Sum, dataSum[:]) {
-		return ErrCorruptTOC
+	// Read only FSs load all of the TOC as it's used, and don't need
+	// the head's block list.
+	if f.readOnly {
+		return nil
 	}
 
-	var (
-		tocSize   = fheader.TocSize
-		blockSize = fheader.BlocksSize
-	)
-
-	buf, err := f.blockAccess.readTransform(data[256 : 256+tocSize])
+	err = hf.toc.Load()
 	if err != nil {
 		return err
 	}
 
-	var set format.BlockSet
+	f.tocBlocks = hf.blocks
 
-	err = set.Unmarshal(buf)
-	if err != nil {
-		return err
-	}
-
-	setData, err := f.blockAccess.readSet(&set)
-	if err != nil {
-		return err
+	f.tocBloom = loadBloomFilter(hf.blocks.BloomFilter, len(hf.blocks.Blocks))
+	if f.tocBloom == nil {
+		f.tocBloom = buildBloomFilter(hf.blocks.Blocks, f.bloomFP)
 	}
 
-	err = f.toc.Unmarshal(setData)
-	if err != nil {
-		return err
-	}
-
-	var bs format.BlockTOC
-
-	bsData := data[256+tocSize : 256+tocSize+blockSize]
+	return nil
+}
 
-	data, err = f.blockAccess.readTransform(bsData)
+func (f *FS) readBlocksTOC() error {
+	err := f.readBlockIndex(f.blocks)
 	if err != nil {
 		return err
 	}
 
-	err = bs.Unmarshal(data)
-	if err != nil {
-		return err
+	f.blocksBloom = loadBloomFilter(f.blocks.BloomFilter, len(f.blocks.Blocks))
+	if f.blocksBloom == nil {
+		f.blocksBloom = buildBloomFilter(f.blocks.Blocks, f.bloomFP)
 	}
 
-	f.tocSet = &set
-
-	f.tocBlocks = &bs
+	f.blocksLoaded = true
 
 	return nil
 }
 
-func (f *FS) readBlocksTOC() error {
-	of, err := os.Open(filepath.Join(f.root, "blocks.idx"))
-	if err != nil {
-		if os.IsNotExist(err) {
-			return nil
+// HasBlock reports whether the repository already stores the block with
+// the given id. The blocks.idx bloom filter answers most negative lookups
+// without scanning the index, so this is cheap enough to call for every
+// block when deciding what needs to be copied to another repository.
+// Read only FSs read blocks.idx the first time it's called.
+func (f *FS) HasBlock(id BlockId) (bool, error) {
+	f.blockslock.RLock()
+	loaded := f.blocksLoaded
+	f.blockslock.RUnlock()
+
+	if !loaded {
+		err := f.loadBlocksTOC()
+		if err != nil {
+			return false, err
 		}
-
-		return nil
 	}
 
-	defer of.Close()
-
-	buf := buffers.Get().([]byte)
+	f.blockslock.RLock()
+	defer f.blockslock.RUnlock()
 
-	stat, err := of.Stat()
-	if err != nil {
-		return err
+	if !f.blocksBloom.MayContain(id) {
+		return false, nil
 	}
 
-	tocSize := int(stat.Size())
-
-	if len(buf) < tocSize {
-		buf = make([]byte, tocSize+64)
-	}
+	_, ok := f.blocks.FindBlock(id)
+	return ok, nil
+}
 
-	defer buffers.Put(buf)
+// loadBlocksTOC reads blocks.idx for a read only FS, unless another
+// call already has.
+func (f *FS) loadBlocksTOC() error {
+	f.blockslock.Lock()
+	defer f.blockslock.Unlock()
 
-	_, err = of.Read(buf[:tocSize])
-	if err != nil {
-		return err
+	if f.blocksLoaded {
+		return nil
 	}
 
-	return f.blocks.Unmarshal(buf[:tocSize])
+	return f.readBlocksTOC()
 }
 
 func (f *FS) ReaderFor(path string) (io.Reader, error) {
 	return f.Txn(false).ReaderFor(path)
 }
 
+// ReaderForContext is ReaderFor, with reads failing with ctx's error once
+// ctx is done.
+func (f *FS) ReaderForContext(ctx context.Context, path string) (io.Reader, error) {
+	return f.Txn(false).ReaderForContext(ctx, path)
+}
+
 func (f *FS) WriterFor(path string) (io.WriteCloser, error) {
 	txn := f.Txn(true)
 
@@ -323,6 +470,28 @@ func (fs *FS) CreateSnapshot(name string) error {
 	return txn.CreateSnapshot(name)
 }
 
-func (fs *FS) ReadSnapshot(name string) (*FS, error) {
-	return NewFS(fs.root, WithSettingsFrom(fs), WithHead(name))
+// Repack rewrites pack files that contain unreferenced blocks, reclaiming
+// the space left behind by garbage collection. It does nothing for
+// repositories that store blocks as individual files.
+func (f *FS) Repack() error {
+	if f.readOnly {
+		return ErrReadOnly
+	}
+
+	f.txnlock.Lock()
+	defer f.txnlock.Unlock()
+
+	ps, ok := f.blockAccess.store.(*packStore)
+	if !ok {
+		return nil
+	}
+
+	return ps.repack()
+}
+
+// ReadSnapshot opens the snapshot name with the settings of fs, and
+// then opts, such as WithTrustedKeys.
+func (fs *FS) ReadSnapshot(name string, opts ...Option) (*FS, error) {
+	opts = append([]Option{WithSettingsFrom(fs), WithHead(name)}, opts...)
+	return NewFS(fs.root, opts...)
 }
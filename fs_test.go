@@ -2,6 +2,10 @@ package yfs
 
 import (
 	"bytes"
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
 	"io"
 	"io/ioutil"
 	"math/rand"
@@ -9,10 +13,14 @@ import (
 	"path/filepath"
 	"strings"
 	"testing"
+	"testing/iotest"
 
+	"github.com/evanphx/yfs/format"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/vektra/neko"
+	"golang.org/x/crypto/curve25519"
+	"golang.org/x/crypto/ed25519"
 )
 
 func TestFS(t *testing.T) {
@@ -81,8 +89,8 @@ func TestFS(t *testing.T) {
 		err = fs.WriteFile("bar", bytes.NewReader(barData))
 		require.NoError(t, err)
 
-		fooBlocks := fs.toc.Paths["foo"]
-		barBlocks := fs.toc.Paths["bar"]
+		fooBlocks := entryOf(t, fs, "foo")
+		barBlocks := entryOf(t, fs, "bar")
 
 		common := len(fooBlocks.Blocks.Blocks) - 1
 
@@ -199,7 +207,7 @@ func TestFS(t *testing.T) {
 		err = fs.WriteFile("foo", bytes.NewReader(zeros))
 		require.NoError(t, err)
 
-		id := fs.toc.Paths["foo"].Blocks.Blocks[0].Id
+		id := entryOf(t, fs, "foo").Blocks.Blocks[0].Id
 
 		var rogueBA blockAccess
 		rogueBA.root = filepath.Join(path, "blocks")
@@ -235,7 +243,7 @@ func TestFS(t *testing.T) {
 		err = fs.WriteFile("foo", bytes.NewReader(com))
 		require.NoError(t, err)
 
-		fooBlocks := fs.toc.Paths["foo"]
+		fooBlocks := entryOf(t, fs, "foo")
 
 		assert.True(t, len(fooBlocks.Blocks.Blocks) > 1)
 
@@ -265,7 +273,7 @@ func TestFS(t *testing.T) {
 		err = fs.WriteFile("foo", bytes.NewReader(com))
 		require.NoError(t, err)
 
-		fooBlocks := fs.toc.Paths["foo"]
+		fooBlocks := entryOf(t, fs, "foo")
 
 		assert.True(t, len(fooBlocks.Blocks.Blocks) > 1)
 
@@ -281,84 +289,2162 @@ func TestFS(t *testing.T) {
 		assert.Equal(t, com, result)
 	})
 
-	n.It("provides a writer to write data to a path", func(t *testing.T) {
-		fs, err := NewFS(path)
+	n.It("can store keys in passphrase protected key files", func(t *testing.T) {
+		key := GenerateKey()
+
+		fs, err := NewFS(path, WithEncryption(key))
 		require.NoError(t, err)
 
-		w, err := fs.WriterFor("foo")
+		err = fs.WriteFile("foo", strings.NewReader("hello"))
 		require.NoError(t, err)
 
-		n, err := w.Write([]byte("hello"))
+		keyPath := filepath.Join(path, "key")
+
+		err = SaveKeyFile(keyPath, key, []byte("open sesame"))
 		require.NoError(t, err)
 
-		assert.Equal(t, n, 5)
+		_, err = LoadKeyFile(keyPath, []byte("open barley"))
+		assert.Equal(t, ErrWrongPassphrase, err)
 
-		err = w.Close()
+		kf, err := os.Open(keyPath)
 		require.NoError(t, err)
 
-		r, err := fs.ReaderFor("foo")
+		id, err := KeyFileId(kf)
+		kf.Close()
+		require.NoError(t, err)
+
+		assert.Equal(t, key.Id(), id)
+
+		loaded, err := LoadKeyFile(keyPath, []byte("open sesame"))
+		require.NoError(t, err)
+
+		assert.Equal(t, key.Id(), loaded.Id())
+
+		fs2, err := NewFS(path, WithEncryption(loaded))
+		require.NoError(t, err)
+
+		r, err := fs2.ReaderFor("foo")
 		require.NoError(t, err)
 
 		data, err := ioutil.ReadAll(r)
 		require.NoError(t, err)
 
-		assert.Equal(t, []byte("hello"), data)
+		assert.Equal(t, "hello", string(data))
 	})
 
-	n.It("supports ReadFrom in the write path", func(t *testing.T) {
-		fs, err := NewFS(path)
+	n.It("rejects key files with tampered parameters", func(t *testing.T) {
+		key := GenerateKey()
+
+		var buf bytes.Buffer
+
+		require.NoError(t, key.WriteKeyFile(&buf, []byte("open sesame")))
+
+		tampers := map[string]func(kf *format.KeyFile){
+			"no time":      func(kf *format.KeyFile) { kf.ArgonTime = 0 },
+			"long time":    func(kf *format.KeyFile) { kf.ArgonTime = 1 << 30 },
+			"no threads":   func(kf *format.KeyFile) { kf.ArgonThreads = 0 },
+			"many threads": func(kf *format.KeyFile) { kf.ArgonThreads = 256 },
+			"no memory":    func(kf *format.KeyFile) { kf.ArgonMemory = 0 },
+			"huge memory":  func(kf *format.KeyFile) { kf.ArgonMemory = 1 << 31 },
+			"short nonce":  func(kf *format.KeyFile) { kf.Nonce = kf.Nonce[:4] },
+		}
+
+		for name, tamper := range tampers {
+			var kf format.KeyFile
+			require.NoError(t, kf.Unmarshal(buf.Bytes()))
+
+			tamper(&kf)
+
+			data, err := kf.Marshal()
+			require.NoError(t, err)
+
+			_, err = ReadKeyFile(bytes.NewReader(data), []byte("open sesame"))
+			assert.Equal(t, ErrUnknownKeyFile, err, name)
+		}
+
+		loaded, err := ReadKeyFile(bytes.NewReader(buf.Bytes()), []byte("open sesame"))
 		require.NoError(t, err)
 
-		w, err := fs.WriterFor("foo")
+		assert.Equal(t, key.Id(), loaded.Id())
+	})
+
+	n.It("opens an encrypted repository with any key that has a slot", func(t *testing.T) {
+		laptop := GenerateKey()
+		server := GenerateKey()
+		escrow := GenerateKey()
+
+		fs, err := NewFS(path, WithEncryption(laptop))
 		require.NoError(t, err)
 
-		rw, ok := w.(io.ReaderFrom)
-		require.True(t, ok)
+		err = fs.WriteFile("foo", strings.NewReader("hello"))
+		require.NoError(t, err)
 
-		n, err := rw.ReadFrom(strings.NewReader("hello"))
+		_, err = NewFS(path, WithEncryption(server))
+		assert.Equal(t, ErrWrongEncryptionKey, err)
+
+		require.NoError(t, fs.AddKeySlot("server", server.Id()))
+		require.NoError(t, fs.AddKeySlot("escrow", escrow.Id()))
+
+		assert.Equal(t, ErrKeySlotExists, fs.AddKeySlot("again", server.Id()))
+
+		slots, err := fs.KeySlots()
 		require.NoError(t, err)
 
-		assert.Equal(t, int64(5), n)
+		require.Equal(t, 3, len(slots))
+		assert.Equal(t, "server", slots[1].Name)
+		assert.Equal(t, escrow.Id(), slots[2].Id)
 
-		err = w.Close()
+		for _, key := range []*Key{laptop, server, escrow} {
+			fs2, err := NewFS(path, WithEncryption(key))
+			require.NoError(t, err)
+
+			r, err := fs2.ReaderFor("foo")
+			require.NoError(t, err)
+
+			data, err := ioutil.ReadAll(r)
+			require.NoError(t, err)
+
+			assert.Equal(t, "hello", string(data))
+		}
+
+		require.NoError(t, fs.RemoveKeySlot(server.Id()))
+
+		_, err = NewFS(path, WithEncryption(server))
+		assert.Equal(t, ErrWrongEncryptionKey, err)
+
+		require.NoError(t, fs.RemoveKeySlot(escrow.Id()))
+
+		assert.Equal(t, ErrLastKeySlot, fs.RemoveKeySlot(laptop.Id()))
+		assert.Equal(t, ErrKeySlotNotFound, fs.RemoveKeySlot(server.Id()))
+
+		_, err = NewFS(path, WithEncryption(laptop))
 		require.NoError(t, err)
+	})
 
-		r, err := fs.ReaderFor("foo")
+	n.It("encrypts identical blocks identically in convergent mode", func(t *testing.T) {
+		key := GenerateKey()
+
+		fs, err := NewFS(path, WithEncryption(key), WithConvergentEncryption())
+		require.NoError(t, err)
+
+		err = fs.WriteFile("foo", strings.NewReader("hello"))
+		require.NoError(t, err)
+
+		id := entryOf(t, fs, "foo").Blocks.Blocks[0].Id
+
+		first, err := fs.blockAccess.blockStore().Get(id)
+		require.NoError(t, err)
+
+		fs2, err := NewFS(path, WithEncryption(key), WithConvergentEncryption())
+		require.NoError(t, err)
+
+		_, err = fs2.blockAccess.writeBlock(id, []byte("hello"), nil)
+		require.NoError(t, err)
+
+		second, err := fs2.blockAccess.blockStore().Get(id)
+		require.NoError(t, err)
+
+		assert.Equal(t, first, second)
+
+		fs3, err := NewFS(path, WithEncryption(key))
+		require.NoError(t, err)
+
+		_, err = fs3.blockAccess.writeBlock(id, []byte("hello"), nil)
+		require.NoError(t, err)
+
+		third, err := fs3.blockAccess.blockStore().Get(id)
+		require.NoError(t, err)
+
+		assert.NotEqual(t, first, third)
+
+		r, err := fs3.ReaderFor("foo")
 		require.NoError(t, err)
 
 		data, err := ioutil.ReadAll(r)
 		require.NoError(t, err)
 
-		assert.Equal(t, []byte("hello"), data)
+		assert.Equal(t, "hello", string(data))
 	})
 
-	n.It("supports WriterTo in the read path", func(t *testing.T) {
+	n.It("only encrypts identical blocks identically with the same compression", func(t *testing.T) {
+		key := GenerateKey()
+
+		block := bytes.Repeat([]byte("convergent "), 1000)
+
+		stored := func(opts ...Option) []byte {
+			fs, err := NewFS(path, append([]Option{WithEncryption(key), WithConvergentEncryption()}, opts...)...)
+			require.NoError(t, err)
+
+			id := BlockId(blockSum(block))
+
+			_, err = fs.blockAccess.writeBlock(id, block, nil)
+			require.NoError(t, err)
+
+			data, err := fs.blockAccess.blockStore().Get(id)
+			require.NoError(t, err)
+
+			out, err := fs.blockAccess.readBlock(id)
+			require.NoError(t, err)
+
+			assert.Equal(t, block, out)
+
+			return data
+		}
+
+		zstd := stored(WithZstd(3))
+
+		assert.Equal(t, zstd, stored(WithZstd(3)))
+		assert.NotEqual(t, zstd, stored(WithLZ4()))
+		assert.NotEqual(t, zstd, stored())
+	})
+
+	n.It("detects encrypted blocks swapped between ids", func(t *testing.T) {
+		key := GenerateKey()
+
+		fs, err := NewFS(path, WithEncryption(key))
+		require.NoError(t, err)
+
+		err = fs.WriteFile("foo", strings.NewReader("hello"))
+		require.NoError(t, err)
+
+		err = fs.WriteFile("bar", strings.NewReader("goodbye"))
+		require.NoError(t, err)
+
+		fooId := entryOf(t, fs, "foo").Blocks.Blocks[0].Id
+		barId := entryOf(t, fs, "bar").Blocks.Blocks[0].Id
+
+		store := fs.blockAccess.blockStore()
+
+		raw, err := store.Get(fooId)
+		require.NoError(t, err)
+
+		require.NoError(t, store.Put(barId, raw))
+
+		_, err = fs.blockAccess.readBlock(barId)
+		assert.Error(t, err)
+		assert.NotEqual(t, ErrCorruptBlock, err)
+
+		r, err := fs.ReaderFor("bar")
+		require.NoError(t, err)
+
+		_, err = ioutil.ReadAll(r)
+		assert.Error(t, err)
+	})
+
+	n.It("detects tampering with the head header", func(t *testing.T) {
+		key := GenerateKey()
+
+		fs, err := NewFS(path, WithEncryption(key), WithLZ4())
+		require.NoError(t, err)
+
+		err = fs.WriteFile("foo", strings.NewReader("hello"))
+		require.NoError(t, err)
+
+		headPath := filepath.Join(path, "heads", DefaultHead)
+
+		orig, err := ioutil.ReadFile(headPath)
+		require.NoError(t, err)
+
+		tamper := func(change func(h *format.TOCHeader)) error {
+			header, sections := splitHead(t, fs, orig)
+
+			change(header)
+
+			hdata, err := marshalHeadHeader(header)
+			require.NoError(t, err)
+
+			data := append(hdata, sections...)
+
+			require.NoError(t, ioutil.WriteFile(headPath, data, 0644))
+
+			_, err = NewFS(path, WithEncryption(key), WithLZ4())
+			return err
+		}
+
+		err = tamper(func(h *format.TOCHeader) {
+			h.Compressed = false
+		})
+		assert.Equal(t, ErrHeadAuthentication, err)
+
+		err = tamper(func(h *format.TOCHeader) {
+			h.Mac = nil
+		})
+		assert.Equal(t, ErrHeadAuthentication, err)
+
+		err = tamper(func(h *format.TOCHeader) {
+			h.BlocksSize--
+		})
+		assert.Equal(t, ErrHeadAuthentication, err)
+
+		err = tamper(func(h *format.TOCHeader) {})
+		assert.NoError(t, err)
+	})
+
+	n.It("frames head headers of any size", func(t *testing.T) {
 		fs, err := NewFS(path)
 		require.NoError(t, err)
 
 		err = fs.WriteFile("foo", strings.NewReader("hello"))
 		require.NoError(t, err)
 
-		fs2, err := NewFS(path)
+		data, err := ioutil.ReadFile(filepath.Join(path, "heads", DefaultHead))
 		require.NoError(t, err)
 
-		r, err := fs2.ReaderFor("foo")
+		assert.Equal(t, byte(headVarintFrame), data[0])
+
+		header, sections := splitHead(t, fs, data)
+
+		header.Signer = bytes.Repeat([]byte{1}, 1000)
+
+		hdata, err := marshalHeadHeader(header)
 		require.NoError(t, err)
 
-		wr, ok := r.(io.WriterTo)
-		require.True(t, ok)
+		parsed, _ := splitHead(t, fs, append(hdata, sections...))
+		assert.Equal(t, header, parsed)
 
-		var buf bytes.Buffer
+		header.Version = 1
 
-		n, err := wr.WriteTo(&buf)
+		_, err = marshalHeadHeader(header)
+		assert.Equal(t, ErrHeadTooLarge, err)
+
+		header.Signer = nil
+
+		hdata, err = marshalHeadHeader(header)
 		require.NoError(t, err)
 
-		assert.Equal(t, int64(5), n)
+		assert.Equal(t, headFixedSize, len(hdata))
 
-		data := buf.String()
+		parsed, rest := splitHead(t, fs, append(hdata, sections...))
+		assert.Equal(t, header, parsed)
+		assert.Equal(t, sections, rest)
+	})
 
-		assert.Equal(t, "hello", data)
+	n.It("signs heads and checks them against trusted keys", func(t *testing.T) {
+		pub, priv, err := ed25519.GenerateKey(nil)
+		require.NoError(t, err)
+
+		otherPub, _, err := ed25519.GenerateKey(nil)
+		require.NoError(t, err)
+
+		fs, err := NewFS(path)
+		require.NoError(t, err)
+
+		err = fs.WriteFile("foo", strings.NewReader("hello"))
+		require.NoError(t, err)
+
+		err = fs.CreateSnapshot("unsigned")
+		require.NoError(t, err)
+
+		_, err = NewFS(path, WithTrustedKeys(pub))
+		assert.Equal(t, ErrUnsignedHead, err)
+
+		fs, err = NewFS(path, WithSigningKey(priv))
+		require.NoError(t, err)
+
+		err = fs.WriteFile("bar", strings.NewReader("goodbye"))
+		require.NoError(t, err)
+
+		err = fs.CreateSnapshot("signed")
+		require.NoError(t, err)
+
+		fs2, err := NewFS(path, WithTrustedKeys(otherPub, pub))
+		require.NoError(t, err)
+
+		r, err := fs2.ReaderFor("bar")
+		require.NoError(t, err)
+
+		data, err := ioutil.ReadAll(r)
+		require.NoError(t, err)
+
+		assert.Equal(t, "goodbye", string(data))
+
+		_, err = fs2.ReadSnapshot("signed")
+		require.NoError(t, err)
+
+		_, err = fs2.ReadSnapshot("unsigned")
+		assert.Equal(t, ErrUnsignedHead, err)
+
+		_, err = NewFS(path, WithTrustedKeys(otherPub))
+		assert.Equal(t, ErrUntrustedSignature, err)
+
+		plain, err := NewFS(path)
+		require.NoError(t, err)
+
+		_, err = plain.ReadSnapshot("signed", WithTrustedKeys(otherPub))
+		assert.Equal(t, ErrUntrustedSignature, err)
+
+		headPath := filepath.Join(path, "heads", DefaultHead)
+
+		orig, err := ioutil.ReadFile(headPath)
+		require.NoError(t, err)
+
+		header, sections := splitHead(t, fs, orig)
+
+		header.Compressed = !header.Compressed
+
+		hdata, err := marshalHeadHeader(header)
+		require.NoError(t, err)
+
+		require.NoError(t, ioutil.WriteFile(headPath, append(hdata, sections...), 0644))
+
+		_, err = NewFS(path, WithTrustedKeys(pub))
+		assert.Equal(t, ErrUntrustedSignature, err)
 	})
 
-	n.Meow()
+	n.It("proves paths are included in a snapshot's merkle root", func(t *testing.T) {
+		fs, err := NewFS(path)
+		require.NoError(t, err)
+
+		empty, err := fs.MerkleRoot()
+		require.NoError(t, err)
+
+		names := []string{"a", "b", "c", "d", "e", "f", "g"}
+
+		for _, name := range names {
+			err = fs.WriteFile(name, strings.NewReader("contents of "+name))
+			require.NoError(t, err)
+		}
+
+		err = fs.CreateSnapshot("day1")
+		require.NoError(t, err)
+
+		root, err := fs.MerkleRoot()
+		require.NoError(t, err)
+
+		assert.NotEqual(t, empty, root)
+
+		snap, err := fs.ReadSnapshot("day1")
+		require.NoError(t, err)
+
+		snapRoot, err := snap.MerkleRoot()
+		require.NoError(t, err)
+
+		assert.Equal(t, root, snapRoot)
+
+		for _, name := range names {
+			proof, err := snap.ProveInclusion(name)
+			require.NoError(t, err)
+
+			assert.Equal(t, entryOf(t, fs, name).Hash, proof.Hash)
+			assert.True(t, VerifyInclusion(root, proof), name)
+		}
+
+		proof, err := snap.ProveInclusion("c")
+		require.NoError(t, err)
+
+		forged := *proof
+		forged.Hash = entryOf(t, fs, "d").Hash
+		assert.False(t, VerifyInclusion(root, &forged))
+
+		forged = *proof
+		forged.Path = "d"
+		assert.False(t, VerifyInclusion(root, &forged))
+
+		forged = *proof
+		forged.Index++
+		assert.False(t, VerifyInclusion(root, &forged))
+
+		_, err = snap.ProveInclusion("z")
+		assert.Equal(t, os.ErrNotExist, err)
+
+		err = fs.WriteFile("c", strings.NewReader("changed"))
+		require.NoError(t, err)
+
+		changed, err := fs.MerkleRoot()
+		require.NoError(t, err)
+
+		assert.NotEqual(t, root, changed)
+		assert.False(t, VerifyInclusion(changed, proof))
+	})
+
+	n.It("encrypts the block index", func(t *testing.T) {
+		key := GenerateKey()
+
+		fs, err := NewFS(path, WithEncryption(key))
+		require.NoError(t, err)
+
+		err = fs.WriteFile("foo", strings.NewReader("hello"))
+		require.NoError(t, err)
+
+		id := entryOf(t, fs, "foo").Blocks.Blocks[0].Id
+
+		idxPath := filepath.Join(path, "blocks.idx")
+
+		data, err := ioutil.ReadFile(idxPath)
+		require.NoError(t, err)
+
+		assert.False(t, bytes.Contains(data, id))
+
+		fs2, err := NewFS(path, WithEncryption(key))
+		require.NoError(t, err)
+
+		assert.True(t, hasBlock(t, fs2, id))
+
+		data[len(data)-1] ^= 1
+		require.NoError(t, ioutil.WriteFile(idxPath, data, 0644))
+
+		_, err = NewFS(path, WithEncryption(key))
+		assert.Equal(t, ErrCorruptBlockIndex, err)
+
+		plain, err := fs.blocks.Marshal()
+		require.NoError(t, err)
+
+		require.NoError(t, ioutil.WriteFile(idxPath, plain, 0644))
+
+		_, err = NewFS(path, WithEncryption(key))
+		assert.Equal(t, ErrCorruptBlockIndex, err)
+	})
+
+	n.It("encrypts the pack index", func(t *testing.T) {
+		key := GenerateKey()
+
+		fs, err := NewFS(path, WithEncryption(key), WithPackFiles(0))
+		require.NoError(t, err)
+
+		err = fs.WriteFile("foo", strings.NewReader("hello"))
+		require.NoError(t, err)
+
+		id := entryOf(t, fs, "foo").Blocks.Blocks[0].Id
+
+		idxPath := filepath.Join(path, "packs", "index")
+
+		data, err := ioutil.ReadFile(idxPath)
+		require.NoError(t, err)
+
+		assert.False(t, bytes.Contains(data, id))
+
+		err = fs.RotateKey(nil, RotateReencrypt, nil)
+		require.NoError(t, err)
+
+		data, err = ioutil.ReadFile(idxPath)
+		require.NoError(t, err)
+
+		assert.False(t, bytes.Contains(data, id))
+
+		fs2, err := NewFS(path, WithEncryption(key), WithPackFiles(0))
+		require.NoError(t, err)
+
+		r, err := fs2.ReaderFor("foo")
+		require.NoError(t, err)
+
+		out, err := ioutil.ReadAll(r)
+		require.NoError(t, err)
+
+		assert.Equal(t, "hello", string(out))
+
+		data[len(data)-1] ^= 1
+		require.NoError(t, ioutil.WriteFile(idxPath, data, 0644))
+
+		_, err = NewFS(path, WithEncryption(key), WithPackFiles(0))
+		assert.Error(t, err)
+	})
+
+	n.It("reads a plaintext block index", func(t *testing.T) {
+		fs, err := NewFS(path)
+		require.NoError(t, err)
+
+		err = fs.WriteFile("foo", strings.NewReader("hello"))
+		require.NoError(t, err)
+
+		plain, err := fs.blocks.Marshal()
+		require.NoError(t, err)
+
+		idxPath := filepath.Join(path, "blocks.idx")
+
+		require.NoError(t, ioutil.WriteFile(idxPath, plain, 0644))
+
+		fs2, err := NewFS(path)
+		require.NoError(t, err)
+
+		assert.Equal(t, len(fs.blocks.Blocks), len(fs2.blocks.Blocks))
+
+		require.NoError(t, os.Remove(idxPath))
+		require.NoError(t, os.Mkdir(idxPath, 0755))
+
+		_, err = NewFS(path)
+		assert.Error(t, err)
+	})
+
+	n.It("rotates a key by rewrapping the master key", func(t *testing.T) {
+		oldKey := GenerateKey()
+		newKey := GenerateKey()
+
+		fs, err := NewFS(path, WithEncryption(oldKey))
+		require.NoError(t, err)
+
+		err = fs.WriteFile("foo", strings.NewReader("hello"))
+		require.NoError(t, err)
+
+		id := entryOf(t, fs, "foo").Blocks.Blocks[0].Id
+
+		before, err := fs.blockAccess.blockStore().Get(id)
+		require.NoError(t, err)
+
+		err = fs.RotateKey(newKey, RotateRewrap, nil)
+		require.NoError(t, err)
+
+		after, err := fs.blockAccess.blockStore().Get(id)
+		require.NoError(t, err)
+
+		assert.Equal(t, before, after)
+
+		_, err = NewFS(path, WithEncryption(oldKey))
+		assert.Equal(t, ErrWrongEncryptionKey, err)
+
+		fs2, err := NewFS(path, WithEncryption(newKey))
+		require.NoError(t, err)
+
+		r, err := fs2.ReaderFor("foo")
+		require.NoError(t, err)
+
+		data, err := ioutil.ReadAll(r)
+		require.NoError(t, err)
+
+		assert.Equal(t, "hello", string(data))
+	})
+
+	n.It("rotates the master key by re-encrypting every block", func(t *testing.T) {
+		owner := GenerateKey()
+		staff := GenerateKey()
+
+		com := make([]byte, AverageBlock*10)
+
+		_, err := rand.Read(com)
+		require.NoError(t, err)
+
+		fs, err := NewFS(path, WithEncryption(owner))
+		require.NoError(t, err)
+
+		err = fs.WriteFile("foo", bytes.NewReader(com))
+		require.NoError(t, err)
+
+		err = fs.CreateSnapshot("snap1")
+		require.NoError(t, err)
+
+		require.NoError(t, fs.AddKeySlot("staff", staff.Id()))
+
+		// What a departing member of staff could have kept.
+		staffFS, err := NewFS(path, WithEncryption(staff))
+		require.NoError(t, err)
+
+		oldMaster := staffFS.masterKey
+
+		require.NoError(t, fs.RemoveKeySlot(staff.Id()))
+
+		var done, total int
+
+		err = fs.RotateKey(nil, RotateReencrypt, func(d, t int) {
+			done, total = d, t
+		})
+		require.NoError(t, err)
+
+		assert.True(t, total > len(fs.blocks.Blocks))
+		assert.Equal(t, total, done)
+
+		assert.NotEqual(t, oldMaster.Id(), fs.masterKey.Id())
+
+		for _, blk := range fs.blocks.Blocks {
+			raw, err := fs.blockAccess.blockStore().Get(blk.Id)
+			require.NoError(t, err)
+
+			_, _, err = (&cryptReader{key: oldMaster}).TransformAD(raw, fs.blockAccess.blockAD(blk.Id))
+			assert.Error(t, err)
+		}
+
+		_, err = NewFS(path, WithEncryption(oldMaster))
+		assert.Equal(t, ErrWrongEncryptionKey, err)
+
+		fs2, err := NewFS(path, WithEncryption(owner))
+		require.NoError(t, err)
+
+		assert.Equal(t, fs.masterKey.Id(), fs2.tocHeader.KeyId)
+
+		for _, name := range []string{DefaultHead, "snap1"} {
+			snap, err := fs2.ReadSnapshot(name)
+			require.NoError(t, err)
+
+			r, err := snap.ReaderFor("foo")
+			require.NoError(t, err)
+
+			data, err := ioutil.ReadAll(r)
+			require.NoError(t, err)
+
+			assert.Equal(t, com, data)
+		}
+	})
+
+	n.It("resumes an interrupted re-encryption", func(t *testing.T) {
+		key := GenerateKey()
+
+		fs, err := NewFS(path, WithEncryption(key))
+		require.NoError(t, err)
+
+		err = fs.WriteFile("foo", strings.NewReader("hello"))
+		require.NoError(t, err)
+
+		ks, err := fs.readKeySlots()
+		require.NoError(t, err)
+
+		// Stop right after the new master key is recorded.
+		require.NoError(t, fs.beginRotation(ks, nil))
+
+		fs2, err := NewFS(path, WithEncryption(key))
+		require.NoError(t, err)
+
+		err = fs2.WriteFile("bar", strings.NewReader("goodbye"))
+		require.NoError(t, err)
+
+		r, err := fs2.ReaderFor("foo")
+		require.NoError(t, err)
+
+		data, err := ioutil.ReadAll(r)
+		require.NoError(t, err)
+
+		assert.Equal(t, "hello", string(data))
+
+		assert.Equal(t, ErrRotationInProgress, fs2.AddKeySlot("other", GenerateKey().Id()))
+
+		err = fs2.RotateKey(nil, RotateReencrypt, nil)
+		require.NoError(t, err)
+
+		ks, err = fs2.readKeySlots()
+		require.NoError(t, err)
+
+		assert.Nil(t, ks.NextMaster)
+
+		fs3, err := NewFS(path, WithEncryption(key))
+		require.NoError(t, err)
+
+		assert.Nil(t, fs3.prevMasterKey)
+
+		for path, content := range map[string]string{"foo": "hello", "bar": "goodbye"} {
+			r, err := fs3.ReaderFor(path)
+			require.NoError(t, err)
+
+			data, err := ioutil.ReadAll(r)
+			require.NoError(t, err)
+
+			assert.Equal(t, content, string(data))
+		}
+	})
+
+	n.It("provides a writer to write data to a path", func(t *testing.T) {
+		fs, err := NewFS(path)
+		require.NoError(t, err)
+
+		w, err := fs.WriterFor("foo")
+		require.NoError(t, err)
+
+		n, err := w.Write([]byte("hello"))
+		require.NoError(t, err)
+
+		assert.Equal(t, n, 5)
+
+		err = w.Close()
+		require.NoError(t, err)
+
+		r, err := fs.ReaderFor("foo")
+		require.NoError(t, err)
+
+		data, err := ioutil.ReadAll(r)
+		require.NoError(t, err)
+
+		assert.Equal(t, []byte("hello"), data)
+	})
+
+	n.It("supports ReadFrom in the write path", func(t *testing.T) {
+		fs, err := NewFS(path)
+		require.NoError(t, err)
+
+		w, err := fs.WriterFor("foo")
+		require.NoError(t, err)
+
+		rw, ok := w.(io.ReaderFrom)
+		require.True(t, ok)
+
+		n, err := rw.ReadFrom(strings.NewReader("hello"))
+		require.NoError(t, err)
+
+		assert.Equal(t, int64(5), n)
+
+		err = w.Close()
+		require.NoError(t, err)
+
+		r, err := fs.ReaderFor("foo")
+		require.NoError(t, err)
+
+		data, err := ioutil.ReadAll(r)
+		require.NoError(t, err)
+
+		assert.Equal(t, []byte("hello"), data)
+	})
+
+	n.It("supports WriterTo in the read path", func(t *testing.T) {
+		fs, err := NewFS(path)
+		require.NoError(t, err)
+
+		err = fs.WriteFile("foo", strings.NewReader("hello"))
+		require.NoError(t, err)
+
+		fs2, err := NewFS(path)
+		require.NoError(t, err)
+
+		r, err := fs2.ReaderFor("foo")
+		require.NoError(t, err)
+
+		wr, ok := r.(io.WriterTo)
+		require.True(t, ok)
+
+		var buf bytes.Buffer
+
+		n, err := wr.WriteTo(&buf)
+		require.NoError(t, err)
+
+		assert.Equal(t, int64(5), n)
+
+		data := buf.String()
+
+		assert.Equal(t, "hello", data)
+	})
+
+	n.It("stores bloom filters for fast negative block lookups", func(t *testing.T) {
+		fs, err := NewFS(path)
+		require.NoError(t, err)
+
+		err = fs.WriteFile("foo", strings.NewReader("hello"))
+		require.NoError(t, err)
+
+		fs2, err := NewFS(path)
+		require.NoError(t, err)
+
+		assert.NotEmpty(t, fs2.tocBlocks.BloomFilter)
+		assert.NotEmpty(t, fs2.blocks.BloomFilter)
+
+		id := entryOf(t, fs2, "foo").Blocks.Blocks[0].Id
+
+		assert.True(t, fs2.tocBloom.MayContain(id))
+		assert.True(t, hasBlock(t, fs2, id))
+
+		missing := make([]byte, len(id))
+		_, err = rand.Read(missing)
+		require.NoError(t, err)
+
+		assert.False(t, hasBlock(t, fs2, missing))
+
+		bf := newBloomFilter(1000, 0.01)
+
+		for i := 0; i < 1000; i++ {
+			id := make([]byte, 32)
+			rand.Read(id)
+			bf.Add(id)
+		}
+
+		var fps int
+
+		for i := 0; i < 10000; i++ {
+			id := make([]byte, 32)
+			rand.Read(id)
+			if bf.MayContain(id) {
+				fps++
+			}
+		}
+
+		assert.True(t, fps < 300, "false positive rate too high: %d/10000", fps)
+	})
+
+	n.It("grows the bloom filters as blocks are added", func(t *testing.T) {
+		fs, err := NewFS(path)
+		require.NoError(t, err)
+
+		data := make([]byte, AverageBlock*200)
+		_, err = rand.Read(data)
+		require.NoError(t, err)
+
+		txn := fs.Txn(true)
+
+		err = txn.WriteFile("foo", bytes.NewReader(data))
+		require.NoError(t, err)
+
+		require.True(t, len(txn.blocks.Blocks) > 100)
+
+		for _, bf := range []*bloomFilter{txn.blocksBloom, txn.tocBloom} {
+			assert.False(t, bf.full())
+		}
+
+		for _, blk := range txn.blocks.Blocks {
+			assert.True(t, txn.blocksBloom.MayContain(blk.Id))
+		}
+
+		var fps int
+
+		for i := 0; i < 10000; i++ {
+			id := make([]byte, 32)
+			rand.Read(id)
+			if txn.blocksBloom.MayContain(id) {
+				fps++
+			}
+		}
+
+		assert.True(t, fps < 300, "false positive rate too high: %d/10000", fps)
+
+		require.NoError(t, txn.Commit())
+	})
+
+	n.It("can store blocks in pack files", func(t *testing.T) {
+		fs, err := NewFS(path, WithPackFiles(0))
+		require.NoError(t, err)
+
+		keep := make([]byte, AverageBlock*10)
+		_, err = rand.Read(keep)
+		require.NoError(t, err)
+
+		drop := make([]byte, AverageBlock*30)
+		_, err = rand.Read(drop)
+		require.NoError(t, err)
+
+		err = fs.WriteFile("keep", bytes.NewReader(keep))
+		require.NoError(t, err)
+
+		err = fs.WriteFile("drop", bytes.NewReader(drop))
+		require.NoError(t, err)
+
+		fds, err := ioutil.ReadDir(filepath.Join(path, "blocks"))
+		require.NoError(t, err)
+
+		assert.Equal(t, 0, len(fds))
+
+		packPath := filepath.Join(path, "packs", "00000001.pack")
+
+		before, err := os.Stat(packPath)
+		require.NoError(t, err)
+
+		err = fs.RemoveFile("drop")
+		require.NoError(t, err)
+
+		err = fs.Repack()
+		require.NoError(t, err)
+
+		_, err = os.Stat(packPath)
+		assert.True(t, os.IsNotExist(err))
+
+		packs, err := filepath.Glob(filepath.Join(path, "packs", "*.pack"))
+		require.NoError(t, err)
+		require.Equal(t, 1, len(packs))
+
+		after, err := os.Stat(packs[0])
+		require.NoError(t, err)
+
+		assert.True(t, after.Size() < before.Size())
+
+		fs2, err := NewFS(path)
+		require.NoError(t, err)
+
+		r, err := fs2.ReaderFor("keep")
+		require.NoError(t, err)
+
+		data, err := ioutil.ReadAll(r)
+		require.NoError(t, err)
+
+		assert.Equal(t, keep, data)
+	})
+
+	n.It("records chunking parameters in the repository", func(t *testing.T) {
+		params := ChunkParams{
+			Window:       32,
+			AverageBlock: 16 << 10,
+			MinBlock:     2 << 10,
+			MaxBlock:     64<<10 - 1,
+		}
+
+		fs, err := NewFS(path, WithChunking(params))
+		require.NoError(t, err)
+
+		data := make([]byte, AverageBlock*100)
+		_, err = rand.Read(data)
+		require.NoError(t, err)
+
+		err = fs.WriteFile("foo", bytes.NewReader(data))
+		require.NoError(t, err)
+
+		for _, blk := range fs.blocks.Blocks {
+			assert.True(t, blk.ByteSize <= int64(params.MaxBlock))
+		}
+
+		fs2, err := NewFS(path)
+		require.NoError(t, err)
+
+		assert.Equal(t, 16<<10, fs2.chunking.AverageBlock)
+
+		_, err = NewFS(path, WithChunking(DefaultChunkParams))
+		assert.Equal(t, ErrChunkingMismatch, err)
+
+		params.AverageBlock = 3000
+
+		_, err = NewFS(filepath.Join(root, "other"), WithChunking(params))
+		assert.Error(t, err)
+	})
+
+	n.It("supports chunks larger than 64KiB with lz4", func(t *testing.T) {
+		params := ChunkParams{
+			Window:       64,
+			AverageBlock: 64 << 10,
+			MinBlock:     16 << 10,
+			MaxBlock:     256 << 10,
+		}
+
+		fs, err := NewFS(path, WithChunking(params), WithLZ4())
+		require.NoError(t, err)
+
+		data := bytes.Repeat([]byte("compressible "), 100000)
+
+		err = fs.WriteFile("foo", bytes.NewReader(data))
+		require.NoError(t, err)
+
+		var large bool
+
+		for _, blk := range fs.blocks.Blocks {
+			if blk.ByteSize > maxFrameBlock {
+				large = true
+				assert.True(t, blk.CompSize < blk.ByteSize)
+			}
+		}
+
+		assert.True(t, large)
+
+		fs2, err := NewFS(path, WithLZ4())
+		require.NoError(t, err)
+
+		r, err := fs2.ReaderFor("foo")
+		require.NoError(t, err)
+
+		result, err := ioutil.ReadAll(r)
+		require.NoError(t, err)
+
+		assert.Equal(t, data, result)
+	})
+
+	n.It("can chunk with FastCDC", func(t *testing.T) {
+		params := DefaultChunkParams
+		params.Algorithm = format.FastCDC
+
+		fs, err := NewFS(path, WithChunking(params))
+		require.NoError(t, err)
+
+		data := make([]byte, AverageBlock*100)
+		_, err = rand.Read(data)
+		require.NoError(t, err)
+
+		err = fs.WriteFile("foo", bytes.NewReader(data))
+		require.NoError(t, err)
+
+		fs2, err := NewFS(path)
+		require.NoError(t, err)
+
+		assert.Equal(t, format.FastCDC, fs2.chunking.Algorithm)
+
+		r, err := fs2.ReaderFor("foo")
+		require.NoError(t, err)
+
+		result, err := ioutil.ReadAll(r)
+		require.NoError(t, err)
+
+		assert.Equal(t, data, result)
+	})
+
+	n.It("tags blocks with their codec so codecs can be mixed", func(t *testing.T) {
+		fs, err := NewFS(path, WithLZ4())
+		require.NoError(t, err)
+
+		lz4Data := append(make([]byte, AverageBlock*2), []byte("lz4")...)
+
+		err = fs.WriteFile("lz4", bytes.NewReader(lz4Data))
+		require.NoError(t, err)
+
+		fs2, err := NewFS(path, WithZstd(9))
+		require.NoError(t, err)
+
+		zstdData := append(bytes.Repeat([]byte("zstd"), AverageBlock), []byte("zstd")...)
+
+		err = fs2.WriteFile("zstd", bytes.NewReader(zstdData))
+		require.NoError(t, err)
+
+		store := fileStore{root: filepath.Join(path, "blocks")}
+
+		raw, err := store.Get(entryOf(t, fs2, "lz4").Blocks.Blocks[0].Id)
+		require.NoError(t, err)
+		assert.Equal(t, codecLZ4Framed, raw[0])
+
+		raw, err = store.Get(entryOf(t, fs2, "zstd").Blocks.Blocks[0].Id)
+		require.NoError(t, err)
+		assert.Equal(t, codecZstd, raw[0])
+
+		fs3, err := NewFS(path)
+		require.NoError(t, err)
+
+		for name, expected := range map[string][]byte{"lz4": lz4Data, "zstd": zstdData} {
+			r, err := fs3.ReaderFor(name)
+			require.NoError(t, err)
+
+			data, err := ioutil.ReadAll(r)
+			require.NoError(t, err)
+
+			assert.Equal(t, expected, data)
+		}
+	})
+
+	n.It("can train a zstd dictionary from existing blocks", func(t *testing.T) {
+		fs, err := NewFS(path, WithZstd(0))
+		require.NoError(t, err)
+
+		rng := rand.New(rand.NewSource(1))
+
+		record := func(i int) []byte {
+			var buf bytes.Buffer
+
+			for buf.Len() < AverageBlock*8 {
+				buf.WriteString(`{"service":"yfs","level":"info","message":"stored block","block":`)
+				buf.WriteString(strings.Repeat("x", rng.Intn(20)))
+				buf.WriteString("}\n")
+			}
+
+			return buf.Bytes()
+		}
+
+		for i := 0; i < 20; i++ {
+			err = fs.WriteFile("log"+string(rune('a'+i)), bytes.NewReader(record(i)))
+			require.NoError(t, err)
+		}
+
+		err = fs.TrainZstdDictionary(100)
+		require.NoError(t, err)
+
+		require.Equal(t, 1, len(fs.config.ZstdDictionaries))
+
+		data := record(100)
+
+		err = fs.WriteFile("after", bytes.NewReader(data))
+		require.NoError(t, err)
+
+		fs2, err := NewFS(path)
+		require.NoError(t, err)
+
+		r, err := fs2.ReaderFor("after")
+		require.NoError(t, err)
+
+		result, err := ioutil.ReadAll(r)
+		require.NoError(t, err)
+
+		assert.Equal(t, data, result)
+	})
+
+	n.It("skips compressing data that looks incompressible", func(t *testing.T) {
+		fs, err := NewFS(path, WithZstd(0), WithAdaptiveCompression())
+		require.NoError(t, err)
+
+		movie := make([]byte, AverageBlock*20)
+		_, err = rand.Read(movie)
+		require.NoError(t, err)
+
+		random := make([]byte, AverageBlock*20)
+		_, err = rand.Read(random)
+		require.NoError(t, err)
+
+		text := []byte(strings.Repeat("the quick brown fox jumps over the lazy dog\n", 2000))
+
+		files := map[string][]byte{
+			"movie.mp4":  movie,
+			"random.bin": random,
+			"notes.txt":  text,
+		}
+
+		for name, data := range files {
+			err = fs.WriteFile(name, bytes.NewReader(data))
+			require.NoError(t, err)
+		}
+
+		stats := fs.CompressionStats()
+
+		assert.True(t, stats.Skipped >= int64(len(movie)+len(random)*3/4), "skipped %d", stats.Skipped)
+		assert.True(t, stats.Attempted >= int64(len(text)))
+		assert.True(t, stats.Saved > int64(len(text)/2))
+
+		fs2, err := NewFS(path)
+		require.NoError(t, err)
+
+		for name, expected := range files {
+			r, err := fs2.ReaderFor(name)
+			require.NoError(t, err)
+
+			data, err := ioutil.ReadAll(r)
+			require.NoError(t, err)
+
+			assert.Equal(t, expected, data)
+		}
+	})
+
+	n.It("rejects zstd on repositories with legacy block frames", func(t *testing.T) {
+		fs, err := NewFS(path)
+		require.NoError(t, err)
+
+		fs.config.BlockFrame = blockFrameLegacy
+		require.NoError(t, fs.writeConfig())
+
+		_, err = NewFS(path, WithZstd(0))
+		assert.Equal(t, ErrTaggedFramesRequired, err)
+	})
+
+	n.It("stores the TOC as a tree and rewrites only changed nodes", func(t *testing.T) {
+		fs, err := NewFS(path)
+		require.NoError(t, err)
+
+		txn := fs.Txn(true)
+
+		for i := 0; i < 3000; i++ {
+			err = txn.WriteFile(fmt.Sprintf("file%04d", i), strings.NewReader(fmt.Sprintf("contents %d", i)))
+			require.NoError(t, err)
+		}
+
+		require.NoError(t, txn.Commit())
+
+		require.False(t, fs.toc.root.leaf())
+
+		before := make(map[string]bool)
+		for _, c := range fs.toc.root.children {
+			before[string(c.set.Sum)] = true
+		}
+
+		err = fs.WriteFile("file1500", strings.NewReader("changed"))
+		require.NoError(t, err)
+
+		changed := 0
+		for _, c := range fs.toc.root.children {
+			if !before[string(c.set.Sum)] {
+				changed++
+			}
+		}
+
+		assert.Equal(t, 1, changed)
+
+		fs2, err := NewFS(path)
+		require.NoError(t, err)
+
+		assert.Equal(t, 0, loadedLeaves(fs2))
+
+		r, err := fs2.ReaderFor("file0042")
+		require.NoError(t, err)
+
+		data, err := ioutil.ReadAll(r)
+		require.NoError(t, err)
+
+		assert.Equal(t, "contents 42", string(data))
+		assert.Equal(t, 1, loadedLeaves(fs2))
+
+		txn = fs2.Txn(true)
+
+		for i := 0; i < 3000; i++ {
+			if i != 7 {
+				require.NoError(t, txn.RemoveFile(fmt.Sprintf("file%04d", i)))
+			}
+		}
+
+		require.NoError(t, txn.Commit())
+
+		fs3, err := NewFS(path)
+		require.NoError(t, err)
+
+		assert.True(t, fs3.toc.root.leaf())
+
+		r, err = fs3.ReaderFor("file0007")
+		require.NoError(t, err)
+
+		data, err = ioutil.ReadAll(r)
+		require.NoError(t, err)
+
+		assert.Equal(t, "contents 7", string(data))
+
+		_, err = fs3.ReaderFor("file1500")
+		assert.Equal(t, os.ErrNotExist, err)
+	})
+
+	n.It("opens repositories read only, loading the TOC as it's used", func(t *testing.T) {
+		fs, err := NewFS(path)
+		require.NoError(t, err)
+
+		txn := fs.Txn(true)
+
+		for i := 0; i < 2100; i++ {
+			err = txn.WriteFile(fmt.Sprintf("file%04d", i), strings.NewReader(fmt.Sprintf("contents %d", i)))
+			require.NoError(t, err)
+		}
+
+		require.NoError(t, txn.Commit())
+
+		require.NoError(t, fs.CreateSnapshot("snap"))
+
+		ro, err := NewFS(path, WithReadOnly(), WithTOCCacheNodes(2))
+		require.NoError(t, err)
+
+		assert.False(t, ro.toc.root.loaded)
+		assert.Equal(t, 0, len(ro.blocks.Blocks))
+
+		// blocks.idx is read once HasBlock needs it.
+		assert.True(t, hasBlock(t, ro, entryOf(t, fs, "file0042").Blocks.Blocks[0].Id))
+		assert.False(t, hasBlock(t, ro, BlockId(blockSum([]byte("missing")))))
+
+		snap, err := ro.ReadSnapshot("snap")
+		require.NoError(t, err)
+
+		for _, i := range []int{0, 600, 1200, 1800, 2099, 0} {
+			r, err := snap.ReaderFor(fmt.Sprintf("file%04d", i))
+			require.NoError(t, err)
+
+			data, err := ioutil.ReadAll(r)
+			require.NoError(t, err)
+
+			assert.Equal(t, fmt.Sprintf("contents %d", i), string(data))
+			assert.True(t, loadedLeaves(snap) <= 2)
+		}
+
+		err = snap.WriteFile("new", strings.NewReader("data"))
+		assert.Equal(t, ErrReadOnly, err)
+
+		err = ro.RemoveFile("file0000")
+		assert.Equal(t, ErrReadOnly, err)
+
+		assert.Equal(t, ErrReadOnly, ro.Upgrade())
+
+		missing := filepath.Join(root, "missing")
+
+		_, err = NewFS(missing, WithReadOnly())
+		require.NoError(t, err)
+
+		_, err = os.Stat(missing)
+		assert.True(t, os.IsNotExist(err))
+	})
+
+	n.It("opens repositories on read only media", func(t *testing.T) {
+		for _, packs := range []bool{false, true} {
+			dir := filepath.Join(root, "media")
+
+			var opts []Option
+			if packs {
+				opts = append(opts, WithPackFiles(0))
+			}
+
+			fs, err := NewFS(dir, opts...)
+			require.NoError(t, err)
+
+			err = fs.WriteFile("foo", strings.NewReader("hello"))
+			require.NoError(t, err)
+
+			chmodTree(t, dir, false)
+
+			ro, err := NewFS(dir, WithReadOnly(), WithPackFiles(0))
+			require.NoError(t, err)
+
+			r, err := ro.ReaderFor("foo")
+			require.NoError(t, err)
+
+			data, err := ioutil.ReadAll(r)
+			require.NoError(t, err)
+
+			assert.Equal(t, "hello", string(data))
+
+			_, err = os.Stat(filepath.Join(dir, "packs"))
+			assert.Equal(t, packs, err == nil)
+
+			chmodTree(t, dir, true)
+			require.NoError(t, os.RemoveAll(dir))
+		}
+	})
+
+	n.It("caches decoded blocks", func(t *testing.T) {
+		fs, err := NewFS(path, WithEncryption(GenerateKey()), WithBlockCache(1<<20))
+		require.NoError(t, err)
+
+		data := make([]byte, 200000)
+		rand.New(rand.NewSource(2)).Read(data)
+
+		err = fs.WriteFile("bar", bytes.NewReader(data))
+		require.NoError(t, err)
+
+		require.NoError(t, fs.CreateSnapshot("snap"))
+
+		blocks := len(entryOf(t, fs, "bar").Blocks.Blocks)
+
+		read := func(fs *FS) {
+			r, err := fs.ReaderFor("bar")
+			require.NoError(t, err)
+
+			out, err := ioutil.ReadAll(r)
+			require.NoError(t, err)
+
+			assert.Equal(t, data, out)
+		}
+
+		before := fs.BlockCacheStats()
+
+		read(fs)
+
+		after := fs.BlockCacheStats()
+		assert.Equal(t, int64(blocks), after.Misses-before.Misses)
+		assert.Equal(t, before.Hits, after.Hits)
+
+		read(fs)
+
+		before, after = after, fs.BlockCacheStats()
+		assert.Equal(t, int64(blocks), after.Hits-before.Hits)
+		assert.Equal(t, before.Misses, after.Misses)
+
+		snap, err := fs.ReadSnapshot("snap")
+		require.NoError(t, err)
+
+		before = snap.BlockCacheStats()
+
+		read(snap)
+
+		after = snap.BlockCacheStats()
+		assert.Equal(t, int64(blocks), after.Hits-before.Hits)
+		assert.Equal(t, before.Misses, after.Misses)
+	})
+
+	n.It("reads blocks ahead in parallel", func(t *testing.T) {
+		key := GenerateKey()
+
+		fs, err := NewFS(path, WithEncryption(key), WithLZ4(), WithReadAhead(4))
+		require.NoError(t, err)
+
+		data := make([]byte, 200000)
+		rand.New(rand.NewSource(3)).Read(data)
+
+		err = fs.WriteFile("bar", bytes.NewReader(data))
+		require.NoError(t, err)
+
+		err = fs.WriteFile("empty", bytes.NewReader(nil))
+		require.NoError(t, err)
+
+		r, err := fs.ReaderFor("bar")
+		require.NoError(t, err)
+
+		out, err := ioutil.ReadAll(r)
+		require.NoError(t, err)
+
+		assert.Equal(t, data, out)
+
+		r, err = fs.ReaderFor("bar")
+		require.NoError(t, err)
+
+		var buf bytes.Buffer
+
+		n, err := io.Copy(&buf, r)
+		require.NoError(t, err)
+
+		assert.Equal(t, int64(len(data)), n)
+		assert.Equal(t, data, buf.Bytes())
+
+		r, err = fs.ReaderFor("empty")
+		require.NoError(t, err)
+
+		out, err = ioutil.ReadAll(r)
+		require.NoError(t, err)
+
+		assert.Equal(t, 0, len(out))
+
+		blocks := entryOf(t, fs, "bar").Blocks.Blocks
+		require.True(t, len(blocks) > 6)
+
+		require.NoError(t, fs.blockAccess.removeBlock(blocks[5].Id))
+
+		r, err = fs.ReaderFor("bar")
+		require.NoError(t, err)
+
+		_, err = io.Copy(ioutil.Discard, r)
+		assert.Error(t, err)
+	})
+
+	n.It("writes blocks with a pool of workers", func(t *testing.T) {
+		key := GenerateKey()
+
+		fs, err := NewFS(path, WithEncryption(key), WithLZ4(), WithWriteWorkers(4))
+		require.NoError(t, err)
+
+		// Repeat the data so some blocks are duplicated while they're
+		// being written.
+		data := make([]byte, 200000)
+		rand.New(rand.NewSource(4)).Read(data)
+		data = append(data, data...)
+
+		err = fs.WriteFile("bar", bytes.NewReader(data))
+		require.NoError(t, err)
+
+		r, err := fs.ReaderFor("bar")
+		require.NoError(t, err)
+
+		out, err := ioutil.ReadAll(r)
+		require.NoError(t, err)
+
+		assert.Equal(t, data, out)
+
+		seqPath := filepath.Join(root, "sequential")
+		os.RemoveAll(seqPath)
+		defer os.RemoveAll(seqPath)
+
+		seq, err := NewFS(seqPath, WithEncryption(key), WithLZ4())
+		require.NoError(t, err)
+
+		err = seq.WriteFile("bar", bytes.NewReader(data))
+		require.NoError(t, err)
+
+		set := entryOf(t, fs, "bar").Blocks
+		seqSet := entryOf(t, seq, "bar").Blocks
+
+		assert.Equal(t, seqSet.Blocks, set.Blocks)
+		assert.Equal(t, seqSet.Sum, set.Sum)
+
+		for _, blk := range set.Blocks {
+			info, ok := fs.tocBlocks.FindBlock(blk.Id)
+			require.True(t, ok)
+
+			seqInfo, ok := seq.tocBlocks.FindBlock(blk.Id)
+			require.True(t, ok)
+
+			assert.Equal(t, seqInfo.References, info.References)
+			assert.True(t, info.CompSize > 0)
+		}
+
+		blocks, before := tocRefs(fs)
+
+		// A file that fails part way through leaves no references to
+		// its blocks behind.
+		fresh := make([]byte, 200000)
+		rand.New(rand.NewSource(5)).Read(fresh)
+
+		err = fs.WriteFile("broken", io.MultiReader(
+			bytes.NewReader(data[:100000]),
+			bytes.NewReader(fresh),
+			iotest.TimeoutReader(bytes.NewReader(fresh))))
+		require.Error(t, err)
+
+		afterBlocks, after := tocRefs(fs)
+
+		assert.Equal(t, blocks, afterBlocks)
+		assert.Equal(t, before, after)
+	})
+
+	n.It("stops operations when their context is done", func(t *testing.T) {
+		fs, err := NewFS(path, WithWriteWorkers(2))
+		require.NoError(t, err)
+
+		data := make([]byte, 200000)
+		rand.New(rand.NewSource(6)).Read(data)
+
+		err = fs.WriteFile("bar", bytes.NewReader(data))
+		require.NoError(t, err)
+
+		blocks, before := tocRefs(fs)
+
+		fresh := make([]byte, 200000)
+		rand.New(rand.NewSource(7)).Read(fresh)
+
+		ctx, cancel := context.WithCancel(context.Background())
+
+		// Cancel once part of the file has been written.
+		r := io.MultiReader(
+			bytes.NewReader(data[:50000]),
+			bytes.NewReader(fresh[:100000]),
+			readerFunc(func(buf []byte) (int, error) {
+				cancel()
+				return 0, io.EOF
+			}),
+			bytes.NewReader(fresh[100000:]),
+		)
+
+		err = fs.WriteFileContext(ctx, "baz", r)
+		assert.Equal(t, context.Canceled, err)
+
+		_, err = fs.ReaderFor("baz")
+		assert.True(t, os.IsNotExist(err))
+
+		afterBlocks, after := tocRefs(fs)
+		assert.Equal(t, blocks, afterBlocks)
+		assert.Equal(t, before, after)
+
+		// A transaction whose commit is cancelled is rolled back.
+		txn := fs.Txn(true)
+
+		err = txn.WriteFile("baz", bytes.NewReader(fresh))
+		require.NoError(t, err)
+
+		err = txn.CommitContext(ctx)
+		assert.Equal(t, context.Canceled, err)
+
+		afterBlocks, after = tocRefs(fs)
+		assert.Equal(t, blocks, afterBlocks)
+		assert.Equal(t, before, after)
+
+		err = fs.WriteFile("qux", bytes.NewReader([]byte("hello")))
+		require.NoError(t, err)
+
+		fs2, err := NewFS(path)
+		require.NoError(t, err)
+
+		_, err = fs2.ReaderFor("baz")
+		assert.True(t, os.IsNotExist(err))
+
+		ctx, cancel = context.WithCancel(context.Background())
+
+		rd, err := fs2.ReaderForContext(ctx, "bar")
+		require.NoError(t, err)
+
+		buf := make([]byte, 10)
+
+		_, err = io.ReadFull(rd, buf)
+		require.NoError(t, err)
+
+		cancel()
+
+		_, err = ioutil.ReadAll(rd)
+		assert.Equal(t, context.Canceled, err)
+	})
+
+	n.It("collects the blocks of cancelled writes after a restart", func(t *testing.T) {
+		fs, err := NewFS(path, WithPackFiles(0))
+		require.NoError(t, err)
+
+		fresh := make([]byte, 200000)
+		rand.New(rand.NewSource(10)).Read(fresh)
+
+		ctx, cancel := context.WithCancel(context.Background())
+
+		r := io.MultiReader(
+			bytes.NewReader(fresh[:100000]),
+			readerFunc(func(buf []byte) (int, error) {
+				cancel()
+				return 0, io.EOF
+			}),
+			bytes.NewReader(fresh[100000:]),
+		)
+
+		err = fs.WriteFileContext(ctx, "baz", r)
+		assert.Equal(t, context.Canceled, err)
+
+		// The blocks written before the cancel are in blocks.idx, for
+		// the next commit's GC to remove.
+		fs2, err := NewFS(path)
+		require.NoError(t, err)
+
+		var orphans []BlockId
+
+		for _, info := range fs2.blocks.Blocks {
+			if _, ok := fs2.tocBlocks.FindBlock(info.Id); !ok {
+				orphans = append(orphans, info.Id)
+			}
+		}
+
+		require.NotEmpty(t, orphans)
+
+		// Cancel during GC, once the head is written.
+		ctx, cancel = context.WithCancel(context.Background())
+
+		fs3, err := NewFS(path, WithProgress(func(p Progress) {
+			if p.Op == ProgressGC {
+				cancel()
+			}
+		}))
+		require.NoError(t, err)
+
+		txn := fs3.Txn(true)
+		require.NoError(t, txn.WriteFile("qux", strings.NewReader("hello")))
+
+		err = txn.CommitContext(ctx)
+		assert.Equal(t, context.Canceled, err)
+
+		entryOf(t, fs3, "qux")
+
+		require.NoError(t, fs3.WriteFile("quux", strings.NewReader("goodbye")))
+
+		for _, id := range orphans {
+			assert.False(t, hasBlock(t, fs3, id))
+
+			_, err := fs3.blockAccess.readBlock(id)
+			assert.Error(t, err)
+		}
+	})
+
+	n.It("reports progress as blocks are written, read and collected", func(t *testing.T) {
+		var events []Progress
+
+		last := func(op ProgressOp, path string) Progress {
+			var found *Progress
+
+			for i := range events {
+				if events[i].Op == op && events[i].Path == path {
+					found = &events[i]
+				}
+			}
+
+			require.NotNil(t, found, path)
+
+			return *found
+		}
+
+		fs, err := NewFS(path, WithProgress(func(p Progress) {
+			events = append(events, p)
+		}))
+		require.NoError(t, err)
+
+		data := make([]byte, 100000)
+		rand.New(rand.NewSource(8)).Read(data)
+
+		err = fs.WriteFile("foo", bytes.NewReader(data))
+		require.NoError(t, err)
+
+		blocks := len(entryOf(t, fs, "foo").Blocks.Blocks)
+
+		p := last(ProgressWrite, "foo")
+		assert.Equal(t, int64(len(data)), p.Bytes)
+		assert.Equal(t, blocks, p.Blocks)
+		assert.Equal(t, blocks, p.Written)
+		assert.Equal(t, 0, p.Deduped)
+
+		err = fs.WriteFile("bar", bytes.NewReader(data))
+		require.NoError(t, err)
+
+		p = last(ProgressWrite, "bar")
+		assert.Equal(t, int64(len(data)), p.Bytes)
+		assert.Equal(t, 0, p.Written)
+		assert.Equal(t, blocks, p.Deduped)
+
+		r, err := fs.ReaderFor("bar")
+		require.NoError(t, err)
+
+		_, err = io.Copy(ioutil.Discard, r)
+		require.NoError(t, err)
+
+		p = last(ProgressRead, "bar")
+		assert.Equal(t, int64(len(data)), p.Bytes)
+		assert.Equal(t, blocks, p.Blocks)
+		assert.Equal(t, blocks, p.TotalBlocks)
+
+		// Blocks that fail to be stored aren't reported as written.
+		fs.blockAccess.store = &failingStore{blockStore: fs.blockAccess.blockStore()}
+
+		other := make([]byte, 100000)
+		rand.New(rand.NewSource(9)).Read(other)
+
+		err = fs.WriteFileContext(context.Background(), "baz", bytes.NewReader(other))
+		require.Error(t, err)
+
+		fs.blockAccess.store = nil
+
+		for _, ev := range events {
+			if ev.Path == "baz" {
+				assert.Equal(t, 0, ev.Written)
+			}
+		}
+
+		require.NoError(t, fs.RemoveFile("foo"))
+
+		// Blocks that are already gone aren't counted as removed.
+		_, missing := fileStore{root: fs.blockAccess.root}.path(entryOf(t, fs, "bar").Blocks.Blocks[0].Id)
+		require.NoError(t, os.Remove(missing))
+
+		events = nil
+
+		require.NoError(t, fs.RemoveFile("bar"))
+
+		p = last(ProgressGC, "")
+		assert.Equal(t, p.TotalBlocks, p.Blocks)
+		assert.Equal(t, p.TotalBlocks-len(fs.blocks.Blocks)-1, p.Removed)
+		assert.True(t, p.Removed >= blocks-1)
+		assert.True(t, p.Bytes > int64(len(data))/2)
+	})
+
+	n.It("reports repository stats", func(t *testing.T) {
+		fs, err := NewFS(path, WithLZ4())
+		require.NoError(t, err)
+
+		st, err := fs.Stats()
+		require.NoError(t, err)
+
+		assert.Equal(t, 0, st.Blocks)
+		assert.Equal(t, 0.0, st.DedupRatio)
+
+		data := make([]byte, 100000)
+		rand.New(rand.NewSource(9)).Read(data)
+
+		require.NoError(t, fs.WriteFile("foo", bytes.NewReader(data)))
+		require.NoError(t, fs.WriteFile("bar", bytes.NewReader(data)))
+		require.NoError(t, fs.CreateSnapshot("snap1"))
+		require.NoError(t, fs.WriteFile("zeros", bytes.NewReader(make([]byte, 50000))))
+
+		st, err = fs.Stats()
+		require.NoError(t, err)
+
+		assert.Equal(t, 1, st.Snapshots)
+		assert.Equal(t, HeadStats{Files: 3, Bytes: 250000}, st.Heads["primary"])
+		assert.Equal(t, HeadStats{Files: 2, Bytes: 200000}, st.Heads["snap1"])
+		assert.Equal(t, int64(450000), st.LogicalBytes)
+
+		var (
+			histogram int
+			unique    int64
+		)
+
+		ids := map[string]bool{}
+
+		for _, file := range []string{"foo", "zeros"} {
+			for _, blk := range entryOf(t, fs, file).Blocks.Blocks {
+				if info, ok := fs.tocBlocks.FindBlock(blk.Id); ok && !ids[string(blk.Id)] {
+					unique += info.ByteSize
+				}
+
+				ids[string(blk.Id)] = true
+			}
+		}
+
+		for _, n := range st.BlockSizes {
+			histogram += n
+		}
+
+		// The blocks holding the TOCs are counted apart.
+		assert.Equal(t, len(ids), st.Blocks)
+		assert.Equal(t, st.Blocks, histogram)
+		assert.True(t, st.TOCBlocks > 0)
+		assert.True(t, st.TOCBytes > 0)
+
+		assert.Equal(t, unique, st.UniqueBytes)
+		assert.True(t, st.StoredBytes < st.UniqueBytes)
+
+		assert.True(t, st.DedupRatio > 2.5)
+		assert.True(t, st.CompressionRatio > 1)
+	})
+
+	n.It("upgrades repositories written before format versions", func(t *testing.T) {
+		bar := make([]byte, 20000)
+		rand.New(rand.NewSource(1)).Read(bar)
+
+		fixtures := map[string][]Option{
+			"v0-plain":     nil,
+			"v0-encrypted": {WithEncryption(fixtureKey(t))},
+		}
+
+		for name, opts := range fixtures {
+			os.RemoveAll(path)
+			require.NoError(t, copyTree(filepath.Join("testdata", name), path))
+
+			check := func(fs *FS) {
+				expected := map[string][]byte{
+					"bar": bar,
+					"baz": []byte("goodbye"),
+				}
+
+				for file, contents := range expected {
+					r, err := fs.ReaderFor(file)
+					require.NoError(t, err, name)
+
+					data, err := ioutil.ReadAll(r)
+					require.NoError(t, err, name)
+
+					assert.Equal(t, contents, data, name)
+				}
+
+				snap, err := fs.ReadSnapshot("snap1", opts...)
+				require.NoError(t, err, name)
+
+				r, err := snap.ReaderFor("foo")
+				require.NoError(t, err, name)
+
+				data, err := ioutil.ReadAll(r)
+				require.NoError(t, err, name)
+
+				assert.Equal(t, "hello", string(data), name)
+			}
+
+			fs, err := NewFS(path, opts...)
+			require.NoError(t, err, name)
+
+			assert.Equal(t, uint32(0), fs.config.Version, name)
+
+			check(fs)
+
+			require.NoError(t, fs.Upgrade(), name)
+
+			fs2, err := NewFS(path, opts...)
+			require.NoError(t, err, name)
+
+			assert.Equal(t, uint32(FormatVersion), fs2.config.Version, name)
+			assert.True(t, fs2.config.Authenticated, name)
+
+			check(fs2)
+
+			for _, head := range []string{DefaultHead, "snap1"} {
+				data, err := ioutil.ReadFile(filepath.Join(path, "heads", head))
+				require.NoError(t, err, name)
+
+				header, _ := splitHead(t, fs2, data)
+				assert.Equal(t, uint32(FormatVersion), header.Version, name)
+			}
+
+			require.NoError(t, fs2.Upgrade(), name)
+
+			err = fs2.WriteFile("qux", strings.NewReader("more"))
+			require.NoError(t, err, name)
+
+			fs3, err := NewFS(path, opts...)
+			require.NoError(t, err, name)
+
+			check(fs3)
+		}
+	})
+
+	n.It("finishes an interrupted upgrade from version 0", func(t *testing.T) {
+		bar := make([]byte, 20000)
+		rand.New(rand.NewSource(1)).Read(bar)
+
+		fixtures := map[string][]Option{
+			"v0-plain":     nil,
+			"v0-encrypted": {WithEncryption(fixtureKey(t))},
+		}
+
+		for name, opts := range fixtures {
+			os.RemoveAll(path)
+			require.NoError(t, copyTree(filepath.Join("testdata", name), path))
+
+			check := func(fs *FS, expected map[string][]byte) {
+				for file, contents := range expected {
+					r, err := fs.ReaderFor(file)
+					require.NoError(t, err, name)
+
+					data, err := ioutil.ReadAll(r)
+					require.NoError(t, err, name)
+
+					assert.Equal(t, contents, data, name)
+				}
+
+				snap, err := fs.ReadSnapshot("snap1", opts...)
+				require.NoError(t, err, name)
+
+				r, err := snap.ReaderFor("foo")
+				require.NoError(t, err, name)
+
+				data, err := ioutil.ReadAll(r)
+				require.NoError(t, err, name)
+
+				assert.Equal(t, "hello", string(data), name)
+			}
+
+			fs, err := NewFS(path, opts...)
+			require.NoError(t, err, name)
+
+			// Stop after a few blocks have been rewritten.
+			fs.blockAccess.store = &failingStore{blockStore: fs.blockAccess.blockStore(), puts: 2}
+
+			require.Error(t, fs.Upgrade(), name)
+
+			fs2, err := NewFS(path, opts...)
+			require.NoError(t, err, name)
+
+			assert.True(t, fs2.config.Upgrading, name)
+
+			expected := map[string][]byte{
+				"bar": bar,
+				"baz": []byte("goodbye"),
+			}
+
+			check(fs2, expected)
+
+			err = fs2.WriteFile("qux", strings.NewReader("more"))
+			require.NoError(t, err, name)
+
+			expected["qux"] = []byte("more")
+
+			fs3, err := NewFS(path, opts...)
+			require.NoError(t, err, name)
+
+			check(fs3, expected)
+
+			require.NoError(t, fs3.Upgrade(), name)
+
+			fs4, err := NewFS(path, opts...)
+			require.NoError(t, err, name)
+
+			assert.Equal(t, uint32(FormatVersion), fs4.config.Version, name)
+			assert.False(t, fs4.config.Upgrading, name)
+			assert.Nil(t, fs4.blockAccess.legacy, name)
+
+			check(fs4, expected)
+		}
+	})
+
+	n.It("rejects repositories and heads from newer format versions", func(t *testing.T) {
+		fs, err := NewFS(path)
+		require.NoError(t, err)
+
+		assert.Equal(t, uint32(FormatVersion), fs.config.Version)
+
+		err = fs.WriteFile("foo", strings.NewReader("hello"))
+		require.NoError(t, err)
+
+		headPath := filepath.Join(path, "heads", DefaultHead)
+
+		orig, err := ioutil.ReadFile(headPath)
+		require.NoError(t, err)
+
+		header, sections := splitHead(t, fs, orig)
+
+		header.Version = 99
+
+		hdata, err := marshalHeadHeader(header)
+		require.NoError(t, err)
+
+		require.NoError(t, ioutil.WriteFile(headPath, append(hdata, sections...), 0644))
+
+		_, err = NewFS(path)
+		assert.Equal(t, ErrUnsupportedHeadVersion, err)
+
+		require.NoError(t, ioutil.WriteFile(headPath, orig, 0644))
+
+		fs.config.Version = 99
+		require.NoError(t, fs.writeConfig())
+
+		_, err = NewFS(path)
+		assert.Equal(t, ErrUnsupportedVersion, err)
+	})
+
+	n.Meow()
+}
+
+// entryOf returns the entry for path in the TOC of fs.
+func entryOf(t *testing.T, fs *FS, path string) *format.Entry {
+	entry, ok, err := fs.toc.Get(path)
+	require.NoError(t, err)
+	require.True(t, ok, path)
+
+	return entry
+}
+
+// tocRefs returns how many blocks the head of fs has, and how many
+// references to them.
+func tocRefs(fs *FS) (blocks int, refs int64) {
+	for _, info := range fs.tocBlocks.Blocks {
+		refs += info.References
+	}
+
+	return len(fs.tocBlocks.Blocks), refs
+}
+
+// hasBlock reports whether fs stores the block with id.
+func hasBlock(t *testing.T, fs *FS, id BlockId) bool {
+	ok, err := fs.HasBlock(id)
+	require.NoError(t, err)
+
+	return ok
+}
+
+// chmodTree makes dir and everything in it read only, or writable again.
+func chmodTree(t *testing.T, dir string, writable bool) {
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		mode := os.FileMode(0444)
+		if info.IsDir() {
+			mode = 0555
+		}
+
+		if writable {
+			mode |= 0200
+		}
+
+		return os.Chmod(path, mode)
+	})
+	require.NoError(t, err)
+}
+
+// loadedLeaves returns how many children of the root of fs's TOC are
+// loaded.
+func loadedLeaves(fs *FS) int {
+	n := 0
+	for _, c := range fs.toc.root.children {
+		if c.loaded {
+			n++
+		}
+	}
+
+	return n
+}
+
+// fixtureKey returns the key testdata/v0-encrypted was written with.
+func fixtureKey(t *testing.T) *Key {
+	keyHex, err := ioutil.ReadFile(filepath.Join("testdata", "v0-encrypted.key"))
+	require.NoError(t, err)
+
+	priv, err := hex.DecodeString(strings.TrimSpace(string(keyHex)))
+	require.NoError(t, err)
+
+	var key Key
+	copy(key.priv[:], priv)
+	curve25519.ScalarBaseMult(&key.pub, &key.priv)
+
+	return &key
+}
+
+// failingStore fails every Put after the first puts.
+type failingStore struct {
+	blockStore
+	puts int
+}
+
+func (s *failingStore) Put(bid BlockId, data []byte) error {
+	if s.puts == 0 {
+		return errors.New("store failed")
+	}
+
+	s.puts--
+
+	return s.blockStore.Put(bid, data)
+}
+
+// readerFunc is an io.Reader that calls itself.
+type readerFunc func(buf []byte) (int, error)
+
+func (f readerFunc) Read(buf []byte) (int, error) {
+	return f(buf)
+}
+
+// splitHead returns the header of a head and the sections that follow
+// it.
+func splitHead(t *testing.T, fs *FS, data []byte) (*format.TOCHeader, []byte) {
+	header, toc, blocks, err := fs.parseHead(data)
+	require.NoError(t, err)
+
+	return header, append(append([]byte(nil), toc...), blocks...)
+}
+
+func copyTree(from, to string) error {
+	return filepath.Walk(from, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(from, path)
+		if err != nil {
+			return err
+		}
+
+		dest := filepath.Join(to, rel)
+
+		if info.IsDir() {
+			return os.MkdirAll(dest, 0755)
+		}
+
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			return err
+		}
+
+		return ioutil.WriteFile(dest, data, 0644)
+	})
 }
@@ -2,6 +2,7 @@ package yfs
 
 import (
 	"bytes"
+	"context"
 	"io"
 
 	"github.com/evanphx/yfs/format"
@@ -9,17 +10,86 @@ import (
 
 type blockReader struct {
 	t      *Txn
+	ctx    context.Context
 	blocks []*format.Block
 	cur    *bytes.Reader
 	clz    io.Reader
+
+	// pending are the blocks being read ahead, in order.
+	pending []chan fetchedBlock
+
+	progress Progress
 }
 
-func (b *blockReader) Read(buf []byte) (int, error) {
-	if b.cur == nil {
+type fetchedBlock struct {
+	data []byte
+	err  error
+}
+
+// remaining is how many blocks haven't been returned by next.
+func (b *blockReader) remaining() int {
+	return len(b.blocks) + len(b.pending)
+}
+
+// next returns the contents of the next block, reporting progress.
+func (b *blockReader) next() ([]byte, error) {
+	data, err := b.fetch()
+	if err != nil {
+		return data, err
+	}
+
+	b.progress.Bytes += int64(len(data))
+	b.progress.Blocks++
+
+	b.t.f.report(b.progress)
+
+	return data, nil
+}
+
+// fetch returns the contents of the next block. With read ahead, the
+// blocks after it are read and decoded in the background while it's
+// used.
+func (b *blockReader) fetch() ([]byte, error) {
+	if err := b.ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	ahead := b.t.f.readAhead
+
+	if ahead <= 0 && len(b.pending) == 0 {
 		block := b.blocks[0]
 		b.blocks = b.blocks[1:]
 
-		data, err := b.t.blockAccess.readBlock(block.Id)
+		return b.t.blockAccess.readBlock(block.Id)
+	}
+
+	for len(b.pending) <= ahead && len(b.blocks) > 0 {
+		block := b.blocks[0]
+		b.blocks = b.blocks[1:]
+
+		ch := make(chan fetchedBlock, 1)
+
+		go func(id BlockId) {
+			data, err := b.t.blockAccess.readBlock(id)
+			ch <- fetchedBlock{data, err}
+		}(block.Id)
+
+		b.pending = append(b.pending, ch)
+	}
+
+	res := <-b.pending[0]
+	b.pending = b.pending[1:]
+
+	return res.data, res.err
+}
+
+func (b *blockReader) Read(buf []byte) (int, error) {
+	if b.cur == nil {
+		if b.remaining() == 0 {
+			return 0, io.EOF
+		}
+
+		data, err := b.next()
 		if err != nil {
 			return 0, err
 		}
@@ -39,14 +109,11 @@ func (b *blockReader) Read(buf []byte) (int, error) {
 		buf = buf[n:]
 	}
 
-	if len(b.blocks) == 0 {
+	if b.remaining() == 0 {
 		return n, io.EOF
 	}
 
-	block := b.blocks[0]
-	b.blocks = b.blocks[1:]
-
-	data, err := b.t.blockAccess.readBlock(block.Id)
+	data, err := b.next()
 	if err != nil {
 		return 0, err
 	}
@@ -69,8 +136,8 @@ func (b *blockReader) WriteTo(w io.Writer) (int64, error) {
 		total += int64(n)
 	}
 
-	for _, blk := range b.blocks {
-		data, err := b.t.blockAccess.readBlock(blk.Id)
+	for b.remaining() > 0 {
+		data, err := b.next()
 		if err != nil {
 			return total, err
 		}
@@ -83,8 +150,6 @@ func (b *blockReader) WriteTo(w io.Writer) (int64, error) {
 		total += int64(n)
 	}
 
-	b.blocks = nil
-
 	return total, nil
 }
 
@@ -101,7 +166,7 @@ type blockWriter struct {
 }
 
 func (b *blockWriter) consume() {
-	_, err := b.t.writeFile(b.path, b.pr, b.entry)
+	_, err := b.t.writeFile(context.Background(), b.path, b.pr, b.entry)
 	if err != nil {
 		b.werr <- err
 		return
@@ -134,7 +199,7 @@ func (b *blockWriter) ReadFrom(r io.Reader) (int64, error) {
 		return io.Copy(b.pw, r)
 	}
 
-	n, err := b.t.writeFile(b.path, r, b.entry)
+	n, err := b.t.writeFile(context.Background(), b.path, r, b.entry)
 	if err != nil {
 		return n, err
 	}
@@ -0,0 +1,200 @@
+package yfs
+
+import (
+	"bytes"
+	"crypto/rand"
+	"errors"
+	"io"
+	"io/ioutil"
+	"os"
+
+	"github.com/evanphx/yfs/format"
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/chacha20poly1305"
+	"golang.org/x/crypto/curve25519"
+)
+
+// Argon2id parameters used for new key files. They're stored in each key
+// file so they can be raised later without breaking existing files.
+const (
+	KeyFileTime    = 3
+	KeyFileMemory  = 64 << 10
+	KeyFileThreads = 4
+)
+
+const keyFileVersion = 1
+
+// Bounds on the Argon2id parameters read from key files, which would
+// otherwise let a tampered file crash the process or exhaust its memory.
+// Memory is in KiB, like KeyFileMemory.
+const (
+	maxKeyFileTime    = 64
+	maxKeyFileMemory  = 1 << 20
+	maxKeyFileThreads = 255
+)
+
+var (
+	ErrWrongPassphrase   = errors.New("wrong passphrase or corrupt key file")
+	ErrUnknownKeyFile    = errors.New("unsupported key file version or parameters")
+	ErrKeyFileIdMismatch = errors.New("key file id doesn't match the key it contains")
+)
+
+// Id returns the public identifier of the key, the value stored in
+// TOCHeader.KeyId of repositories encrypted with it.
+func (k *Key) Id() []byte {
+	id := make([]byte, len(k.pub))
+	copy(id, k.pub[:])
+	return id
+}
+
+func keyFileCipherKey(passphrase, salt []byte, kf *format.KeyFile) []byte {
+	return argon2.IDKey(passphrase, salt, kf.ArgonTime, kf.ArgonMemory, uint8(kf.ArgonThreads), chacha20poly1305.KeySize)
+}
+
+// validKeyFile reports if kf's parameters are safe to derive its key
+// and open it with.
+func validKeyFile(kf *format.KeyFile) bool {
+	switch {
+	case kf.Version != keyFileVersion:
+		return false
+	case kf.ArgonTime < 1 || kf.ArgonTime > maxKeyFileTime:
+		return false
+	case kf.ArgonThreads < 1 || kf.ArgonThreads > maxKeyFileThreads:
+		return false
+	case kf.ArgonMemory < 8*kf.ArgonThreads || kf.ArgonMemory > maxKeyFileMemory:
+		return false
+	case len(kf.Nonce) != chacha20poly1305.NonceSize:
+		return false
+	}
+
+	return true
+}
+
+// WriteKeyFile writes k to w, with the private key encrypted under a key
+// derived from passphrase with Argon2id. The public key id is stored in
+// the clear so tooling can tell which repositories a key file opens
+// without the passphrase.
+func (k *Key) WriteKeyFile(w io.Writer, passphrase []byte) error {
+	kf := &format.KeyFile{
+		Version:      keyFileVersion,
+		KeyId:        k.Id(),
+		Salt:         make([]byte, 16),
+		ArgonTime:    KeyFileTime,
+		ArgonMemory:  KeyFileMemory,
+		ArgonThreads: KeyFileThreads,
+		Nonce:        make([]byte, chacha20poly1305.NonceSize),
+	}
+
+	if _, err := io.ReadFull(rand.Reader, kf.Salt); err != nil {
+		return err
+	}
+
+	if _, err := io.ReadFull(rand.Reader, kf.Nonce); err != nil {
+		return err
+	}
+
+	cipher, err := chacha20poly1305.New(keyFileCipherKey(passphrase, kf.Salt, kf))
+	if err != nil {
+		return err
+	}
+
+	// Binding the id means it can't be swapped for another key's.
+	kf.SealedKey = cipher.Seal(nil, kf.Nonce, k.priv[:], kf.KeyId)
+
+	data, err := kf.Marshal()
+	if err != nil {
+		return err
+	}
+
+	_, err = w.Write(data)
+	return err
+}
+
+// ReadKeyFile reads a key file written by WriteKeyFile and decrypts the
+// key inside with passphrase.
+func ReadKeyFile(r io.Reader, passphrase []byte) (*Key, error) {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var kf format.KeyFile
+
+	err = kf.Unmarshal(data)
+	if err != nil {
+		return nil, err
+	}
+
+	if !validKeyFile(&kf) {
+		return nil, ErrUnknownKeyFile
+	}
+
+	cipher, err := chacha20poly1305.New(keyFileCipherKey(passphrase, kf.Salt, &kf))
+	if err != nil {
+		return nil, err
+	}
+
+	priv, err := cipher.Open(nil, kf.Nonce, kf.SealedKey, kf.KeyId)
+	if err != nil || len(priv) != 32 {
+		return nil, ErrWrongPassphrase
+	}
+
+	var key Key
+
+	copy(key.priv[:], priv)
+	curve25519.ScalarBaseMult(&key.pub, &key.priv)
+
+	if !bytes.Equal(key.pub[:], kf.KeyId) {
+		return nil, ErrKeyFileIdMismatch
+	}
+
+	return &key, nil
+}
+
+// KeyFileId returns the public key id recorded in a key file without
+// decrypting it.
+func KeyFileId(r io.Reader) ([]byte, error) {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var kf format.KeyFile
+
+	err = kf.Unmarshal(data)
+	if err != nil {
+		return nil, err
+	}
+
+	return kf.KeyId, nil
+}
+
+// SaveKeyFile writes k to a new key file at path, readable only by the
+// current user.
+func SaveKeyFile(path string, k *Key, passphrase []byte) error {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		return err
+	}
+
+	err = k.WriteKeyFile(f, passphrase)
+	if err != nil {
+		f.Close()
+		os.Remove(path)
+		return err
+	}
+
+	return f.Close()
+}
+
+// LoadKeyFile reads and decrypts the key file at path.
+func LoadKeyFile(path string, passphrase []byte) (*Key, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	defer f.Close()
+
+	return ReadKeyFile(f, passphrase)
+}
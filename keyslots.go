@@ -0,0 +1,332 @@
+package yfs
+
+import (
+	"bytes"
+	"crypto/rand"
+	"errors"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/evanphx/yfs/format"
+	"github.com/golang/crypto/blake2b"
+	"golang.org/x/crypto/chacha20poly1305"
+	"golang.org/x/crypto/curve25519"
+)
+
+// Blocks are encrypted to a repository master key. Each key slot holds
+// the master private key wrapped for one user key, so any key with a
+// slot can open the repository, and slots can be added and removed
+// without touching any blocks.
+//
+// Repositories encrypted before key slots existed use the key given to
+// WithEncryption as their master key. Slots can be added to them too,
+// but that key can't be revoked without rotating the master key.
+
+var (
+	ErrNoMasterKey     = errors.New("repository is not opened with an encryption key")
+	ErrLastKeySlot     = errors.New("can't remove the last key slot")
+	ErrKeySlotNotFound = errors.New("no key slot for that key")
+	ErrKeySlotExists   = errors.New("key already has a slot")
+	ErrInvalidKeyId    = errors.New("key id must be a 32 byte public key")
+)
+
+// KeySlotInfo describes a key that can open the repository.
+type KeySlotInfo struct {
+	Name string
+	Id   []byte
+}
+
+func (f *FS) keySlotsPath() string {
+	return filepath.Join(f.root, "keys")
+}
+
+func (f *FS) readKeySlots() (*format.KeySlots, error) {
+	data, err := ioutil.ReadFile(f.keySlotsPath())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	var ks format.KeySlots
+
+	err = ks.Unmarshal(data)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ks, nil
+}
+
+func (f *FS) writeKeySlots(ks *format.KeySlots) error {
+	data, err := ks.Marshal()
+	if err != nil {
+		return err
+	}
+
+	tmp := f.keySlotsPath() + ".tmp"
+
+	err = ioutil.WriteFile(tmp, data, 0644)
+	if err != nil {
+		return err
+	}
+
+	return os.Rename(tmp, f.keySlotsPath())
+}
+
+func slotCipherKey(shared, ephemeral, recipient []byte) []byte {
+	var buf bytes.Buffer
+	buf.Write(shared)
+	buf.Write(ephemeral)
+	buf.Write(recipient)
+
+	sum := blake2b.Sum256(buf.Bytes())
+	return sum[:]
+}
+
+func slotData(masterId, keyId []byte) []byte {
+	return append(append([]byte(nil), masterId...), keyId...)
+}
+
+// wrapKey seals master for the public key id.
+func wrapKey(master *Key, name string, id []byte) (*format.KeySlot, error) {
+	if len(id) != 32 {
+		return nil, ErrInvalidKeyId
+	}
+
+	temp := GenerateKey()
+
+	var shared, pub [32]byte
+	copy(pub[:], id)
+	curve25519.ScalarMult(&shared, &temp.priv, &pub)
+
+	cipher, err := chacha20poly1305.New(slotCipherKey(shared[:], temp.pub[:], id))
+	if err != nil {
+		return nil, err
+	}
+
+	slot := &format.KeySlot{
+		KeyId:     append([]byte(nil), id...),
+		Name:      name,
+		Ephemeral: temp.Id(),
+		Nonce:     make([]byte, chacha20poly1305.NonceSize),
+	}
+
+	if _, err := io.ReadFull(rand.Reader, slot.Nonce); err != nil {
+		return nil, err
+	}
+
+	slot.WrappedKey = cipher.Seal(nil, slot.Nonce, master.priv[:], slotData(master.pub[:], id))
+
+	return slot, nil
+}
+
+// unwrapKey opens slot with key, returning the master key.
+func unwrapKey(slot *format.KeySlot, masterId []byte, key *Key) (*Key, error) {
+	var shared, eph [32]byte
+	copy(eph[:], slot.Ephemeral)
+	curve25519.ScalarMult(&shared, &key.priv, &eph)
+
+	cipher, err := chacha20poly1305.New(slotCipherKey(shared[:], slot.Ephemeral, key.pub[:]))
+	if err != nil {
+		return nil, err
+	}
+
+	priv, err := cipher.Open(nil, slot.Nonce, slot.WrappedKey, slotData(masterId, key.pub[:]))
+	if err != nil || len(priv) != 32 {
+		return nil, ErrWrongEncryptionKey
+	}
+
+	var master Key
+
+	copy(master.priv[:], priv)
+	curve25519.ScalarBaseMult(&master.pub, &master.priv)
+
+	if !bytes.Equal(master.pub[:], masterId) {
+		return nil, ErrWrongEncryptionKey
+	}
+
+	return &master, nil
+}
+
+// masterKeyFor finds the master key that key opens, creating a master
+// key and a slot for key if this is a new repository. While the master
+// key is being rotated it returns the new master key, which new data is
+// written with, and the old one, which is still needed to read.
+func (f *FS) masterKeyFor(key *Key) (*Key, *Key, error) {
+	ks, err := f.readKeySlots()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if ks == nil {
+		existing, err := f.hasData()
+		if err != nil {
+			return nil, nil, err
+		}
+
+		// Read only FSs of new repositories have nothing to decrypt.
+		if existing || f.readOnly {
+			return key, nil, nil
+		}
+
+		master := GenerateKey()
+
+		slot, err := wrapKey(master, "", key.Id())
+		if err != nil {
+			return nil, nil, err
+		}
+
+		err = f.writeKeySlots(&format.KeySlots{
+			MasterId: master.Id(),
+			Slots:    []*format.KeySlot{slot},
+		})
+		if err != nil {
+			return nil, nil, err
+		}
+
+		return master, nil, nil
+	}
+
+	master, err := openKeySlots(ks.MasterId, ks.Slots, key)
+	if err != nil {
+		if ks.NextMaster != nil && err == ErrWrongEncryptionKey {
+			// key only opens the new master key, which can't read
+			// the blocks that haven't been rotated yet.
+			if _, err := openKeySlots(ks.NextMasterId, ks.NextSlots, key); err == nil {
+				return nil, nil, ErrRotationInProgress
+			}
+		}
+
+		return nil, nil, err
+	}
+
+	if ks.NextMaster == nil {
+		return master, nil, nil
+	}
+
+	next, err := unwrapKey(ks.NextMaster, ks.NextMasterId, master)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return next, master, nil
+}
+
+// openKeySlots returns the master key with id masterId if key is that
+// master key or has a slot in slots.
+func openKeySlots(masterId []byte, slots []*format.KeySlot, key *Key) (*Key, error) {
+	if bytes.Equal(masterId, key.pub[:]) {
+		return key, nil
+	}
+
+	for _, slot := range slots {
+		if bytes.Equal(slot.KeyId, key.pub[:]) {
+			return unwrapKey(slot, masterId, key)
+		}
+	}
+
+	return nil, ErrWrongEncryptionKey
+}
+
+// KeySlots lists the keys that have a slot in the repository.
+func (f *FS) KeySlots() ([]KeySlotInfo, error) {
+	ks, err := f.readKeySlots()
+	if err != nil || ks == nil {
+		return nil, err
+	}
+
+	var out []KeySlotInfo
+
+	for _, slot := range ks.Slots {
+		out = append(out, KeySlotInfo{Name: slot.Name, Id: slot.KeyId})
+	}
+
+	return out, nil
+}
+
+// AddKeySlot lets the key with public id open the repository. Only the
+// public part of the key is needed, as returned by Key.Id or KeyFileId,
+// so a slot can be added for a key held elsewhere.
+func (f *FS) AddKeySlot(name string, id []byte) error {
+	if f.readOnly {
+		return ErrReadOnly
+	}
+
+	if f.masterKey == nil {
+		return ErrNoMasterKey
+	}
+
+	f.txnlock.Lock()
+	defer f.txnlock.Unlock()
+
+	ks, err := f.readKeySlots()
+	if err != nil {
+		return err
+	}
+
+	if ks == nil {
+		ks = &format.KeySlots{MasterId: f.masterKey.Id()}
+	}
+
+	if ks.NextMaster != nil {
+		return ErrRotationInProgress
+	}
+
+	for _, slot := range ks.Slots {
+		if bytes.Equal(slot.KeyId, id) {
+			return ErrKeySlotExists
+		}
+	}
+
+	slot, err := wrapKey(f.masterKey, name, id)
+	if err != nil {
+		return err
+	}
+
+	ks.Slots = append(ks.Slots, slot)
+
+	return f.writeKeySlots(ks)
+}
+
+// RemoveKeySlot stops the key with public id from opening the
+// repository. Whoever held that key may have kept the master key, and
+// can decrypt data written with it until the master key is rotated.
+func (f *FS) RemoveKeySlot(id []byte) error {
+	if f.readOnly {
+		return ErrReadOnly
+	}
+
+	f.txnlock.Lock()
+	defer f.txnlock.Unlock()
+
+	ks, err := f.readKeySlots()
+	if err != nil {
+		return err
+	}
+
+	if ks == nil {
+		return ErrKeySlotNotFound
+	}
+
+	if ks.NextMaster != nil {
+		return ErrRotationInProgress
+	}
+
+	for i, slot := range ks.Slots {
+		if bytes.Equal(slot.KeyId, id) {
+			if len(ks.Slots) == 1 {
+				return ErrLastKeySlot
+			}
+
+			ks.Slots = append(ks.Slots[:i], ks.Slots[i+1:]...)
+			return f.writeKeySlots(ks)
+		}
+	}
+
+	return ErrKeySlotNotFound
+}
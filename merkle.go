@@ -0,0 +1,190 @@
+package yfs
+
+import (
+	"bytes"
+	"encoding/binary"
+	"os"
+	"sort"
+
+	"github.com/evanphx/yfs/format"
+	"github.com/golang/crypto/blake2b"
+)
+
+// A snapshot's Merkle tree has a leaf for every TOC path, in sorted
+// order, committing to the path and its Entry.Hash. The tree is built as
+// in RFC 6962, with leaves and interior nodes hashed with distinct
+// prefixes so one can't be passed off as the other.
+const (
+	merkleLeafPrefix = 0
+	merkleNodePrefix = 1
+)
+
+// InclusionProof shows that Path, with contents hashing to Hash, is one
+// of the Size paths of the snapshot whose Merkle root it's checked
+// against.
+type InclusionProof struct {
+	Path     string
+	Hash     []byte
+	Index    int
+	Size     int
+	Siblings [][]byte
+}
+
+func merkleLeaf(path string, hash []byte) []byte {
+	var lbuf [binary.MaxVarintLen64]byte
+
+	n := binary.PutUvarint(lbuf[:], uint64(len(path)))
+
+	var buf bytes.Buffer
+	buf.WriteByte(merkleLeafPrefix)
+	buf.Write(lbuf[:n])
+	buf.WriteString(path)
+	buf.Write(hash)
+
+	sum := blake2b.Sum256(buf.Bytes())
+	return sum[:]
+}
+
+func merkleNode(left, right []byte) []byte {
+	buf := make([]byte, 0, 1+len(left)+len(right))
+	buf = append(buf, merkleNodePrefix)
+	buf = append(buf, left...)
+	buf = append(buf, right...)
+
+	sum := blake2b.Sum256(buf)
+	return sum[:]
+}
+
+// merkleSplit is the largest power of 2 smaller than n.
+func merkleSplit(n int) int {
+	k := 1
+	for k<<1 < n {
+		k <<= 1
+	}
+
+	return k
+}
+
+func merkleRoot(leaves [][]byte) []byte {
+	switch len(leaves) {
+	case 0:
+		sum := blake2b.Sum256(nil)
+		return sum[:]
+	case 1:
+		return leaves[0]
+	}
+
+	k := merkleSplit(len(leaves))
+
+	return merkleNode(merkleRoot(leaves[:k]), merkleRoot(leaves[k:]))
+}
+
+// merklePath returns the siblings needed to rebuild the root from leaf
+// m, nearest first.
+func merklePath(leaves [][]byte, m int) [][]byte {
+	if len(leaves) <= 1 {
+		return nil
+	}
+
+	k := merkleSplit(len(leaves))
+
+	if m < k {
+		return append(merklePath(leaves[:k], m), merkleRoot(leaves[k:]))
+	}
+
+	return append(merklePath(leaves[k:], m-k), merkleRoot(leaves[:k]))
+}
+
+// merkleLeaves returns the leaves of the snapshot's tree, and the sorted
+// paths and content hashes they're for.
+func (f *FS) merkleLeaves() ([]string, [][]byte, [][]byte, error) {
+	f.toclock.Lock()
+	defer f.toclock.Unlock()
+
+	var (
+		paths  []string
+		hashes [][]byte
+		leaves [][]byte
+	)
+
+	err := f.toc.Walk(func(path string, entry *format.Entry) error {
+		paths = append(paths, path)
+		hashes = append(hashes, entry.Hash)
+		leaves = append(leaves, merkleLeaf(path, entry.Hash))
+		return nil
+	})
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	return paths, hashes, leaves, nil
+}
+
+// MerkleRoot returns a hash that commits to every path in the snapshot
+// and the hash of its contents.
+func (f *FS) MerkleRoot() ([]byte, error) {
+	_, _, leaves, err := f.merkleLeaves()
+	if err != nil {
+		return nil, err
+	}
+
+	return merkleRoot(leaves), nil
+}
+
+// ProveInclusion returns a proof that path, with its current contents,
+// is in the snapshot with root MerkleRoot.
+func (f *FS) ProveInclusion(path string) (*InclusionProof, error) {
+	paths, hashes, leaves, err := f.merkleLeaves()
+	if err != nil {
+		return nil, err
+	}
+
+	i := sort.SearchStrings(paths, path)
+	if i == len(paths) || paths[i] != path {
+		return nil, os.ErrNotExist
+	}
+
+	return &InclusionProof{
+		Path:     path,
+		Hash:     hashes[i],
+		Index:    i,
+		Size:     len(leaves),
+		Siblings: merklePath(leaves, i),
+	}, nil
+}
+
+// VerifyInclusion reports if proof shows that proof.Path, with contents
+// hashing to proof.Hash, is in the snapshot whose Merkle root is root.
+func VerifyInclusion(root []byte, proof *InclusionProof) bool {
+	if proof.Index < 0 || proof.Index >= proof.Size {
+		return false
+	}
+
+	var (
+		fn = proof.Index
+		sn = proof.Size - 1
+		r  = merkleLeaf(proof.Path, proof.Hash)
+	)
+
+	for _, sib := range proof.Siblings {
+		if sn == 0 {
+			return false
+		}
+
+		if fn&1 == 1 || fn == sn {
+			r = merkleNode(sib, r)
+
+			for fn&1 == 0 && fn != 0 {
+				fn >>= 1
+				sn >>= 1
+			}
+		} else {
+			r = merkleNode(r, sib)
+		}
+
+		fn >>= 1
+		sn >>= 1
+	}
+
+	return sn == 0 && bytes.Equal(r, root)
+}
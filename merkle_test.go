@@ -0,0 +1,44 @@
+package yfs
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vektra/neko"
+)
+
+func TestMerkle(t *testing.T) {
+	n := neko.Modern(t)
+
+	n.It("verifies proofs for every leaf of trees of any size", func(t *testing.T) {
+		for size := 1; size <= 33; size++ {
+			var leaves [][]byte
+
+			for i := 0; i < size; i++ {
+				leaves = append(leaves, merkleLeaf(fmt.Sprintf("p%d", i), []byte{byte(i)}))
+			}
+
+			root := merkleRoot(leaves)
+
+			for i := 0; i < size; i++ {
+				proof := &InclusionProof{
+					Path:     fmt.Sprintf("p%d", i),
+					Hash:     []byte{byte(i)},
+					Index:    i,
+					Size:     size,
+					Siblings: merklePath(leaves, i),
+				}
+
+				assert.True(t, VerifyInclusion(root, proof), "leaf %d of %d", i, size)
+
+				if size > 1 {
+					proof.Index = (i + 1) % size
+					assert.False(t, VerifyInclusion(root, proof), "leaf %d of %d", i, size)
+				}
+			}
+		}
+	})
+
+	n.Meow()
+}
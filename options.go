@@ -1,6 +1,11 @@
 package yfs
 
-import "path/filepath"
+import (
+	"path/filepath"
+
+	"github.com/aclements/go-rabin/rabin"
+	"github.com/evanphx/yfs/format"
+)
 
 type Option func(*FS)
 
@@ -14,5 +19,103 @@ func WithSettingsFrom(parent *FS) Option {
 	return Option(func(f *FS) {
 		f.blockAccess.read = parent.blockAccess.read
 		f.blockAccess.write = parent.blockAccess.write
+		f.bloomFP = parent.bloomFP
+		f.blockAccess.store = parent.blockAccess.store
+		f.chunking = parent.chunking
+		f.codec = parent.codec
+		f.zstdLevel = parent.zstdLevel
+		f.adaptive = parent.adaptive
+		f.encKey = parent.encKey
+		f.convergent = parent.convergent
+		f.signingKey = parent.signingKey
+		f.trustedKeys = parent.trustedKeys
+		f.readOnly = parent.readOnly
+		f.tocCacheNodes = parent.tocCacheNodes
+		f.blockCache = parent.blockCache
+		f.readAhead = parent.readAhead
+		f.writeWorkers = parent.writeWorkers
+		f.progress = parent.progress
+	})
+}
+
+// DefaultTOCCacheNodes is how many TOC leaves a read only FS keeps loaded
+// unless WithTOCCacheNodes says otherwise.
+const DefaultTOCCacheNodes = 64
+
+// WithReadOnly opens the repository for reading only. Only the head's
+// header is read when opening: its TOC is loaded as paths are looked up,
+// keeping the leaves bounded as WithTOCCacheNodes describes, and
+// blocks.idx is only read if HasBlock is called. Nothing is written to
+// the repository, and writes fail with ErrReadOnly.
+func WithReadOnly() Option {
+	return Option(func(f *FS) {
+		f.readOnly = true
+	})
+}
+
+// WithTOCCacheNodes bounds how many TOC leaves, of up to 1024 entries
+// each, are kept loaded. The root and interior nodes, which only list
+// other nodes, stay loaded once read, and so do leaves with changes that
+// haven't been committed. Repositories from before format version 3 keep
+// their whole TOC in the root, so it isn't bounded until they're
+// upgraded. The default is no limit, or DefaultTOCCacheNodes for read only
+// FSs.
+func WithTOCCacheNodes(nodes int) Option {
+	return Option(func(f *FS) {
+		f.tocCacheNodes = nodes
+	})
+}
+
+// WithReadAhead reads and decodes up to blocks blocks of a file in
+// parallel ahead of the one being read, for readers that are bound by
+// decryption and decompression rather than the disk. Bytes are still
+// returned in order.
+func WithReadAhead(blocks int) Option {
+	return Option(func(f *FS) {
+		f.readAhead = blocks
+	})
+}
+
+// WithWriteWorkers hashes, compresses, encrypts and stores the blocks of
+// files being written with up to workers goroutines, for writers that are
+// bound by the CPU rather than the disk. Only a few blocks per worker are
+// held in memory at once, and blocks are still recorded in file order.
+func WithWriteWorkers(workers int) Option {
+	return Option(func(f *FS) {
+		f.writeWorkers = workers
+	})
+}
+
+// WithPackFiles stores new blocks appended to pack files of up to
+// packSize bytes instead of one file per block. A packSize of 0 uses
+// DefaultPackSize. Repositories that already have packs use them
+// automatically.
+func WithPackFiles(packSize int64) Option {
+	return Option(func(f *FS) {
+		f.usePacks = true
+		f.packSize = packSize
+	})
+}
+
+// WithBloomFalsePositiveRate sets the false positive rate the block
+// bloom filters are sized for when they are rebuilt. Lower rates make
+// the filters larger but avoid more index scans.
+func WithBloomFalsePositiveRate(p float64) Option {
+	return Option(func(f *FS) {
+		f.bloomFP = p
+	})
+}
+
+// WithChunking sets the content defined chunking parameters for a new
+// repository. Opening an existing repository with parameters that differ
+// from the ones in its config fails with ErrChunkingMismatch. A zero
+// Polynomial uses rabin.Poly64 for the Rabin algorithm.
+func WithChunking(params ChunkParams) Option {
+	return Option(func(f *FS) {
+		if params.Algorithm == format.Rabin && params.Polynomial == 0 {
+			params.Polynomial = rabin.Poly64
+		}
+
+		f.chunking = &params
 	})
 }
@@ -0,0 +1,417 @@
+package yfs
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+
+	"github.com/evanphx/yfs/format"
+)
+
+// DefaultPackSize is the size a pack file is allowed to grow to before
+// a new one is started.
+const DefaultPackSize = 32 << 20
+
+// RepackGarbageRatio is the fraction of a pack that must be garbage
+// before Repack rewrites it.
+const RepackGarbageRatio = 0.25
+
+// packStore appends blocks to large container files instead of writing
+// each block to its own file. The location of every live block is kept
+// in packs/index, which is rewritten on Flush and encrypted like
+// blocks.idx.
+//
+// Blocks that aren't found in the index are looked up as loose files,
+// so a repository can be switched to packs without rewriting it.
+type packStore struct {
+	root     string
+	loose    fileStore
+	packSize int64
+
+	// ba encrypts the index like blocks.idx.
+	ba *blockAccess
+
+	mu    sync.RWMutex
+	index map[string]*format.PackLocation
+	dirty bool
+
+	cur     *os.File
+	curPack uint32
+	curSize int64
+	maxPack uint32
+}
+
+func newPackStore(root string, loose fileStore, packSize int64, ba *blockAccess) (*packStore, error) {
+	if packSize <= 0 {
+		packSize = DefaultPackSize
+	}
+
+	err := os.MkdirAll(root, 0755)
+	if err != nil {
+		return nil, err
+	}
+
+	ps := &packStore{
+		root:     root,
+		loose:    loose,
+		packSize: packSize,
+		ba:       ba,
+		index:    make(map[string]*format.PackLocation),
+	}
+
+	err = ps.readIndex()
+	if err != nil {
+		return nil, err
+	}
+
+	return ps, nil
+}
+
+func (ps *packStore) indexPath() string {
+	return filepath.Join(ps.root, "index")
+}
+
+func (ps *packStore) packPath(n uint32) string {
+	return filepath.Join(ps.root, fmt.Sprintf("%08x.pack", n))
+}
+
+func (ps *packStore) readIndex() error {
+	data, err := ioutil.ReadFile(ps.indexPath())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+
+		return err
+	}
+
+	data, sealed, err := ps.ba.openIndex(data, ps.ba.packIndexAD())
+	if err != nil {
+		return err
+	}
+
+	// Written before the index was encrypted, encrypt it at the next
+	// flush.
+	if !sealed && ps.ba.write.encryption != nil {
+		ps.dirty = true
+	}
+
+	var idx format.PackIndex
+
+	err = idx.Unmarshal(data)
+	if err != nil {
+		return err
+	}
+
+	for _, loc := range idx.Blocks {
+		ps.index[string(loc.Id)] = loc
+
+		if loc.Pack > ps.maxPack {
+			ps.maxPack = loc.Pack
+		}
+	}
+
+	return nil
+}
+
+// openPack makes sure there is a pack to append to, starting a new one
+// when the current one can't take another sz bytes.
+func (ps *packStore) openPack(sz int64) error {
+	if ps.cur != nil && ps.curSize+sz <= ps.packSize {
+		return nil
+	}
+
+	if ps.cur != nil {
+		err := ps.cur.Close()
+		if err != nil {
+			return err
+		}
+
+		ps.cur = nil
+		ps.maxPack++
+	} else if ps.maxPack == 0 {
+		ps.maxPack = 1
+	}
+
+	n := ps.maxPack
+
+	f, err := os.OpenFile(ps.packPath(n), os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+
+	stat, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return err
+	}
+
+	ps.cur = f
+	ps.curPack = n
+	ps.curSize = stat.Size()
+
+	// An existing pack that is already full, start the next one.
+	if ps.curSize > 0 && ps.curSize+sz > ps.packSize {
+		return ps.openPack(sz)
+	}
+
+	return nil
+}
+
+func (ps *packStore) Put(bid BlockId, data []byte) error {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+
+	return ps.put(bid, data)
+}
+
+func (ps *packStore) put(bid BlockId, data []byte) error {
+	err := ps.openPack(int64(len(data)))
+	if err != nil {
+		return err
+	}
+
+	_, err = ps.cur.Write(data)
+	if err != nil {
+		return err
+	}
+
+	ps.index[string(bid)] = &format.PackLocation{
+		Id:     bid,
+		Pack:   ps.curPack,
+		Offset: ps.curSize,
+		Length: int64(len(data)),
+	}
+
+	ps.curSize += int64(len(data))
+	ps.dirty = true
+
+	return nil
+}
+
+func (ps *packStore) readLocation(loc *format.PackLocation) ([]byte, error) {
+	f, err := os.Open(ps.packPath(loc.Pack))
+	if err != nil {
+		return nil, err
+	}
+
+	defer f.Close()
+
+	data := make([]byte, loc.Length)
+
+	_, err = f.ReadAt(data, loc.Offset)
+	if err != nil {
+		if err == io.EOF {
+			return nil, ErrCorruptBlock
+		}
+
+		return nil, err
+	}
+
+	return data, nil
+}
+
+func (ps *packStore) Get(bid BlockId) ([]byte, error) {
+	ps.mu.RLock()
+	loc, ok := ps.index[string(bid)]
+	ps.mu.RUnlock()
+
+	if !ok {
+		return ps.loose.Get(bid)
+	}
+
+	return ps.readLocation(loc)
+}
+
+// Remove only drops the block from the index. The space it used in its
+// pack is reclaimed by Repack.
+func (ps *packStore) Remove(bid BlockId) error {
+	ps.mu.Lock()
+	_, ok := ps.index[string(bid)]
+	if ok {
+		delete(ps.index, string(bid))
+		ps.dirty = true
+	}
+	ps.mu.Unlock()
+
+	if !ok {
+		return ps.loose.Remove(bid)
+	}
+
+	return nil
+}
+
+func (ps *packStore) Flush() error {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+
+	return ps.flush()
+}
+
+func (ps *packStore) flush() error {
+	if ps.cur != nil {
+		err := ps.cur.Sync()
+		if err != nil {
+			return err
+		}
+	}
+
+	if !ps.dirty {
+		return nil
+	}
+
+	var idx format.PackIndex
+
+	for _, loc := range ps.index {
+		idx.Blocks = append(idx.Blocks, loc)
+	}
+
+	sort.Slice(idx.Blocks, func(i, j int) bool {
+		a, b := idx.Blocks[i], idx.Blocks[j]
+		if a.Pack != b.Pack {
+			return a.Pack < b.Pack
+		}
+
+		return a.Offset < b.Offset
+	})
+
+	data, err := idx.Marshal()
+	if err != nil {
+		return err
+	}
+
+	data, err = ps.ba.sealIndex(data, ps.ba.packIndexAD())
+	if err != nil {
+		return err
+	}
+
+	tmp := ps.indexPath() + ".tmp"
+
+	err = ioutil.WriteFile(tmp, data, 0644)
+	if err != nil {
+		return err
+	}
+
+	err = os.Rename(tmp, ps.indexPath())
+	if err != nil {
+		return err
+	}
+
+	ps.dirty = false
+
+	return nil
+}
+
+// rewriteIndex writes the index again, with the current transforms.
+func (ps *packStore) rewriteIndex() error {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+
+	ps.dirty = true
+
+	return ps.flush()
+}
+
+// repack rewrites every pack where at least RepackGarbageRatio of the
+// file is no longer referenced by the index. Live blocks are copied into
+// new packs and the index is flushed before the old packs are removed,
+// so an interrupted repack never loses data.
+func (ps *packStore) repack() error {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+
+	live := map[uint32]int64{}
+	blocks := map[uint32][]*format.PackLocation{}
+
+	for _, loc := range ps.index {
+		live[loc.Pack] += loc.Length
+		blocks[loc.Pack] = append(blocks[loc.Pack], loc)
+	}
+
+	files, err := ioutil.ReadDir(ps.root)
+	if err != nil {
+		return err
+	}
+
+	var victims []uint32
+
+	for _, fi := range files {
+		var n uint32
+
+		if _, err := fmt.Sscanf(fi.Name(), "%08x.pack", &n); err != nil {
+			continue
+		}
+
+		if fi.Size() == 0 {
+			continue
+		}
+
+		garbage := fi.Size() - live[n]
+
+		if float64(garbage)/float64(fi.Size()) >= RepackGarbageRatio {
+			victims = append(victims, n)
+		}
+	}
+
+	if len(victims) == 0 {
+		return nil
+	}
+
+	// Never append to a pack that is about to be removed.
+	if ps.cur != nil {
+		err = ps.cur.Close()
+		if err != nil {
+			return err
+		}
+
+		ps.cur = nil
+	}
+
+	for _, n := range victims {
+		if n > ps.maxPack {
+			ps.maxPack = n
+		}
+	}
+
+	ps.maxPack++
+
+	for _, n := range victims {
+		locs := blocks[n]
+
+		sort.Slice(locs, func(i, j int) bool {
+			return locs[i].Offset < locs[j].Offset
+		})
+
+		for _, loc := range locs {
+			data, err := ps.readLocation(loc)
+			if err != nil {
+				return err
+			}
+
+			err = ps.put(BlockId(loc.Id), data)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	ps.dirty = true
+
+	err = ps.flush()
+	if err != nil {
+		return err
+	}
+
+	for _, n := range victims {
+		err = os.Remove(ps.packPath(n))
+		if err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+
+	return nil
+}
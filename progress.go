@@ -0,0 +1,58 @@
+package yfs
+
+// ProgressOp is the kind of operation a Progress event reports on.
+type ProgressOp int
+
+const (
+	// ProgressWrite events are sent as the blocks of a file being
+	// written are stored or deduplicated.
+	ProgressWrite ProgressOp = iota
+	// ProgressRead events are sent as the blocks of a file are read.
+	ProgressRead
+	// ProgressGC events are sent as garbage collection checks the blocks
+	// in the repository.
+	ProgressGC
+)
+
+// Progress reports how far along an operation is.
+type Progress struct {
+	Op ProgressOp
+
+	// Path is the file being written or read, empty for GC.
+	Path string
+
+	// Bytes is how many bytes of the file have been written or read, or
+	// for GC how many stored bytes have been removed.
+	Bytes int64
+
+	// Blocks is how many blocks have been processed, out of TotalBlocks
+	// if that's known, which it isn't for writes.
+	Blocks      int
+	TotalBlocks int
+
+	// Written and Deduped count the blocks of a file being written that
+	// were stored and that were already in the head.
+	Written int
+	Deduped int
+
+	// Removed counts the blocks GC has removed, not counting those that
+	// were already gone.
+	Removed int
+}
+
+// WithProgress calls fn as files are written and read and as garbage
+// collection runs, after every block. fn is called by the goroutine
+// doing the work, so it should be quick, handing events off to a channel
+// for example. With WithWriteWorkers the events of a write come from its
+// workers, one at a time.
+func WithProgress(fn func(Progress)) Option {
+	return Option(func(f *FS) {
+		f.progress = fn
+	})
+}
+
+func (f *FS) report(p Progress) {
+	if f.progress != nil {
+		f.progress(p)
+	}
+}
@@ -0,0 +1,338 @@
+package yfs
+
+import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/evanphx/yfs/format"
+)
+
+// RotationMode selects how much work RotateKey does.
+type RotationMode int
+
+const (
+	// RotateRewrap replaces the slot of the key the repository was
+	// opened with by one for the new key. The master key and the blocks
+	// are untouched, so it's cheap, but anyone who saw the master key
+	// can still read the repository.
+	RotateRewrap RotationMode = iota
+
+	// RotateReencrypt generates a new master key, wraps it for every key
+	// slot and re-encrypts every block and head with it. Keys whose slots
+	// were removed, and anyone who kept the old master key, lose access.
+	RotateReencrypt
+)
+
+// rotateFlushEvery is how many blocks are re-encrypted between flushes
+// of the block store, bounding the work redone when a rotation resumes.
+const rotateFlushEvery = 1024
+
+var (
+	ErrUnknownRotationMode = errors.New("unknown key rotation mode")
+	ErrReencryptRequired   = errors.New("the repository key is its master key, rotating it requires re-encryption")
+	ErrRotationInProgress  = errors.New("a key rotation is in progress, finish it with a key that opened the repository before it began")
+)
+
+// RotateKey replaces the key the repository was opened with by newKey,
+// as described by mode. newKey may be nil with RotateReencrypt to only
+// replace the master key, for instance after removing a key slot.
+//
+// A re-encryption interrupted part way is resumed by calling RotateKey
+// again with RotateReencrypt, newKey is then ignored. Until then the
+// repository stays usable with the keys it had before the rotation
+// began. progress, if not nil, is called after each block and head with
+// how many of them are done.
+func (f *FS) RotateKey(newKey *Key, mode RotationMode, progress func(done, total int)) error {
+	if f.readOnly {
+		return ErrReadOnly
+	}
+
+	if f.masterKey == nil {
+		return ErrNoMasterKey
+	}
+
+	f.txnlock.Lock()
+	defer f.txnlock.Unlock()
+
+	ks, err := f.readKeySlots()
+	if err != nil {
+		return err
+	}
+
+	switch mode {
+	case RotateRewrap:
+		return f.rewrapKey(ks, newKey)
+	case RotateReencrypt:
+		return f.reencrypt(ks, newKey, progress)
+	default:
+		return ErrUnknownRotationMode
+	}
+}
+
+func (f *FS) rewrapKey(ks *format.KeySlots, newKey *Key) error {
+	if ks == nil || bytes.Equal(ks.MasterId, f.encKey.pub[:]) {
+		return ErrReencryptRequired
+	}
+
+	if ks.NextMaster != nil {
+		return ErrRotationInProgress
+	}
+
+	if newKey == nil {
+		newKey = f.encKey
+	}
+
+	slots, err := rewrapSlots(f.masterKey, ks.Slots, f.encKey, newKey)
+	if err != nil {
+		return err
+	}
+
+	ks.Slots = slots
+
+	err = f.writeKeySlots(ks)
+	if err != nil {
+		return err
+	}
+
+	f.encKey = newKey
+
+	return nil
+}
+
+// rewrapSlots wraps master for the key of every slot in slots, giving
+// the slot of oldKey to newKey.
+func rewrapSlots(master *Key, slots []*format.KeySlot, oldKey, newKey *Key) ([]*format.KeySlot, error) {
+	var out []*format.KeySlot
+
+	for _, slot := range slots {
+		id := slot.KeyId
+
+		if bytes.Equal(id, oldKey.pub[:]) {
+			id = newKey.Id()
+		} else if bytes.Equal(id, newKey.pub[:]) {
+			return nil, ErrKeySlotExists
+		}
+
+		wrapped, err := wrapKey(master, slot.Name, id)
+		if err != nil {
+			return nil, err
+		}
+
+		out = append(out, wrapped)
+	}
+
+	return out, nil
+}
+
+func (f *FS) reencrypt(ks *format.KeySlots, newKey *Key, progress func(done, total int)) error {
+	if ks == nil || ks.NextMaster == nil {
+		err := f.beginRotation(ks, newKey)
+		if err != nil {
+			return err
+		}
+	}
+
+	next, prev := f.masterKey, f.prevMasterKey
+
+	f.blockslock.RLock()
+	ids := make([]BlockId, len(f.blocks.Blocks))
+	for i, blk := range f.blocks.Blocks {
+		ids[i] = blk.Id
+	}
+	f.blockslock.RUnlock()
+
+	heads, err := ioutil.ReadDir(filepath.Join(f.root, "heads"))
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	var (
+		total = len(ids) + len(heads)
+		done  int
+
+		store    = f.blockAccess.blockStore()
+		writer   = f.blockAccess.write.encryption
+		nextRead = &cryptReader{key: next}
+		prevRead = &cryptReader{key: prev}
+	)
+
+	for _, id := range ids {
+		raw, err := store.Get(id)
+		if err != nil {
+			return err
+		}
+
+		// Blocks written since the rotation began, or re-encrypted
+		// before it was interrupted, are already done.
+		ad := f.blockAccess.blockAD(id)
+
+		if _, _, err := nextRead.TransformAD(raw, ad); err != nil {
+			pt, _, err := prevRead.TransformAD(raw, ad)
+			if err != nil {
+				return err
+			}
+
+			data, _, err := transformAD(writer, pt, ad)
+			if err != nil {
+				return err
+			}
+
+			err = store.Put(id, data)
+			if err != nil {
+				return err
+			}
+		}
+
+		done++
+
+		if done%rotateFlushEvery == 0 {
+			err = store.Flush()
+			if err != nil {
+				return err
+			}
+		}
+
+		if progress != nil {
+			progress(done, total)
+		}
+	}
+
+	err = store.Flush()
+	if err != nil {
+		return err
+	}
+
+	// Heads go after the blocks they refer to, so a head encrypted to
+	// the new master key never refers to blocks that aren't.
+	for _, head := range heads {
+		err = f.rewriteHead(filepath.Join(f.root, "heads", head.Name()), &f.blockAccess, &f.blockAccess,
+			func(h *format.TOCHeader) bool {
+				if bytes.Equal(h.KeyId, next.pub[:]) {
+					return false
+				}
+
+				h.KeyId = next.Id()
+				return true
+			})
+		if err != nil {
+			return err
+		}
+
+		done++
+
+		if progress != nil {
+			progress(done, total)
+		}
+	}
+
+	f.blockslock.RLock()
+	err = f.writeBlockIndex(&f.blockAccess, f.blocks)
+	f.blockslock.RUnlock()
+
+	if err != nil {
+		return err
+	}
+
+	if ps, ok := f.blockAccess.store.(*packStore); ok {
+		err = ps.rewriteIndex()
+		if err != nil {
+			return err
+		}
+	}
+
+	return f.finishRotation()
+}
+
+// beginRotation generates the new master key and records it in the key
+// slots file, so that an interrupted rotation can be resumed.
+func (f *FS) beginRotation(ks *format.KeySlots, newKey *Key) error {
+	if newKey == nil {
+		newKey = f.encKey
+	}
+
+	prev := f.masterKey
+	next := GenerateKey()
+
+	var slots []*format.KeySlot
+
+	if ks == nil {
+		// The key the repository was opened with is its master key,
+		// from here on it gets a slot like any other key.
+		ks = &format.KeySlots{MasterId: prev.Id()}
+
+		slot, err := wrapKey(next, "", newKey.Id())
+		if err != nil {
+			return err
+		}
+
+		slots = append(slots, slot)
+	} else {
+		var err error
+
+		slots, err = rewrapSlots(next, ks.Slots, f.encKey, newKey)
+		if err != nil {
+			return err
+		}
+
+		if bytes.Equal(ks.MasterId, f.encKey.pub[:]) {
+			slot, err := wrapKey(next, "", newKey.Id())
+			if err != nil {
+				return err
+			}
+
+			slots = append(slots, slot)
+		}
+	}
+
+	wrapped, err := wrapKey(next, "", prev.Id())
+	if err != nil {
+		return err
+	}
+
+	ks.NextMasterId = next.Id()
+	ks.NextMaster = wrapped
+	ks.NextSlots = slots
+
+	err = f.writeKeySlots(ks)
+	if err != nil {
+		return err
+	}
+
+	f.encKey = newKey
+
+	return f.useMasterKey(next, prev)
+}
+
+// finishRotation makes the new master key the only one, and drops the
+// copies of blocks still encrypted to the old one from pack files.
+func (f *FS) finishRotation() error {
+	ks, err := f.readKeySlots()
+	if err != nil {
+		return err
+	}
+
+	ks.MasterId = ks.NextMasterId
+	ks.Slots = ks.NextSlots
+	ks.NextMasterId = nil
+	ks.NextMaster = nil
+	ks.NextSlots = nil
+
+	err = f.writeKeySlots(ks)
+	if err != nil {
+		return err
+	}
+
+	err = f.useMasterKey(f.masterKey, nil)
+	if err != nil {
+		return err
+	}
+
+	if ps, ok := f.blockAccess.store.(*packStore); ok {
+		return ps.repack()
+	}
+
+	return nil
+}
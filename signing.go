@@ -0,0 +1,123 @@
+package yfs
+
+import (
+	"errors"
+
+	"github.com/evanphx/yfs/format"
+	"github.com/golang/crypto/blake2b"
+	"golang.org/x/crypto/ed25519"
+)
+
+var (
+	ErrUnsignedHead       = errors.New("head is not signed")
+	ErrUntrustedSignature = errors.New("head signature doesn't verify with any trusted key")
+	ErrSigningKeyRequired = errors.New("rewriting a signed head requires a signing key")
+	ErrInvalidSigningKey  = errors.New("signing key must be an ed25519 private key")
+	ErrInvalidTrustedKey  = errors.New("trusted key must be an ed25519 public key")
+)
+
+var headSignatureContext = []byte("yfs head signature")
+
+// WithSigningKey signs every head written with key. The signature covers
+// the head header and the Sum of the TOC's BlockSet, which commits to
+// the contents of every file, so heads can be checked for tampering
+// whether or not the repository is encrypted. Heads of repositories
+// before format version 2 may have no room for a signature, writing them
+// then fails with ErrHeadTooLarge until the repository is upgraded.
+func WithSigningKey(key ed25519.PrivateKey) Option {
+	return Option(func(f *FS) {
+		f.signingKey = key
+	})
+}
+
+// WithTrustedKeys refuses to open heads that aren't signed by one of
+// keys.
+func WithTrustedKeys(keys ...ed25519.PublicKey) Option {
+	return Option(func(f *FS) {
+		f.trustedKeys = keys
+	})
+}
+
+func (f *FS) checkSigningKeys() error {
+	if f.signingKey != nil && len(f.signingKey) != ed25519.PrivateKeySize {
+		return ErrInvalidSigningKey
+	}
+
+	for _, key := range f.trustedKeys {
+		if len(key) != ed25519.PublicKeySize {
+			return ErrInvalidTrustedKey
+		}
+	}
+
+	return nil
+}
+
+// headSignedData is what a head's signature covers: its header, without
+// the signature and MAC, the Sum of its TOC BlockSet, and a hash of its
+// block list section.
+func headSignedData(h *format.TOCHeader, tocSum, blocks []byte) ([]byte, error) {
+	hc := *h
+	hc.Signature = nil
+	hc.Mac = nil
+
+	data, err := hc.Marshal()
+	if err != nil {
+		return nil, err
+	}
+
+	blocksSum := blake2b.Sum256(blocks)
+
+	msg := append([]byte(nil), headSignatureContext...)
+	msg = append(msg, data...)
+	msg = append(msg, tocSum...)
+	msg = append(msg, blocksSum[:]...)
+
+	return msg, nil
+}
+
+// addHeadSignature signs a head about to be written, if there's a
+// signing key.
+func (f *FS) addHeadSignature(h *format.TOCHeader, tocSum, blocks []byte) error {
+	h.Signature = nil
+	h.Signer = nil
+
+	if f.signingKey == nil {
+		return nil
+	}
+
+	h.Signer = f.signingKey.Public().(ed25519.PublicKey)
+
+	msg, err := headSignedData(h, tocSum, blocks)
+	if err != nil {
+		return err
+	}
+
+	h.Signature = ed25519.Sign(f.signingKey, msg)
+
+	return nil
+}
+
+// checkHeadSignature verifies a head that was read against the trusted
+// keys, if there are any.
+func (f *FS) checkHeadSignature(h *format.TOCHeader, tocSum, blocks []byte) error {
+	if len(f.trustedKeys) == 0 {
+		return nil
+	}
+
+	if h.Signature == nil {
+		return ErrUnsignedHead
+	}
+
+	msg, err := headSignedData(h, tocSum, blocks)
+	if err != nil {
+		return err
+	}
+
+	for _, key := range f.trustedKeys {
+		if ed25519.Verify(key, msg, h.Signature) {
+			return nil
+		}
+	}
+
+	return ErrUntrustedSignature
+}
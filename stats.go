@@ -0,0 +1,146 @@
+package yfs
+
+import (
+	"math/bits"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/evanphx/yfs/format"
+)
+
+// Stats describes what a repository holds and how much space it takes.
+type Stats struct {
+	// LogicalBytes is the size of every file in every head, counting
+	// files that are in several heads once per head.
+	LogicalBytes int64
+
+	// UniqueBytes and StoredBytes are the size of the blocks of files
+	// in any head, before and after compression and encryption.
+	UniqueBytes int64
+	StoredBytes int64
+
+	// DedupRatio is LogicalBytes over UniqueBytes, and CompressionRatio
+	// is UniqueBytes over StoredBytes. They're 0 for empty repositories.
+	DedupRatio       float64
+	CompressionRatio float64
+
+	// Blocks is how many blocks files in any head have.
+	Blocks int
+
+	// BlockSizes is a histogram of the sizes of those blocks:
+	// BlockSizes[i] counts the blocks of 2^(i-1) up to 2^i - 1 bytes.
+	BlockSizes []int
+
+	// TOCBlocks is how many blocks hold the TOCs of the heads, and
+	// TOCBytes their stored size. They're left out of the fields above.
+	TOCBlocks int
+	TOCBytes  int64
+
+	// Heads has the stats of each head by name, and Snapshots is how
+	// many of them are heads other than the one this FS uses.
+	Heads     map[string]HeadStats
+	Snapshots int
+}
+
+// HeadStats describes the files in one head.
+type HeadStats struct {
+	Files int
+	Bytes int64
+}
+
+// Stats reads every head to describe the repository. Only what has been
+// committed is counted.
+func (f *FS) Stats() (*Stats, error) {
+	heads, err := readDirNames(filepath.Join(f.root, "heads"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return &Stats{Heads: map[string]HeadStats{}}, nil
+		}
+
+		return nil, err
+	}
+
+	sort.Strings(heads)
+
+	st := &Stats{Heads: make(map[string]HeadStats, len(heads))}
+
+	var (
+		blocks = make(map[string]*format.BlockInfo)
+		toc    = make(map[string]bool)
+	)
+
+	for _, head := range heads {
+		hf, err := f.unmarshalTOC(filepath.Join(f.root, "heads", head), true)
+		if err != nil {
+			return nil, err
+		}
+
+		var hs HeadStats
+
+		err = hf.toc.Walk(func(path string, entry *format.Entry) error {
+			if entry.Type == File {
+				hs.Files++
+			}
+
+			hs.Bytes += entry.ByteSize
+
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		st.Heads[head] = hs
+		st.LogicalBytes += hs.Bytes
+
+		if filepath.Join("heads", head) != f.tocPath {
+			st.Snapshots++
+		}
+
+		sets, err := hf.toc.Sets()
+		if err != nil {
+			return nil, err
+		}
+
+		for _, set := range sets {
+			for _, blk := range set.Blocks {
+				toc[string(blk.Id)] = true
+			}
+		}
+
+		for _, info := range hf.blocks.Blocks {
+			blocks[string(info.Id)] = info
+		}
+	}
+
+	for id, info := range blocks {
+		if toc[id] {
+			st.TOCBlocks++
+			st.TOCBytes += info.CompSize
+			continue
+		}
+
+		st.Blocks++
+		st.UniqueBytes += info.ByteSize
+		st.StoredBytes += info.CompSize
+
+		bucket := bits.Len64(uint64(info.ByteSize))
+
+		for len(st.BlockSizes) <= bucket {
+			st.BlockSizes = append(st.BlockSizes, 0)
+		}
+
+		st.BlockSizes[bucket]++
+	}
+
+	if st.UniqueBytes > 0 {
+		st.DedupRatio = float64(st.LogicalBytes) / float64(st.UniqueBytes)
+	}
+
+	if st.StoredBytes > 0 {
+		st.CompressionRatio = float64(st.UniqueBytes) / float64(st.StoredBytes)
+	}
+
+	return st, nil
+}
@@ -2,39 +2,94 @@ package yfs
 
 import (
 	"bytes"
+	"crypto/hmac"
+	"encoding/binary"
+	"errors"
 	"io/ioutil"
+	"os"
+	"path/filepath"
 
 	"github.com/evanphx/yfs/format"
 	"github.com/golang/crypto/blake2b"
 )
 
-func (f *FS) unmarshalTOC(path string) (*format.TOCHeader, *format.TOC, *format.BlockTOC, error) {
-	data, err := ioutil.ReadFile(path)
-	if err != nil {
-		return nil, nil, nil, err
+// Heads before version 2 start with a byte giving the header's length,
+// and the header, padded to headFixedSize. Later heads start with
+// headVarintFrame, which a length byte can't be, and the header's length
+// as a uvarint, with the sections right after the header.
+const (
+	headFixedSize   = 256
+	headFixedMax    = 247
+	headVarintFrame = 0xff
+)
+
+// ErrHeadTooLarge is returned when writing a head whose header, usually
+// because of a signature, doesn't fit the fixed frame of format versions
+// before 2. Upgrade the repository to write it.
+var ErrHeadTooLarge = errors.New("head header is too large for this format version, upgrade the repository")
+
+// headFile is a decoded head.
+type headFile struct {
+	header *format.TOCHeader
+	set    *format.BlockSet
+	toc    *tocTree
+	blocks *format.BlockTOC
+}
+
+// parseHead splits a head into its header and its TOC and block list
+// sections, checking that the header can be read with this FS.
+func (f *FS) parseHead(data []byte) (*format.TOCHeader, []byte, []byte, error) {
+	if len(data) == 0 {
+		return nil, nil, nil, ErrCorruptTOC
 	}
 
-	var fheader format.TOCHeader
+	var hstart, hend int
 
-	sz := data[0]
+	if data[0] == headVarintFrame {
+		hlen, n := binary.Uvarint(data[1:])
+		if n <= 0 || hlen > uint64(len(data)) {
+			return nil, nil, nil, ErrCorruptTOC
+		}
 
-	err = fheader.Unmarshal(data[1 : 1+sz])
+		hstart = 1 + n
+		hend = hstart + int(hlen)
+
+		if hend > len(data) {
+			return nil, nil, nil, ErrCorruptTOC
+		}
+	} else {
+		if len(data) < headFixedSize {
+			return nil, nil, nil, ErrCorruptTOC
+		}
+
+		hstart = 1
+		hend = 1 + int(data[0])
+	}
+
+	var fheader format.TOCHeader
+
+	err := fheader.Unmarshal(data[hstart:hend])
 	if err != nil {
 		return nil, nil, nil, err
 	}
 
-	if f.tocHeader.Compressed != fheader.Compressed {
+	// Tagged blocks record their own codec, so only legacy
+	// repositories have to agree on compression.
+	if f.config.BlockFrame == blockFrameLegacy && f.tocHeader.Compressed != fheader.Compressed {
 		return nil, nil, nil, ErrCompressionMismatch
 	}
 
-	if !bytes.Equal(f.tocHeader.KeyId, fheader.KeyId) {
-		return nil, nil, nil, ErrWrongEncryptionKey
+	if fheader.Version > FormatVersion {
+		return nil, nil, nil, ErrUnsupportedHeadVersion
 	}
 
-	dataSum := blake2b.Sum256(data[256 : 256+fheader.TocSize])
+	if !f.validKeyId(fheader.KeyId) {
+		return nil, nil, nil, ErrWrongEncryptionKey
+	}
 
-	if !bytes.Equal(fheader.Sum, dataSum[:]) {
-		return nil, nil, nil, ErrCorruptTOC
+	body := data[hend:]
+	if data[0] != headVarintFrame {
+		body = data[headFixedSize:]
 	}
 
 	var (
@@ -42,43 +97,324 @@ func (f *FS) unmarshalTOC(path string) (*format.TOCHeader, *format.TOC, *format.
 		blockSize = fheader.BlocksSize
 	)
 
-	buf, err := f.blockAccess.readTransform(data[256 : 256+tocSize])
+	if tocSize < 0 || blockSize < 0 || int64(len(body)) < tocSize+blockSize {
+		return nil, nil, nil, ErrCorruptTOC
+	}
+
+	tocData := body[:tocSize]
+
+	dataSum := blake2b.Sum256(tocData)
+
+	if !bytes.Equal(fheader.Sum, dataSum[:]) {
+		return nil, nil, nil, ErrCorruptTOC
+	}
+
+	return &fheader, tocData, body[tocSize : tocSize+blockSize], nil
+}
+
+// unmarshalTOC reads and decodes the head at path. Its TOC is loaded as
+// it's used, and its block list only decoded if blocks is set.
+func (f *FS) unmarshalTOC(path string, blocks bool) (*headFile, error) {
+	data, err := ioutil.ReadFile(path)
 	if err != nil {
-		return nil, nil, nil, err
+		return nil, err
+	}
+
+	fheader, tocData, bsData, err := f.parseHead(data)
+	if err != nil {
+		return nil, err
+	}
+
+	ba := &f.blockAccess
+
+	// Version 0 heads haven't been rewritten by an upgrade yet.
+	if fheader.Version == 0 && ba.legacy != nil {
+		ba = ba.legacy
+	}
+
+	err = ba.verifyHead(fheader, tocData, bsData)
+	if err != nil {
+		return nil, err
+	}
+
+	ad := ba.headAD(fheader)
+
+	buf, err := ba.readTransform(tocData, ad)
+	if err != nil {
+		return nil, err
 	}
 
 	var set format.BlockSet
 
 	err = set.Unmarshal(buf)
 	if err != nil {
-		return nil, nil, nil, err
+		return nil, err
 	}
 
-	setData, err := f.blockAccess.readSet(&set)
+	err = f.checkHeadSignature(fheader, set.Sum, bsData)
 	if err != nil {
-		return nil, nil, nil, err
+		return nil, err
 	}
 
-	var toc format.TOC
+	hf := &headFile{
+		header: fheader,
+		set:    &set,
+		toc:    openTOCTree(&f.blockAccess, &set, f.config.Version >= 3, f.tocCacheNodes),
+	}
 
-	err = toc.Unmarshal(setData)
-	if err != nil {
-		return nil, nil, nil, err
+	if !blocks {
+		return hf, nil
 	}
 
 	var bs format.BlockTOC
 
-	bsData := data[256+tocSize : 256+tocSize+blockSize]
+	buf, err = ba.readTransform(bsData, ad)
+	if err != nil {
+		return nil, err
+	}
 
-	data, err = f.blockAccess.readTransform(bsData)
+	err = bs.Unmarshal(buf)
 	if err != nil {
-		return nil, nil, nil, err
+		return nil, err
+	}
+
+	hf.blocks = &bs
+
+	return hf, nil
+}
+
+// marshalHeadHeader encodes the header that starts every head, framed
+// as its version requires.
+func marshalHeadHeader(h *format.TOCHeader) ([]byte, error) {
+	hlen := h.Size()
+
+	if h.Version >= 2 {
+		hdata := make([]byte, 1+binary.MaxVarintLen64+hlen)
+		hdata[0] = headVarintFrame
+
+		n := 1 + binary.PutUvarint(hdata[1:], uint64(hlen))
+
+		_, err := h.MarshalTo(hdata[n:])
+		if err != nil {
+			return nil, err
+		}
+
+		return hdata[:n+hlen], nil
+	}
+
+	if hlen > headFixedMax {
+		return nil, ErrHeadTooLarge
 	}
 
-	err = bs.Unmarshal(data)
+	hdata := make([]byte, headFixedSize)
+
+	_, err := h.MarshalTo(hdata[1:])
 	if err != nil {
-		return nil, nil, nil, err
+		return nil, err
+	}
+
+	hdata[0] = byte(hlen)
+
+	return hdata, nil
+}
+
+// headAD is the associated data the sections of a head with header h are
+// encrypted with, binding the header fields known before encryption.
+func (ba *blockAccess) headAD(h *format.TOCHeader) []byte {
+	if !ba.authenticated {
+		return nil
+	}
+
+	ad := []byte("yfs head")
+
+	if h.Compressed {
+		ad = append(ad, 1)
+	} else {
+		ad = append(ad, 0)
+	}
+
+	return append(ad, h.KeyId...)
+}
+
+// headMAC authenticates a head's header, without its Mac, and sections.
+func headMAC(key []byte, h *format.TOCHeader, toc, blocks []byte) ([]byte, error) {
+	hc := *h
+	hc.Mac = nil
+
+	data, err := hc.Marshal()
+	if err != nil {
+		return nil, err
+	}
+
+	m, err := blake2b.New256(key)
+	if err != nil {
+		return nil, err
+	}
+
+	m.Write(data)
+	m.Write(toc)
+	m.Write(blocks)
+
+	return m.Sum(nil), nil
+}
+
+// signHead sets the Mac of a head about to be written.
+func (ba *blockAccess) signHead(h *format.TOCHeader, toc, blocks []byte) error {
+	h.Mac = nil
+
+	if !ba.authenticated || len(ba.headKeys) == 0 {
+		return nil
+	}
+
+	mac, err := headMAC(ba.headKeys[0], h, toc, blocks)
+	if err != nil {
+		return err
+	}
+
+	h.Mac = mac
+
+	return nil
+}
+
+// verifyHead checks the Mac of a head that was read. Heads of encrypted
+// repositories with authenticated set must have one.
+func (ba *blockAccess) verifyHead(h *format.TOCHeader, toc, blocks []byte) error {
+	if len(ba.headKeys) == 0 {
+		return nil
+	}
+
+	if h.Mac == nil {
+		if ba.authenticated {
+			return ErrHeadAuthentication
+		}
+
+		return nil
+	}
+
+	for _, key := range ba.headKeys {
+		mac, err := headMAC(key, h, toc, blocks)
+		if err != nil {
+			return err
+		}
+
+		if hmac.Equal(mac, h.Mac) {
+			return nil
+		}
+	}
+
+	return ErrHeadAuthentication
+}
+
+// rewriteHead rewrites the head at path, read with from, with to. update
+// changes the header, or returns false if the head doesn't need to be
+// rewritten. Signed heads are signed again.
+func (f *FS) rewriteHead(path string, from, to *blockAccess, update func(h *format.TOCHeader) bool) error {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	orig, tocData, blocksData, err := f.parseHead(data)
+	if err != nil {
+		return err
+	}
+
+	header := *orig
+
+	if !update(&header) {
+		return nil
+	}
+
+	err = from.verifyHead(orig, tocData, blocksData)
+	if err != nil {
+		return err
+	}
+
+	fromAD := from.headAD(orig)
+
+	tocPT, err := from.readTransform(tocData, fromAD)
+	if err != nil {
+		return err
+	}
+
+	// Copy, the transforms reuse buffers.
+	tocPT = append([]byte(nil), tocPT...)
+
+	blocksPT, err := from.readTransform(blocksData, fromAD)
+	if err != nil {
+		return err
+	}
+
+	blocksPT = append([]byte(nil), blocksPT...)
+
+	var set format.BlockSet
+
+	if orig.Signature != nil {
+		if f.signingKey == nil {
+			return ErrSigningKeyRequired
+		}
+
+		err = set.Unmarshal(tocPT)
+		if err != nil {
+			return err
+		}
+
+		err = f.checkHeadSignature(orig, set.Sum, blocksData)
+		if err != nil {
+			return err
+		}
+	}
+
+	toAD := to.headAD(&header)
+
+	tocCT, err := to.writeTransform(tocPT, toAD)
+	if err != nil {
+		return err
+	}
+
+	tocCT = append([]byte(nil), tocCT...)
+
+	blocksCT, err := to.writeTransform(blocksPT, toAD)
+	if err != nil {
+		return err
+	}
+
+	sum := blake2b.Sum256(tocCT)
+
+	header.Sum = sum[:]
+	header.TocSize = int64(len(tocCT))
+	header.BlocksSize = int64(len(blocksCT))
+
+	if orig.Signature != nil {
+		err = f.addHeadSignature(&header, set.Sum, blocksCT)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = to.signHead(&header, tocCT, blocksCT)
+	if err != nil {
+		return err
+	}
+
+	hdata, err := marshalHeadHeader(&header)
+	if err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+
+	buf.Write(hdata)
+	buf.Write(tocCT)
+	buf.Write(blocksCT)
+
+	// Not in heads, where an interrupted write would look like a head.
+	tmp := filepath.Join(f.root, "head.tmp")
+
+	err = ioutil.WriteFile(tmp, buf.Bytes(), 0644)
+	if err != nil {
+		return err
 	}
 
-	return &fheader, &toc, &bs, nil
+	return os.Rename(tmp, path)
 }
@@ -0,0 +1,528 @@
+package yfs
+
+import (
+	"bytes"
+	"container/list"
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/evanphx/yfs/format"
+)
+
+// A head's TOC is stored as a B-tree of TOCNodes, each written with
+// writeAsBlocks so nodes that didn't change are shared between commits
+// and heads. Nodes are loaded as lookups reach them, and a commit only
+// rewrites the nodes on the way to changed entries. Heads before
+// version 3 hold the whole TOC in one leaf.
+const (
+	maxLeafEntries  = 1024
+	maxNodeChildren = 256
+)
+
+type tocNode struct {
+	// first is the smallest path under the node, used to find the
+	// child a path belongs to.
+	first string
+
+	// set is where the node is stored, nil if it hasn't been written.
+	set *format.BlockSet
+
+	loaded bool
+	dirty  bool
+
+	paths    map[string]*format.Entry
+	children []*tocNode
+
+	// elem is the node's place in its tree's lru.
+	elem *list.Element
+}
+
+func (n *tocNode) leaf() bool {
+	return len(n.children) == 0
+}
+
+// child returns the index of the child whose range path falls in.
+func (n *tocNode) child(path string) int {
+	i := sort.Search(len(n.children), func(i int) bool {
+		return n.children[i].first > path
+	})
+
+	if i == 0 {
+		return 0
+	}
+
+	return i - 1
+}
+
+type tocTree struct {
+	mu sync.Mutex
+
+	ba   *blockAccess
+	root *tocNode
+
+	// sharded splits nodes that grow too large. Without it the root
+	// is always a single leaf, as heads before version 3 require.
+	sharded bool
+
+	// maxLeaves is how many leaves, other than the root, are kept
+	// loaded, with 0 meaning no limit. The least recently used leaves
+	// that haven't changed are unloaded past it.
+	maxLeaves int
+	lru       *list.List
+}
+
+func newTOCTree(ba *blockAccess, sharded bool, maxLeaves int) *tocTree {
+	return &tocTree{
+		ba:        ba,
+		sharded:   sharded,
+		maxLeaves: maxLeaves,
+		lru:       list.New(),
+		root: &tocNode{
+			loaded: true,
+			paths:  make(map[string]*format.Entry),
+		},
+	}
+}
+
+// openTOCTree returns the tree whose root is stored in set, without
+// loading any of it.
+func openTOCTree(ba *blockAccess, set *format.BlockSet, sharded bool, maxLeaves int) *tocTree {
+	return &tocTree{
+		ba:        ba,
+		sharded:   sharded,
+		maxLeaves: maxLeaves,
+		lru:       list.New(),
+		root:      &tocNode{set: set},
+	}
+}
+
+// touch marks the leaf n as used, unloading the least recently used
+// leaves if there are too many.
+func (tr *tocTree) touch(n *tocNode) {
+	if tr.maxLeaves <= 0 || n == tr.root {
+		return
+	}
+
+	if n.elem != nil {
+		tr.lru.MoveToFront(n.elem)
+		return
+	}
+
+	n.elem = tr.lru.PushFront(n)
+
+	for e := tr.lru.Back(); e != nil && tr.lru.Len() > tr.maxLeaves; {
+		prev := e.Prev()
+
+		// Dirty leaves have changes that are only in memory.
+		if old := e.Value.(*tocNode); !old.dirty && old != n {
+			tr.lru.Remove(e)
+
+			old.elem = nil
+			old.loaded = false
+			old.paths = nil
+		}
+
+		e = prev
+	}
+}
+
+// forget drops n, which has been replaced, from the lru.
+func (tr *tocTree) forget(n *tocNode) {
+	if n.elem != nil {
+		tr.lru.Remove(n.elem)
+		n.elem = nil
+	}
+}
+
+func (tr *tocTree) load(n *tocNode) error {
+	if n.loaded {
+		return nil
+	}
+
+	data, err := tr.ba.readSet(n.set)
+	if err != nil {
+		return err
+	}
+
+	var node format.TOCNode
+
+	err = node.Unmarshal(data)
+	if err != nil {
+		return err
+	}
+
+	n.paths = node.Paths
+
+	for _, c := range node.Children {
+		n.children = append(n.children, &tocNode{first: c.First, set: c.Node})
+	}
+
+	if n.leaf() && n.paths == nil {
+		n.paths = make(map[string]*format.Entry)
+	}
+
+	n.loaded = true
+
+	return nil
+}
+
+func (tr *tocTree) setSharded(sharded bool) {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+
+	tr.sharded = sharded
+}
+
+// Load reads the root node, so that a head that can't be read fails
+// when it's opened.
+func (tr *tocTree) Load() error {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+
+	return tr.load(tr.root)
+}
+
+// leafFor returns the leaf path belongs in, loading the nodes on the way
+// to it, and marking them dirty if dirty is set.
+func (tr *tocTree) leafFor(path string, dirty bool) (*tocNode, error) {
+	n := tr.root
+
+	for {
+		err := tr.load(n)
+		if err != nil {
+			return nil, err
+		}
+
+		if dirty {
+			n.dirty = true
+		}
+
+		if n.leaf() {
+			tr.touch(n)
+			return n, nil
+		}
+
+		n = n.children[n.child(path)]
+	}
+}
+
+// Get returns the entry for path.
+func (tr *tocTree) Get(path string) (*format.Entry, bool, error) {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+
+	n, err := tr.leafFor(path, false)
+	if err != nil {
+		return nil, false, err
+	}
+
+	entry, ok := n.paths[path]
+	return entry, ok, nil
+}
+
+// Set stores entry as the entry for path.
+func (tr *tocTree) Set(path string, entry *format.Entry) error {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+
+	n, err := tr.leafFor(path, true)
+	if err != nil {
+		return err
+	}
+
+	n.paths[path] = entry
+
+	return nil
+}
+
+// Delete removes path, returning the entry it had.
+func (tr *tocTree) Delete(path string) (*format.Entry, bool, error) {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+
+	n, err := tr.leafFor(path, false)
+	if err != nil {
+		return nil, false, err
+	}
+
+	entry, ok := n.paths[path]
+	if !ok {
+		return nil, false, nil
+	}
+
+	// Mark the way to the leaf dirty, now that it's changing.
+	_, err = tr.leafFor(path, true)
+	if err != nil {
+		return nil, false, err
+	}
+
+	delete(n.paths, path)
+
+	return entry, true, nil
+}
+
+// Walk calls fn with every path and its entry, in path order, loading
+// the whole tree.
+func (tr *tocTree) Walk(fn func(path string, entry *format.Entry) error) error {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+
+	return tr.walk(tr.root, fn)
+}
+
+// Sets returns where each node of the tree is stored, loading the whole
+// tree. Nodes that haven't been written are left out.
+func (tr *tocTree) Sets() ([]*format.BlockSet, error) {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+
+	var sets []*format.BlockSet
+
+	err := tr.sets(tr.root, &sets)
+
+	return sets, err
+}
+
+func (tr *tocTree) sets(n *tocNode, sets *[]*format.BlockSet) error {
+	if n.set != nil {
+		*sets = append(*sets, n.set)
+	}
+
+	err := tr.load(n)
+	if err != nil {
+		return err
+	}
+
+	for _, c := range n.children {
+		err = tr.sets(c, sets)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (tr *tocTree) walk(n *tocNode, fn func(path string, entry *format.Entry) error) error {
+	err := tr.load(n)
+	if err != nil {
+		return err
+	}
+
+	for _, c := range n.children {
+		err = tr.walk(c, fn)
+		if err != nil {
+			return err
+		}
+	}
+
+	paths := n.paths
+
+	if n.leaf() {
+		tr.touch(n)
+	}
+
+	for _, path := range sortedPaths(paths) {
+		err = fn(path, paths[path])
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func sortedPaths(paths map[string]*format.Entry) []string {
+	sorted := make([]string, 0, len(paths))
+	for path := range paths {
+		sorted = append(sorted, path)
+	}
+
+	sort.Strings(sorted)
+
+	return sorted
+}
+
+// Flush writes every dirty node with t, releasing the blocks of the
+// nodes they replace, and returns where the root is stored.
+func (tr *tocTree) Flush(t *Txn) (*format.BlockSet, error) {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+
+	if !tr.root.dirty && tr.root.set != nil {
+		return tr.root.set, nil
+	}
+
+	nodes, err := tr.flush(t, tr.root)
+	if err != nil {
+		return nil, err
+	}
+
+	// Shrink the tree if deletes left the root with one child.
+	for len(nodes) == 1 && !nodes[0].leaf() && len(nodes[0].children) == 1 {
+		t.releaseTOCSet(nodes[0].set)
+		nodes = nodes[0].children
+	}
+
+	// Grow the tree until one node covers everything.
+	for len(nodes) != 1 {
+		var parents []*tocNode
+
+		if len(nodes) == 0 {
+			parents = append(parents, &tocNode{
+				loaded: true,
+				paths:  make(map[string]*format.Entry),
+			})
+		}
+
+		for len(nodes) > 0 {
+			n := len(nodes)
+			if n > maxNodeChildren {
+				n = maxNodeChildren
+			}
+
+			parents = append(parents, &tocNode{
+				first:    nodes[0].first,
+				loaded:   true,
+				children: nodes[:n:n],
+			})
+
+			nodes = nodes[n:]
+		}
+
+		for _, p := range parents {
+			err = tr.write(t, p)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		nodes = parents
+	}
+
+	tr.root = nodes[0]
+	tr.forget(tr.root)
+
+	return tr.root.set, nil
+}
+
+// flush writes n and the dirty nodes below it, returning the nodes that
+// replace n: none if it's empty, or several if it had to be split.
+func (tr *tocTree) flush(t *Txn, n *tocNode) ([]*tocNode, error) {
+	if !n.dirty && n.set != nil {
+		return []*tocNode{n}, nil
+	}
+
+	var nodes []*tocNode
+
+	if n.leaf() {
+		paths := sortedPaths(n.paths)
+
+		switch {
+		case len(paths) == 0:
+			// Only dropped if it's a child, Flush replaces an
+			// empty root.
+		case !tr.sharded || len(paths) <= maxLeafEntries:
+			nodes = append(nodes, &tocNode{paths: n.paths})
+		default:
+			for len(paths) > 0 {
+				k := len(paths)
+				if k > maxLeafEntries/2 {
+					k = maxLeafEntries / 2
+				}
+
+				leaf := &tocNode{paths: make(map[string]*format.Entry, k)}
+
+				for _, path := range paths[:k] {
+					leaf.paths[path] = n.paths[path]
+				}
+
+				nodes = append(nodes, leaf)
+				paths = paths[k:]
+			}
+		}
+	} else {
+		var children []*tocNode
+
+		for _, c := range n.children {
+			cn, err := tr.flush(t, c)
+			if err != nil {
+				return nil, err
+			}
+
+			children = append(children, cn...)
+		}
+
+		for len(children) > 0 {
+			k := len(children)
+			if k > maxNodeChildren {
+				k = maxNodeChildren / 2
+			}
+
+			nodes = append(nodes, &tocNode{children: children[:k:k]})
+			children = children[k:]
+		}
+	}
+
+	for _, node := range nodes {
+		node.loaded = true
+
+		err := tr.write(t, node)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	tr.forget(n)
+
+	for _, node := range nodes {
+		if node.leaf() {
+			tr.touch(node)
+		}
+	}
+
+	if n.set != nil {
+		t.releaseTOCSet(n.set)
+	}
+
+	return nodes, nil
+}
+
+// write stores n with t.
+func (tr *tocTree) write(t *Txn, n *tocNode) error {
+	var node format.TOCNode
+
+	if n.leaf() {
+		node.Paths = n.paths
+
+		paths := sortedPaths(n.paths)
+		if len(paths) > 0 {
+			n.first = paths[0]
+		}
+	} else {
+		n.first = n.children[0].first
+
+		for _, c := range n.children {
+			node.Children = append(node.Children, &format.TOCChild{
+				First: c.first,
+				Node:  c.set,
+			})
+		}
+	}
+
+	data, err := node.Marshal()
+	if err != nil {
+		return err
+	}
+
+	set, err := t.writeAsBlocks(context.Background(), "", bytes.NewReader(data), nil)
+	if err != nil {
+		return err
+	}
+
+	n.set = set
+	n.dirty = false
+
+	return nil
+}
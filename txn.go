@@ -2,18 +2,19 @@ package yfs
 
 import (
 	"bytes"
-	"encoding/hex"
+	"context"
 	"errors"
 	"fmt"
+	"hash"
 	"io"
 	"io/ioutil"
 	"os"
 	"os/user"
 	"path/filepath"
 	"strconv"
+	"sync"
 	"syscall"
 
-	"github.com/aclements/go-rabin/rabin"
 	"github.com/evanphx/yfs/format"
 	"github.com/golang/crypto/blake2b"
 )
@@ -25,10 +26,12 @@ type Txn struct {
 	root    string
 	tocPath string
 
-	toc       *format.TOC
+	toc       *tocTree
 	tocBlocks *format.BlockTOC
+	tocBloom  *bloomFilter
 
-	blocks *format.BlockTOC
+	blocks      *format.BlockTOC
+	blocksBloom *bloomFilter
 
 	tocHeader format.TOCHeader
 
@@ -38,28 +41,52 @@ type Txn struct {
 	blockUpdates *format.BlockTOC
 	removal      []string
 
+	// added and refd are the blocks added to tocBlocks and the ones
+	// whose references were increased since the head was last written,
+	// for rollback to undo.
+	added, refd []*format.BlockInfo
+
 	tocSet *format.BlockSet
 }
 
 var ErrReadOnly = errors.New("only read operations allowed")
 
-func (t *Txn) entryFor(path string) (*format.Entry, bool) {
+func (t *Txn) entryFor(path string) (*format.Entry, bool, error) {
 	entry, ok := t.updates.Paths[path]
 	if ok {
-		return entry, true
+		return entry, true, nil
 	}
 
-	entry, ok = t.toc.Paths[path]
-	return entry, ok
+	return t.toc.Get(path)
 }
 
 func (t *Txn) ReaderFor(path string) (io.Reader, error) {
-	entry, ok := t.entryFor(path)
+	return t.ReaderForContext(context.Background(), path)
+}
+
+// ReaderForContext is ReaderFor, with reads failing with ctx's error once
+// ctx is done.
+func (t *Txn) ReaderForContext(ctx context.Context, path string) (io.Reader, error) {
+	entry, ok, err := t.entryFor(path)
+	if err != nil {
+		return nil, err
+	}
+
 	if !ok {
 		return nil, os.ErrNotExist
 	}
 
-	return &blockReader{t: t, blocks: entry.Blocks.Blocks}, nil
+	return &blockReader{
+		t:      t,
+		ctx:    ctx,
+		blocks: entry.Blocks.Blocks,
+
+		progress: Progress{
+			Op:          ProgressRead,
+			Path:        path,
+			TotalBlocks: len(entry.Blocks.Blocks),
+		},
+	}, nil
 }
 
 func (t *Txn) WriterFor(path string) (io.WriteCloser, error) {
@@ -67,7 +94,11 @@ func (t *Txn) WriterFor(path string) (io.WriteCloser, error) {
 		return nil, ErrReadOnly
 	}
 
-	entry, ok := t.entryFor(path)
+	entry, ok, err := t.entryFor(path)
+	if err != nil {
+		return nil, err
+	}
+
 	if !ok {
 		entry = &format.Entry{}
 	}
@@ -87,7 +118,11 @@ func (t *Txn) RemoveFile(path string) error {
 		return ErrReadOnly
 	}
 
-	_, ok := t.entryFor(path)
+	_, ok, err := t.entryFor(path)
+	if err != nil {
+		return err
+	}
+
 	if !ok {
 		return os.ErrNotExist
 	}
@@ -98,11 +133,24 @@ func (t *Txn) RemoveFile(path string) error {
 }
 
 func (t *Txn) WriteFile(path string, r io.Reader) error {
-	_, err := t.writeFile(path, r, &format.Entry{})
+	return t.WriteFileContext(context.Background(), path, r)
+}
+
+// WriteFileContext is WriteFile, stopping between blocks once ctx is done.
+// A file that's stopped isn't added, and the references to its blocks
+// are undone.
+func (t *Txn) WriteFileContext(ctx context.Context, path string, r io.Reader) error {
+	_, err := t.writeFile(ctx, path, r, &format.Entry{})
 	return err
 }
 
 func (t *Txn) CopyFile(path string, of *os.File) error {
+	return t.CopyFileContext(context.Background(), path, of)
+}
+
+// CopyFileContext is CopyFile, stopping between blocks once ctx is done,
+// like WriteFileContext.
+func (t *Txn) CopyFileContext(ctx context.Context, path string, of *os.File) error {
 	stat, err := of.Stat()
 	if err != nil {
 		return err
@@ -130,7 +178,7 @@ func (t *Txn) CopyFile(path string, of *os.File) error {
 
 	ent.ModifiedAt = &format.TimeSpec{stat.ModTime().Unix(), int32(stat.ModTime().Nanosecond())}
 
-	_, err = t.writeFile(path, of, ent)
+	_, err = t.writeFile(ctx, path, of, ent)
 	if err != nil {
 		return err
 	}
@@ -167,10 +215,29 @@ func (t *Txn) CreateSnapshot(name string) error {
 }
 
 func (t *Txn) Commit() error {
+	return t.CommitContext(context.Background())
+}
+
+// CommitContext is Commit, unless ctx is done before the head is written,
+// in which case the transaction's changes since the head was last written
+// are rolled back and ctx's error is returned. Once the head is written
+// the commit stands, and a ctx that's done only stops garbage collection
+// early, leaving the rest of the unused blocks for the next commit. ctx's
+// error is still returned then, so an error doesn't always mean nothing
+// was committed.
+func (t *Txn) CommitContext(ctx context.Context) error {
 	if !t.write {
 		return nil
 	}
 
+	if err := ctx.Err(); err != nil {
+		if rerr := t.rollback(); rerr != nil {
+			return rerr
+		}
+
+		return err
+	}
+
 	defer t.f.txnlock.Unlock()
 
 	err := t.flushTOC()
@@ -178,19 +245,31 @@ func (t *Txn) Commit() error {
 		return err
 	}
 
-	err = t.gcBlocks()
+	gcErr := t.gcBlocks(ctx)
+	if gcErr != nil && gcErr != ctx.Err() {
+		return gcErr
+	}
+
+	err = t.blockAccess.flush()
 	if err != nil {
 		return err
 	}
 
-	return t.flushBlockTOC()
+	err = t.flushBlockTOC()
+	if err != nil {
+		return err
+	}
+
+	return gcErr
 }
 
-func (t *Txn) writeBlock(bid BlockId, block []byte) (int64, error) {
-	return t.blockAccess.writeBlock(bid, block)
+func (t *Txn) writeBlock(bid BlockId, block []byte, hint *compressHint) (int64, error) {
+	return t.blockAccess.writeBlock(bid, block, hint)
 }
 
-func (t *Txn) writeAsBlocks(r io.Reader) (*format.BlockSet, error) {
+// writeAsBlocks stores the data read from r as blocks, reporting
+// progress for path unless it's empty.
+func (t *Txn) writeAsBlocks(ctx context.Context, path string, r io.Reader, hint *compressHint) (*format.BlockSet, error) {
 	backing := getBlockBuf(0)
 
 	buf := bytes.NewBuffer(backing[:0])
@@ -199,94 +278,424 @@ func (t *Txn) writeAsBlocks(r io.Reader) (*format.BlockSet, error) {
 
 	var total int64
 
-	fh, err := blake2b.New256(nil)
+	p, err := t.newBlockPipeline(ctx, path, hint)
 	if err != nil {
 		return nil, err
 	}
 
-	c := rabin.NewChunker(table, io.TeeReader(r, buf), MinBlock, AverageBlock, MaxBlock)
+	c := t.f.newChunker(io.TeeReader(r, buf))
 
-	var (
-		blocks  []*format.Block
-		updates []*format.BlockInfo
-	)
-
-	for i := 0; ; i++ {
+	for {
 		len, err := c.Next()
 		if err != nil {
 			if err == io.EOF {
 				break
 			}
 
+			p.abort()
 			return nil, err
 		}
 
 		total += int64(len)
 
-		h, err := blake2b.New256(nil)
+		err = p.add(buf.Next(len))
 		if err != nil {
+			p.abort()
 			return nil, err
 		}
+	}
+
+	err = p.finish()
+	if err != nil {
+		p.abort()
+		return nil, err
+	}
+
+	t.indexBlocks(p.updates)
+
+	t.added = append(t.added, p.updates...)
+	t.refd = append(t.refd, p.refs...)
+
+	fhSum := p.fh.Sum(nil)
+
+	set := &format.BlockSet{
+		Blocks:   p.blocks,
+		Sum:      fhSum[:],
+		ByteSize: total,
+	}
+
+	return set, nil
+}
+
+// blockPipeline hashes, transforms and stores the chunks of one file
+// for writeAsBlocks. With WithWriteWorkers, chunks are hashed and written
+// by a pool of goroutines, but are deduplicated and added to the BlockSet
+// in order by the goroutine calling add, so the result is the same as
+// writing them one at a time.
+type blockPipeline struct {
+	t    *Txn
+	ctx  context.Context
+	path string
+	hint *compressHint
+
+	workers int
+
+	// sem is held by every goroutine hashing or writing a chunk.
+	sem chan struct{}
+	wg  sync.WaitGroup
+
+	// queue holds the chunks being hashed, in order.
+	queue []*pendingBlock
+
+	mu  sync.Mutex
+	err error
+
+	fh      hash.Hash
+	blocks  []*format.Block
+	updates []*format.BlockInfo
+
+	// refs are the existing blocks whose references were increased.
+	refs []*format.BlockInfo
+
+	// progress is reported as chunks are placed.
+	progress Progress
+}
+
+// pendingBlock is a chunk being hashed. data is a copy of the chunk in
+// backing, since the chunker reuses its buffer.
+type pendingBlock struct {
+	data    []byte
+	backing []byte
+	sum     chan []byte
+}
+
+func (t *Txn) newBlockPipeline(ctx context.Context, path string, hint *compressHint) (*blockPipeline, error) {
+	fh, err := blake2b.New256(nil)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &blockPipeline{
+		t:       t,
+		ctx:     ctx,
+		path:    path,
+		hint:    hint,
+		workers: t.f.writeWorkers,
+		fh:      fh,
 
-		block := buf.Next(len)
+		progress: Progress{Op: ProgressWrite, Path: path},
+	}
+
+	if p.workers > 1 {
+		p.sem = make(chan struct{}, p.workers)
+	}
+
+	return p, nil
+}
+
+func blockSum(block []byte) []byte {
+	sum := blake2b.Sum256(block)
+	return sum[:]
+}
+
+// add queues block, the next chunk of the file.
+func (p *blockPipeline) add(block []byte) error {
+	if err := p.ctx.Err(); err != nil {
+		return err
+	}
+
+	if p.sem == nil {
+		return p.place(blockSum(block), block, nil)
+	}
+
+	if err := p.failed(); err != nil {
+		return err
+	}
+
+	backing := getBlockBuf(len(block))
+	copy(backing, block)
+
+	pb := &pendingBlock{
+		data:    backing[:len(block)],
+		backing: backing,
+		sum:     make(chan []byte, 1),
+	}
 
-		_, err = h.Write(block)
+	p.sem <- struct{}{}
+	p.wg.Add(1)
+
+	go func() {
+		defer p.wg.Done()
+
+		pb.sum <- blockSum(pb.data)
+		<-p.sem
+	}()
+
+	p.queue = append(p.queue, pb)
+
+	// Bound how many chunks are held in memory.
+	for len(p.queue) > 2*p.workers {
+		err := p.settle()
 		if err != nil {
-			return nil, err
+			return err
 		}
+	}
 
-		sum := h.Sum(nil)
+	return nil
+}
 
-		fh.Write(sum[:])
+// settle places the oldest chunk being hashed, once it's hashed.
+func (p *blockPipeline) settle() error {
+	pb := p.queue[0]
+	p.queue = p.queue[1:]
 
-		bid := BlockId(sum[:])
+	return p.place(<-pb.sum, pb.data, pb.backing)
+}
+
+// place adds the chunk block with the given sum to the BlockSet, writing
+// it unless the head already has it. backing, if set, is returned to the
+// pool once the chunk isn't needed.
+func (p *blockPipeline) place(sum, block, backing []byte) error {
+	p.fh.Write(sum)
+
+	bid := BlockId(sum)
+
+	p.blocks = append(p.blocks, &format.Block{
+		Id: bid,
+	})
 
-		blocks = append(blocks, &format.Block{
-			Id: bid,
-		})
+	// if this is an existing block, then inc our internal
+	// refs to it.
+	if info, ok := p.t.lookupTOCBlock(bid); ok {
+		info.References++
+		p.refs = append(p.refs, info)
 
-		// if this is an existing block, then inc our internal
-		// refs to it.
-		if info, ok := t.lookupTOCBlock(bid); ok {
-			info.References++
-			continue
+		if backing != nil {
+			putBlockBuf(backing)
 		}
 
-		clen, err := t.writeBlock(bid, block)
+		p.report(block, false)
+
+		return nil
+	}
+
+	info := &format.BlockInfo{
+		Id:         bid,
+		ByteSize:   int64(len(block)),
+		References: 1,
+	}
+
+	// Added before it's written, so later chunks with the same contents
+	// reference it instead of writing it again.
+	p.t.addTOCBlock(info)
+
+	p.updates = append(p.updates, info)
+
+	if p.sem == nil {
+		clen, err := p.t.writeBlock(bid, block, p.hint)
 		if err != nil {
-			return nil, err
+			return err
 		}
 
-		info := &format.BlockInfo{
-			Id:         bid,
-			ByteSize:   int64(len),
-			CompSize:   clen,
-			References: 1,
+		info.CompSize = clen
+		p.report(block, true)
+
+		return nil
+	}
+
+	p.sem <- struct{}{}
+	p.wg.Add(1)
+
+	go func() {
+		defer p.wg.Done()
+
+		clen, err := p.t.writeBlock(bid, block, p.hint)
+		if err != nil {
+			p.fail(err)
+		} else {
+			info.CompSize = clen
+			p.report(block, true)
 		}
 
-		t.addTOCBlock(info)
+		putBlockBuf(backing)
+		<-p.sem
+	}()
+
+	return nil
+}
+
+// report sends a progress event for the chunk block, once it's written
+// or found to be in the head already. Workers report one at a time.
+func (p *blockPipeline) report(block []byte, written bool) {
+	if p.path == "" {
+		return
+	}
 
-		updates = append(updates, info)
+	p.mu.Lock()
+	defer p.mu.Unlock()
 
+	if written {
+		p.progress.Written++
+	} else {
+		p.progress.Deduped++
 	}
 
+	p.progress.Bytes += int64(len(block))
+	p.progress.Blocks++
+
+	p.t.f.report(p.progress)
+}
+
+func (p *blockPipeline) fail(err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.err == nil {
+		p.err = err
+	}
+}
+
+func (p *blockPipeline) failed() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.err
+}
+
+// finish places the chunks left in the queue and waits for every write.
+func (p *blockPipeline) finish() error {
+	for len(p.queue) > 0 {
+		if err := p.failed(); err != nil {
+			return err
+		}
+
+		err := p.settle()
+		if err != nil {
+			return err
+		}
+	}
+
+	p.wg.Wait()
+
+	return p.failed()
+}
+
+// abort waits for the goroutines still running, then undoes the changes
+// made to the head's block list, so none of the file's blocks are
+// referenced, including ones that may not have been written. The blocks
+// that were written are indexed, for garbage collection to remove.
+func (p *blockPipeline) abort() {
+	p.wg.Wait()
+
+	for _, pb := range p.queue {
+		putBlockBuf(pb.backing)
+	}
+
+	p.queue = nil
+
+	for _, info := range p.refs {
+		info.References--
+	}
+
+	p.t.dropTOCBlocks(p.updates)
+
+	var written []*format.BlockInfo
+
+	for _, info := range p.updates {
+		if info.CompSize > 0 {
+			written = append(written, info)
+		}
+	}
+
+	p.t.indexBlocks(written)
+
+	p.refs = nil
+	p.updates = nil
+}
+
+// indexBlocks adds infos to the repository's block index.
+func (t *Txn) indexBlocks(infos []*format.BlockInfo) {
 	t.f.blockslock.Lock()
-	t.blocks.Blocks = append(t.blocks.Blocks, updates...)
-	t.f.blockslock.Unlock()
+	defer t.f.blockslock.Unlock()
 
-	fhSum := fh.Sum(nil)
+	t.blocks.Blocks = append(t.blocks.Blocks, infos...)
+	for _, info := range infos {
+		t.blocksBloom.Add(info.Id)
+	}
 
-	set := &format.BlockSet{
-		Blocks:   blocks,
-		Sum:      fhSum[:],
-		ByteSize: total,
+	if t.blocksBloom.full() {
+		t.blocksBloom = buildBloomFilter(t.blocks.Blocks, t.f.bloomFP)
+		t.f.blocksBloom = t.blocksBloom
 	}
+}
 
-	return set, nil
+// dropTOCBlocks removes infos from the head's block list.
+func (t *Txn) dropTOCBlocks(infos []*format.BlockInfo) {
+	if len(infos) == 0 {
+		return
+	}
+
+	drop := make(map[*format.BlockInfo]bool, len(infos))
+	for _, info := range infos {
+		drop[info] = true
+	}
+
+	kept := t.tocBlocks.Blocks[:0]
+
+	for _, info := range t.tocBlocks.Blocks {
+		if !drop[info] {
+			kept = append(kept, info)
+		}
+	}
+
+	t.tocBlocks.Blocks = kept
 }
 
-func (t *Txn) writeFile(path string, r io.Reader, ent *format.Entry) (int64, error) {
-	set, err := t.writeAsBlocks(r)
+// rollback undoes the changes made since the head was last written and
+// ends the transaction. Blocks that were written are indexed in
+// blocks.idx, so garbage collection removes them even if nothing is
+// committed again.
+func (t *Txn) rollback() error {
+	if !t.write {
+		return nil
+	}
+
+	defer t.f.txnlock.Unlock()
+
+	for _, info := range t.refd {
+		info.References--
+	}
+
+	t.dropTOCBlocks(t.added)
+
+	t.added = nil
+	t.refd = nil
+
+	t.updates = &format.TOC{
+		Paths: make(map[string]*format.Entry),
+	}
+
+	t.removal = nil
+
+	err := t.blockAccess.flush()
+	if err != nil {
+		return err
+	}
+
+	return t.flushBlockTOC()
+}
+
+func (t *Txn) writeFile(ctx context.Context, path string, r io.Reader, ent *format.Entry) (int64, error) {
+	if !t.write {
+		return 0, ErrReadOnly
+	}
+
+	prev, _, err := t.entryFor(path)
+	if err != nil {
+		return 0, err
+	}
+
+	set, err := t.writeAsBlocks(ctx, path, r, t.newCompressHint(path, prev))
 	if err != nil {
 		return 0, err
 	}
@@ -302,6 +711,11 @@ func (t *Txn) writeFile(path string, r io.Reader, ent *format.Entry) (int64, err
 }
 
 func (t *Txn) lookupTOCBlock(bid BlockId) (*format.BlockInfo, bool) {
+	// Most new chunks are rejected here without scanning the block list.
+	if !t.tocBloom.MayContain(bid) {
+		return nil, false
+	}
+
 	for _, info := range t.tocBlocks.Blocks {
 		if bytes.Equal(info.Id, bid) {
 			return info, true
@@ -313,6 +727,26 @@ func (t *Txn) lookupTOCBlock(bid BlockId) (*format.BlockInfo, bool) {
 
 func (t *Txn) addTOCBlock(info *format.BlockInfo) {
 	t.tocBlocks.Blocks = append(t.tocBlocks.Blocks, info)
+	t.tocBloom.Add(info.Id)
+
+	if t.tocBloom.full() {
+		t.tocBloom = buildBloomFilter(t.tocBlocks.Blocks, t.f.bloomFP)
+		t.f.tocBloom = t.tocBloom
+	}
+}
+
+// releaseTOCSet drops the head's references to the blocks of set,
+// which it no longer uses.
+func (t *Txn) releaseTOCSet(set *format.BlockSet) {
+	for _, blk := range set.Blocks {
+		if info, ok := t.tocBlocks.FindBlock(blk.Id); ok {
+			info.References--
+
+			if info.References == 0 {
+				t.tocBlocks.RemoveBlock(BlockId(blk.Id))
+			}
+		}
+	}
 }
 
 func (t *Txn) flushTOC() error {
@@ -320,59 +754,36 @@ func (t *Txn) flushTOC() error {
 	defer t.f.toclock.Unlock()
 
 	for path, entry := range t.updates.Paths {
-		t.toc.Paths[path] = entry
+		err := t.toc.Set(path, entry)
+		if err != nil {
+			return err
+		}
 	}
 
 	for _, path := range t.removal {
-		if entry, ok := t.toc.Paths[path]; ok {
-			for _, blk := range entry.Blocks.Blocks {
-				if info, ok := t.tocBlocks.FindBlock(blk.Id); ok {
-					info.References--
-
-					if info.References == 0 {
-						t.tocBlocks.RemoveBlock(BlockId(blk.Id))
-					}
-				}
-			}
+		entry, ok, err := t.toc.Delete(path)
+		if err != nil {
+			return err
 		}
 
-		delete(t.toc.Paths, path)
+		if ok && entry.Blocks != nil {
+			t.releaseTOCSet(entry.Blocks)
+		}
 	}
 
 	t.updates = &format.TOC{
 		Paths: make(map[string]*format.Entry),
 	}
 
-	buf := getBlockBuf(t.toc.Size())
-
-	tlen, err := t.toc.MarshalTo(buf)
-	if err != nil {
-		return err
-	}
-
-	set, err := t.writeAsBlocks(bytes.NewReader(buf[:tlen]))
+	set, err := t.toc.Flush(t)
 	if err != nil {
 		return err
 	}
 
-	if t.tocSet != nil {
-		for _, blk := range t.tocSet.Blocks {
-			if info, ok := t.tocBlocks.FindBlock(blk.Id); ok {
-				info.References--
-
-				if info.References == 0 {
-					t.tocBlocks.RemoveBlock(BlockId(blk.Id))
-				}
-			}
-		}
-	}
-
 	t.f.tocSet = set
 	t.tocSet = set
 
-	putBlockBuf(buf)
-
-	buf = getBlockBuf(set.Size())
+	buf := getBlockBuf(set.Size())
 	defer putBlockBuf(buf)
 
 	slen, err := set.MarshalTo(buf)
@@ -380,6 +791,13 @@ func (t *Txn) flushTOC() error {
 		return err
 	}
 
+	// Make sure every block the head refers to is reachable before the
+	// head itself is written.
+	err = t.blockAccess.flush()
+	if err != nil {
+		return err
+	}
+
 	of, err := os.Create(filepath.Join(t.root, t.tocPath))
 	if err != nil {
 		return err
@@ -387,7 +805,9 @@ func (t *Txn) flushTOC() error {
 
 	defer of.Close()
 
-	buf, err = t.blockAccess.writeTransform(buf[:slen])
+	ad := t.blockAccess.headAD(&t.tocHeader)
+
+	buf, err = t.blockAccess.writeTransform(buf[:slen], ad)
 	if err != nil {
 		return err
 	}
@@ -397,7 +817,12 @@ func (t *Txn) flushTOC() error {
 	t.tocHeader.Sum = tocSum[:]
 	t.tocHeader.TocSize = int64(len(buf))
 
-	// Now marshal the blockTOC
+	// Now marshal the blockTOC, rebuilding the bloom filter so that it
+	// is sized for the current block count and forgets removed blocks.
+
+	t.tocBloom = buildBloomFilter(t.tocBlocks.Blocks, t.f.bloomFP)
+	t.f.tocBloom = t.tocBloom
+	t.tocBlocks.BloomFilter = t.tocBloom.Bytes()
 
 	bbuf := getBlockBuf(t.tocBlocks.Size())
 
@@ -406,68 +831,65 @@ func (t *Txn) flushTOC() error {
 		return err
 	}
 
-	bdata, err := t.blockAccess.writeTransform(bbuf[:n])
+	bdata, err := t.blockAccess.writeTransform(bbuf[:n], ad)
 	if err != nil {
 		return err
 	}
 
 	t.tocHeader.BlocksSize = int64(len(bdata))
 
-	hdata := make([]byte, 256)
-
-	hlen, err := t.tocHeader.MarshalTo(hdata[1:])
-	if hlen > 247 {
-		return fmt.Errorf("header too large! %d > 247", hlen)
+	err = t.f.addHeadSignature(&t.tocHeader, set.Sum, bdata)
+	if err != nil {
+		return err
 	}
 
-	hdata[0] = byte(hlen)
-
-	_, err = of.Write(hdata)
+	err = t.blockAccess.signHead(&t.tocHeader, buf, bdata)
 	if err != nil {
 		return err
 	}
 
-	_, err = of.Write(buf)
+	hdata, err := marshalHeadHeader(&t.tocHeader)
 	if err != nil {
 		return err
 	}
 
-	_, err = of.Write(bdata)
-	return err
-}
-
-func (t *Txn) flushBlockTOC() error {
-	buf := buffers.Get().([]byte)
-
-	tocSize := t.blocks.Size()
-
-	if len(buf) < tocSize {
-		buf = make([]byte, tocSize+64)
+	_, err = of.Write(hdata)
+	if err != nil {
+		return err
 	}
 
-	defer buffers.Put(buf)
-
-	len, err := t.blocks.MarshalTo(buf)
+	_, err = of.Write(buf)
 	if err != nil {
 		return err
 	}
 
-	of, err := os.Create(filepath.Join(t.root, "blocks.idx"))
+	_, err = of.Write(bdata)
 	if err != nil {
 		return err
 	}
 
-	defer of.Close()
+	// The head has the changes now.
+	t.added = nil
+	t.refd = nil
 
-	_, err = of.Write(buf[:len])
-	return err
+	return nil
 }
 
-func (t *Txn) gcBlocks() error {
-	var (
-		fanChecks = map[string]struct{}{}
-		foundRefs = map[string]int64{}
-	)
+func (t *Txn) flushBlockTOC() error {
+	t.f.blockslock.Lock()
+	t.blocksBloom = buildBloomFilter(t.blocks.Blocks, t.f.bloomFP)
+	t.f.blocksBloom = t.blocksBloom
+	t.blocks.BloomFilter = t.blocksBloom.Bytes()
+	t.f.blockslock.Unlock()
+
+	return t.f.writeBlockIndex(&t.blockAccess, t.blocks)
+}
+
+// gcBlocks removes the blocks that no head references. Once ctx is done
+// it stops between blocks and returns ctx's error, keeping the blocks it
+// hasn't looked at.
+func (t *Txn) gcBlocks(ctx context.Context) error {
+	foundRefs := map[string]int64{}
 
 	heads, err := ioutil.ReadDir(filepath.Join(t.root, "heads"))
 	if err != nil {
@@ -475,42 +897,50 @@ func (t *Txn) gcBlocks() error {
 	}
 
 	for _, head := range heads {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
 		path := filepath.Join(t.root, "heads", head.Name())
-		_, _, blocks, err := t.f.unmarshalTOC(path)
+		hf, err := t.f.unmarshalTOC(path, true)
 		if err != nil {
 			return err
 		}
 
-		for _, blk := range blocks.Blocks {
+		for _, blk := range hf.blocks.Blocks {
 			foundRefs[BlockId(blk.Id).String()]++
 		}
 	}
 
-	for _, blk := range t.blocks.Blocks {
-		id := hex.EncodeToString(blk.Id)
+	var kept []*format.BlockInfo
 
-		if foundRefs[id] == 0 {
-			fanPath := filepath.Join(t.root, "blocks", id[:6])
-			fanChecks[fanPath] = struct{}{}
+	progress := Progress{Op: ProgressGC, TotalBlocks: len(t.blocks.Blocks)}
 
-			err := os.Remove(filepath.Join(fanPath, id))
-			if err != nil {
-				return err
-			}
+	for i, blk := range t.blocks.Blocks {
+		if err = ctx.Err(); err != nil {
+			kept = append(kept, t.blocks.Blocks[i:]...)
+			break
 		}
-	}
 
-	for path, _ := range fanChecks {
-		f, err := os.Open(path)
-		if err == nil {
-			if _, err = f.Readdir(1); err == io.EOF {
-				err = os.Remove(path)
-				if err != nil {
-					return err
-				}
+		if foundRefs[BlockId(blk.Id).String()] > 0 {
+			kept = append(kept, blk)
+		} else {
+			rerr := t.blockAccess.removeBlock(blk.Id)
+			if rerr == nil {
+				progress.Removed++
+				progress.Bytes += blk.CompSize
+			} else if !os.IsNotExist(rerr) {
+				return rerr
 			}
 		}
+
+		progress.Blocks++
+		t.f.report(progress)
 	}
 
-	return nil
+	t.f.blockslock.Lock()
+	t.blocks.Blocks = kept
+	t.f.blockslock.Unlock()
+
+	return err
 }
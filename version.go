@@ -0,0 +1,341 @@
+package yfs
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/evanphx/yfs/format"
+)
+
+// FormatVersion is the newest on-disk format this package reads and
+// writes, recorded in the repository config and every head. Version 0
+// is every repository from before versions were recorded, whatever
+// features it was created with. Version 1 repositories use tagged block
+// frames, bind block ids and head headers to their ciphertext, and
+// encrypt blocks.idx. Version 2 heads frame their header with a varint
+// length rather than in a fixed 256 bytes. Version 3 heads store their
+// TOC as a tree of nodes.
+const FormatVersion = 3
+
+var (
+	ErrUnsupportedVersion     = errors.New("repository format version is newer than this version of yfs supports")
+	ErrUnsupportedHeadVersion = errors.New("head format version is newer than this version of yfs supports")
+)
+
+// migrations upgrade a repository from the format version they're keyed
+// by to the next one.
+var migrations = map[uint32]func(f *FS) error{
+	0: (*FS).upgradeFrom0,
+	1: (*FS).upgradeFrom1,
+	2: (*FS).upgradeFrom2,
+}
+
+// Upgrade migrates the repository in place to FormatVersion. It must be
+// opened with the options it was written with, including its encryption
+// key. An interrupted upgrade is finished by calling Upgrade again. The
+// repository stays readable until then.
+func (f *FS) Upgrade() error {
+	if f.readOnly {
+		return ErrReadOnly
+	}
+
+	f.txnlock.Lock()
+	defer f.txnlock.Unlock()
+
+	for f.config.Version < FormatVersion {
+		migrate, ok := migrations[f.config.Version]
+		if !ok {
+			return fmt.Errorf("no upgrade from format version %d", f.config.Version)
+		}
+
+		err := migrate(f)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// upgradeFrom0 rewrites every block, head and the block index with
+// tagged block frames and authenticated encryption. Version 0 block
+// indexes can list blocks that were never stored; those entries are
+// dropped, since a write would otherwise dedup against them.
+func (f *FS) upgradeFrom0() error {
+	heads, err := readDirNames(filepath.Join(f.root, "heads"))
+	if err != nil {
+		return err
+	}
+
+	reachable, err := f.reachableBlocks(heads)
+	if err != nil {
+		return err
+	}
+
+	if !f.config.Upgrading {
+		legacy := f.blockAccess
+
+		f.config.BlockFrame = blockFrameTagged
+		f.config.Authenticated = true
+		f.config.Upgrading = true
+
+		err = f.setupCompression()
+		if err != nil {
+			return err
+		}
+
+		err = f.setupEncryption()
+		if err != nil {
+			return err
+		}
+
+		f.blockAccess.legacy = &legacy
+		f.tocHeader.Version = 1
+
+		// Recorded before any block is rewritten, so that NewFS reads
+		// both frames if the upgrade is interrupted.
+		err = f.writeConfig()
+		if err != nil {
+			return err
+		}
+	}
+
+	old := f.blockAccess.legacy
+	cur := &f.blockAccess
+
+	f.blockslock.RLock()
+	ids := make([]BlockId, 0, len(f.blocks.Blocks)+len(reachable))
+	for _, blk := range f.blocks.Blocks {
+		ids = append(ids, blk.Id)
+	}
+	f.blockslock.RUnlock()
+
+	for id := range reachable {
+		ids = append(ids, BlockId(id))
+	}
+
+	missing := make(map[string]bool)
+
+	for _, id := range ids {
+		data, err := old.readBlock(id)
+		if err != nil {
+			// Rewritten before an upgrade was interrupted.
+			if _, err := cur.readBlock(id); err == nil {
+				continue
+			}
+
+			if os.IsNotExist(err) && !reachable[string(id)] {
+				missing[string(id)] = true
+				continue
+			}
+
+			return err
+		}
+
+		_, err = cur.writeBlock(id, data, nil)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = cur.flush()
+	if err != nil {
+		return err
+	}
+
+	for _, head := range heads {
+		err = f.rewriteHead(filepath.Join(f.root, "heads", head), old, cur,
+			func(h *format.TOCHeader) bool {
+				if h.Version >= 1 {
+					return false
+				}
+
+				h.Version = 1
+				h.Compressed = f.tocHeader.Compressed
+				return true
+			})
+		if err != nil {
+			return err
+		}
+	}
+
+	f.blockslock.Lock()
+
+	for id := range missing {
+		f.blocks.RemoveBlock(BlockId(id))
+	}
+
+	f.blocksBloom = buildBloomFilter(f.blocks.Blocks, f.bloomFP)
+	f.blocks.BloomFilter = f.blocksBloom.Bytes()
+
+	err = f.writeBlockIndex(cur, f.blocks)
+
+	f.blockslock.Unlock()
+
+	if err != nil {
+		return err
+	}
+
+	f.config.Version = 1
+	f.config.Upgrading = false
+	f.blockAccess.legacy = nil
+
+	err = f.writeConfig()
+	if err != nil {
+		return err
+	}
+
+	if ps, ok := cur.store.(*packStore); ok {
+		return ps.repack()
+	}
+
+	return nil
+}
+
+// legacyBlockAccess returns the block access of the repository before
+// upgradeFrom0 switched its config to tagged frames and authenticated
+// encryption, for reading what it hasn't rewritten yet.
+func (f *FS) legacyBlockAccess() (*blockAccess, error) {
+	cur := f.blockAccess
+	frame, auth := f.config.BlockFrame, f.config.Authenticated
+	compressed := f.tocHeader.Compressed
+
+	f.config.BlockFrame = blockFrameLegacy
+	f.config.Authenticated = false
+
+	err := f.setupCompression()
+	if err == nil {
+		err = f.setupEncryption()
+	}
+
+	legacy := f.blockAccess
+
+	f.blockAccess = cur
+	f.config.BlockFrame, f.config.Authenticated = frame, auth
+	f.tocHeader.Compressed = compressed
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &legacy, nil
+}
+
+// upgradeFrom1 rewrites every head with a varint framed header.
+func (f *FS) upgradeFrom1() error {
+	heads, err := readDirNames(filepath.Join(f.root, "heads"))
+	if err != nil {
+		return err
+	}
+
+	for _, head := range heads {
+		err = f.rewriteHead(filepath.Join(f.root, "heads", head), &f.blockAccess, &f.blockAccess,
+			func(h *format.TOCHeader) bool {
+				if h.Version >= 2 {
+					return false
+				}
+
+				h.Version = 2
+				return true
+			})
+		if err != nil {
+			return err
+		}
+	}
+
+	f.config.Version = 2
+	f.tocHeader.Version = 2
+
+	return f.writeConfig()
+}
+
+// upgradeFrom2 marks every head as version 3. A version 2 TOC is already
+// a tree with a single leaf, which is split as it's next written.
+func (f *FS) upgradeFrom2() error {
+	heads, err := readDirNames(filepath.Join(f.root, "heads"))
+	if err != nil {
+		return err
+	}
+
+	for _, head := range heads {
+		err = f.rewriteHead(filepath.Join(f.root, "heads", head), &f.blockAccess, &f.blockAccess,
+			func(h *format.TOCHeader) bool {
+				if h.Version >= 3 {
+					return false
+				}
+
+				h.Version = 3
+				return true
+			})
+		if err != nil {
+			return err
+		}
+	}
+
+	f.config.Version = 3
+	f.tocHeader.Version = 3
+
+	err = f.writeConfig()
+	if err != nil {
+		return err
+	}
+
+	f.toc.setSharded(true)
+
+	return nil
+}
+
+// reachableBlocks returns the ids of every block the heads refer to.
+func (f *FS) reachableBlocks(heads []string) (map[string]bool, error) {
+	reachable := make(map[string]bool)
+
+	for _, head := range heads {
+		path := filepath.Join(f.root, "heads", head)
+
+		// Heads written while an interrupted upgrade was pending can
+		// reference blocks that haven't been rewritten, so every head
+		// is walked.
+		hf, err := f.unmarshalTOC(path, true)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, blk := range hf.blocks.Blocks {
+			reachable[string(blk.Id)] = true
+		}
+
+		for _, blk := range hf.set.Blocks {
+			reachable[string(blk.Id)] = true
+		}
+
+		err = hf.toc.Walk(func(path string, entry *format.Entry) error {
+			if entry.Blocks == nil {
+				return nil
+			}
+
+			for _, blk := range entry.Blocks.Blocks {
+				reachable[string(blk.Id)] = true
+			}
+
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return reachable, nil
+}
+
+func readDirNames(dir string) ([]string, error) {
+	d, err := os.Open(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	defer d.Close()
+
+	return d.Readdirnames(-1)
+}